@@ -2,8 +2,11 @@ package internal
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 	"time"
 )
@@ -13,17 +16,37 @@ type EventType string
 const (
 	EventTypePush      EventType = "push"
 	EventTypeTag       EventType = "tag"
+	EventTypeTagDelete EventType = "tag-delete"
 	EventTypePromotion EventType = "promotion"
 )
 
 type AuditEvent struct {
-	EventType EventType   `json:"event_type"`
-	Timestamp time.Time   `json:"timestamp"`
-	User      string      `json:"user"`
-	AppName   string      `json:"app_name"`
-	GitHash   string      `json:"git_hash"`
-	GitTime   string      `json:"git_time"`
-	Details   interface{} `json:"details"`
+	EventType     EventType   `json:"event_type"`
+	Timestamp     time.Time   `json:"timestamp"`
+	User          string      `json:"user"`
+	AppName       string      `json:"app_name"`
+	GitHash       string      `json:"git_hash"`
+	GitTime       string      `json:"git_time"`
+	Source        string      `json:"source,omitempty"`         // Tool that wrote the event (e.g. "s3dock")
+	ClientVersion string      `json:"client_version,omitempty"` // Tool version and commit
+	PrevHash      string      `json:"prev_hash,omitempty"`      // SHA-256 of the previous event for this app (audit.chain)
+	TraceID       string      `json:"trace_id,omitempty"`       // Correlation ID for the invocation (--trace-id)
+	Details       interface{} `json:"details"`
+}
+
+// Source identity stamped onto every audit event. Multiple tools may write to
+// the same bucket, and behavior changes across versions show up in the log -
+// main sets these once at startup from the binary's build info.
+var (
+	auditSource        = "s3dock"
+	auditClientVersion = "dev"
+)
+
+// SetAuditClientInfo records the source name and client version written to
+// audit events created by this process.
+func SetAuditClientInfo(source, clientVersion string) {
+	auditSource = source
+	auditClientVersion = clientVersion
 }
 
 type PushEventDetails struct {
@@ -47,6 +70,9 @@ type PromotionEventDetails struct {
 	SourceType     string `json:"source_type"` // "image" or "tag"
 	PointerPath    string `json:"pointer_path"`
 	PreviousTarget string `json:"previous_target,omitempty"`
+	RequestedBy    string `json:"requested_by,omitempty"` // Requester of an approval-protected promotion
+	ApprovedBy     string `json:"approved_by,omitempty"`  // Second actor who approved it
+	NoOp           bool   `json:"no_op,omitempty"`        // Promotion was skipped because the target was unchanged
 }
 
 func (a *AuditEvent) ToJSON() ([]byte, error) {
@@ -83,13 +109,16 @@ func CreatePushEvent(appName, gitHash, gitTime, imageRef, s3Path, checksum strin
 	}
 
 	return &AuditEvent{
-		EventType: EventTypePush,
-		Timestamp: time.Now(),
-		User:      user,
-		AppName:   appName,
-		GitHash:   gitHash,
-		GitTime:   gitTime,
-		Details:   details,
+		EventType:     EventTypePush,
+		Timestamp:     time.Now(),
+		User:          user,
+		AppName:       appName,
+		GitHash:       gitHash,
+		GitTime:       gitTime,
+		Source:        auditSource,
+		ClientVersion: auditClientVersion,
+		TraceID:       traceID,
+		Details:       details,
 	}, nil
 }
 
@@ -106,13 +135,42 @@ func CreateTagEvent(appName, gitHash, gitTime, imageRef, version, tagPath string
 	}
 
 	return &AuditEvent{
-		EventType: EventTypeTag,
-		Timestamp: time.Now(),
-		User:      user,
-		AppName:   appName,
-		GitHash:   gitHash,
-		GitTime:   gitTime,
-		Details:   details,
+		EventType:     EventTypeTag,
+		Timestamp:     time.Now(),
+		User:          user,
+		AppName:       appName,
+		GitHash:       gitHash,
+		GitTime:       gitTime,
+		Source:        auditSource,
+		ClientVersion: auditClientVersion,
+		TraceID:       traceID,
+		Details:       details,
+	}, nil
+}
+
+func CreateTagDeleteEvent(appName, gitHash, gitTime, imageRef, version, tagPath string) (*AuditEvent, error) {
+	user, err := getCurrentUser()
+	if err != nil {
+		user = "unknown"
+	}
+
+	details := TagEventDetails{
+		ImageReference: imageRef,
+		Version:        version,
+		TagPath:        tagPath,
+	}
+
+	return &AuditEvent{
+		EventType:     EventTypeTagDelete,
+		Timestamp:     time.Now(),
+		User:          user,
+		AppName:       appName,
+		GitHash:       gitHash,
+		GitTime:       gitTime,
+		Source:        auditSource,
+		ClientVersion: auditClientVersion,
+		TraceID:       traceID,
+		Details:       details,
 	}, nil
 }
 
@@ -131,33 +189,97 @@ func CreatePromotionEvent(appName, gitHash, gitTime, environment, source, source
 	}
 
 	return &AuditEvent{
-		EventType: EventTypePromotion,
-		Timestamp: time.Now(),
-		User:      user,
-		AppName:   appName,
-		GitHash:   gitHash,
-		GitTime:   gitTime,
-		Details:   details,
+		EventType:     EventTypePromotion,
+		Timestamp:     time.Now(),
+		User:          user,
+		AppName:       appName,
+		GitHash:       gitHash,
+		GitTime:       gitTime,
+		Source:        auditSource,
+		ClientVersion: auditClientVersion,
+		TraceID:       traceID,
+		Details:       details,
 	}, nil
 }
 
+// CreateNoOpPromotionEvent records a promotion attempt that changed nothing
+// because the environment already pointed at the target. Only emitted with
+// --no-skip-identical, so heartbeat re-promotions still show up in the trail.
+func CreateNoOpPromotionEvent(appName, gitHash, gitTime, environment, source, sourceType, pointerPath, previousTarget string) (*AuditEvent, error) {
+	event, err := CreatePromotionEvent(appName, gitHash, gitTime, environment, source, sourceType, pointerPath, previousTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	details := event.Details.(PromotionEventDetails)
+	details.NoOp = true
+	event.Details = details
+	return event, nil
+}
+
 type AuditLogger interface {
 	LogEvent(ctx context.Context, event *AuditEvent) error
 }
 
+// NopAuditLogger discards audit events (--no-audit). Useful for test pushes
+// that should not pollute the audit trail.
+type NopAuditLogger struct{}
+
+func (NopAuditLogger) LogEvent(ctx context.Context, event *AuditEvent) error {
+	return nil
+}
+
 type S3AuditLogger struct {
 	s3     S3Client
 	bucket string
+	chain  bool
 }
 
 func NewS3AuditLogger(s3Client S3Client, bucket string) *S3AuditLogger {
 	return &S3AuditLogger{
 		s3:     s3Client,
 		bucket: bucket,
+		chain:  os.Getenv("S3DOCK_AUDIT_CHAIN") != "",
+	}
+}
+
+// AuditEventHash returns the hash of a serialized audit event, used to link
+// events into a tamper-evident chain
+func AuditEventHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%x", sum)
+}
+
+// latestEventHash returns the hash of the most recent audit event for an app,
+// or an empty string when no events exist yet
+func (a *S3AuditLogger) latestEventHash(ctx context.Context, appName string) (string, error) {
+	prefix := fmt.Sprintf("audit/%s/", appName)
+	keys, err := a.s3.List(ctx, a.bucket, prefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to list audit events: %w", err)
+	}
+	if len(keys) == 0 {
+		return "", nil
 	}
+
+	// Audit keys sort chronologically (yearMonth, then timestamp)
+	sort.Strings(keys)
+	data, err := a.s3.Download(ctx, a.bucket, keys[len(keys)-1])
+	if err != nil {
+		return "", fmt.Errorf("failed to download latest audit event: %w", err)
+	}
+	return AuditEventHash(data), nil
 }
 
 func (a *S3AuditLogger) LogEvent(ctx context.Context, event *AuditEvent) error {
+	if a.chain {
+		prevHash, err := a.latestEventHash(ctx, event.AppName)
+		if err != nil {
+			return fmt.Errorf("failed to read previous audit event hash: %w", err)
+		}
+		event.PrevHash = prevHash
+	}
+
 	auditKey := GenerateAuditKey(event.AppName, event.Timestamp, event.EventType, event.GitHash)
 
 	eventJSON, err := event.ToJSON()
@@ -157,3 +157,22 @@ func TestS3AuditLogger_LogEvent(t *testing.T) {
 	assert.NoError(t, err)
 	mockS3.AssertExpectations(t)
 }
+
+func TestAuditEvent_ClientInfo(t *testing.T) {
+	defer SetAuditClientInfo("s3dock", "dev")
+	SetAuditClientInfo("s3dock", "1.2.3 (abc1234)")
+
+	event, err := CreatePushEvent("myapp", "abc1234", "20250721-1430", "myapp:latest", "images/myapp/x.tar.gz", "sum", 1, false, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "s3dock", event.Source)
+	assert.Equal(t, "1.2.3 (abc1234)", event.ClientVersion)
+
+	// Client info survives the JSON round trip used by the audit log
+	data, err := event.ToJSON()
+	assert.NoError(t, err)
+	parsed, err := AuditEventFromJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3dock", parsed.Source)
+	assert.Equal(t, "1.2.3 (abc1234)", parsed.ClientVersion)
+}
@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Backend abstracts where pushed images and environment pointers live. The
+// default S3 backend stores image tarballs and JSON pointers in a bucket; the
+// registry backend (--local-registry) meets teams that already run a Docker
+// registry where they are, moving images with docker push/pull and modeling
+// pointers as registry tags. The first cut covers push, pull, and current;
+// tagging and promotion stay S3-only for now.
+type Backend interface {
+	// PushImage publishes a locally built image to the backend
+	PushImage(ctx context.Context, imageRef string) error
+	// PullImage fetches an image by environment or version tag and returns
+	// the local reference it was imported as
+	PullImage(ctx context.Context, appName, target string) (string, error)
+	// CurrentImage resolves which image an environment currently points to
+	CurrentImage(ctx context.Context, appName, environment string) (string, error)
+}
+
+// S3Backend is the default backend, delegating to the existing S3-based
+// pusher, puller, and current services with their default options.
+type S3Backend struct {
+	docker DockerClient
+	s3     S3Client
+	git    GitClient
+	bucket string
+}
+
+func NewS3Backend(docker DockerClient, s3 S3Client, git GitClient, bucket string) *S3Backend {
+	return &S3Backend{
+		docker: docker,
+		s3:     s3,
+		git:    git,
+		bucket: bucket,
+	}
+}
+
+func (b *S3Backend) PushImage(ctx context.Context, imageRef string) error {
+	_, err := NewImagePusher(b.docker, b.s3, b.git, b.bucket).Push(ctx, imageRef)
+	return err
+}
+
+func (b *S3Backend) PullImage(ctx context.Context, appName, target string) (string, error) {
+	puller := NewImagePuller(b.docker, b.s3, b.bucket)
+
+	var result *PullResult
+	var err error
+	if isVersionTag(target) {
+		result, err = puller.PullFromTag(ctx, appName, target)
+	} else {
+		result, err = puller.Pull(ctx, appName, target)
+	}
+	if err != nil {
+		return "", err
+	}
+	return result.ImageRef, nil
+}
+
+func (b *S3Backend) CurrentImage(ctx context.Context, appName, environment string) (string, error) {
+	return NewCurrentService(b.s3, b.bucket).GetCurrentImage(ctx, appName, environment)
+}
+
+// RegistryBackend stores images in a Docker registry instead of S3. Images
+// are pushed under their canonical git-based tag (<registry>/<app>:<gittime>-
+// <githash>) and environment pointers are plain registry tags
+// (<registry>/<app>:<environment>), so the registry's own tag listing shows
+// the same state s3dock would keep in pointers/.
+type RegistryBackend struct {
+	registry string
+	docker   DockerClient
+	run      func(ctx context.Context, args ...string) error // docker CLI runner, replaceable in tests
+}
+
+func NewRegistryBackend(registry string, docker DockerClient) *RegistryBackend {
+	return &RegistryBackend{
+		registry: registry,
+		docker:   docker,
+		run:      runDockerCommand,
+	}
+}
+
+// runDockerCommand shells out to the docker CLI; push and pull need the
+// daemon's credential helpers and progress output, like buildx builds do
+func runDockerCommand(ctx context.Context, args ...string) error {
+	LogDebug("Running: docker %s", strings.Join(args, " "))
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// registryRef prefixes a local reference with the registry host
+func (b *RegistryBackend) registryRef(imageRef string) string {
+	return b.registry + "/" + imageRef
+}
+
+func (b *RegistryBackend) PushImage(ctx context.Context, imageRef string) error {
+	if _, _, _, err := ParseImageReference(imageRef); err != nil {
+		return err
+	}
+
+	registryRef := b.registryRef(imageRef)
+	LogInfo("Pushing %s to registry %s", imageRef, b.registry)
+
+	if err := b.docker.TagImage(ctx, imageRef, registryRef); err != nil {
+		return fmt.Errorf("failed to tag %s as %s: %w", imageRef, registryRef, err)
+	}
+	if err := b.run(ctx, "push", registryRef); err != nil {
+		return fmt.Errorf("failed to push %s: %w", registryRef, err)
+	}
+
+	LogInfo("Successfully pushed %s", registryRef)
+	return nil
+}
+
+func (b *RegistryBackend) PullImage(ctx context.Context, appName, target string) (string, error) {
+	registryRef := b.registryRef(appName + ":" + target)
+	LogInfo("Pulling %s from registry %s", registryRef, b.registry)
+
+	if err := b.run(ctx, "pull", registryRef); err != nil {
+		return "", fmt.Errorf("failed to pull %s: %w", registryRef, err)
+	}
+
+	// Re-tag under the bare app name so the local reference matches what the
+	// S3 backend would import
+	localRef := appName + ":" + target
+	if err := b.docker.TagImage(ctx, registryRef, localRef); err != nil {
+		return "", fmt.Errorf("failed to tag %s as %s: %w", registryRef, localRef, err)
+	}
+
+	LogInfo("Successfully pulled %s as %s", registryRef, localRef)
+	return localRef, nil
+}
+
+func (b *RegistryBackend) CurrentImage(ctx context.Context, appName, environment string) (string, error) {
+	envRef := b.registryRef(appName + ":" + environment)
+
+	if err := b.run(ctx, "pull", envRef); err != nil {
+		return "", fmt.Errorf("failed to pull environment tag %s: %w", envRef, err)
+	}
+
+	// The environment tag and the canonical git-based tag point at the same
+	// image, so the canonical reference shows up among the pulled image's tags
+	tags, err := b.docker.ImageTags(ctx, envRef)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect %s: %w", envRef, err)
+	}
+
+	prefix := b.registry + "/"
+	for _, tag := range tags {
+		candidate := strings.TrimPrefix(tag, prefix)
+		if candidate == tag {
+			continue
+		}
+		if _, _, _, err := ParseImageReference(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no canonical image tag found for %s/%s (only the environment tag %s is present locally)", appName, environment, envRef)
+}
+
+// isVersionTag reports whether a pull/current target names a version tag
+// (v1.2.0) rather than an environment (production)
+func isVersionTag(target string) bool {
+	return strings.HasPrefix(target, "v") && len(strings.Split(target, ".")) >= 2
+}
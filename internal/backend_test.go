@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordingRunner captures docker CLI invocations instead of executing them
+func recordingRunner(calls *[][]string) func(ctx context.Context, args ...string) error {
+	return func(ctx context.Context, args ...string) error {
+		*calls = append(*calls, args)
+		return nil
+	}
+}
+
+func TestRegistryBackend_PushImage(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockDocker.On("TagImage", mock.Anything, "myapp:20250721-2118-f7a5a27", "registry.local:5000/myapp:20250721-2118-f7a5a27").Return(nil)
+
+	var calls [][]string
+	backend := NewRegistryBackend("registry.local:5000", mockDocker)
+	backend.run = recordingRunner(&calls)
+
+	err := backend.PushImage(context.Background(), "myapp:20250721-2118-f7a5a27")
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"push", "registry.local:5000/myapp:20250721-2118-f7a5a27"}}, calls)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestRegistryBackend_PushImage_InvalidRef(t *testing.T) {
+	backend := NewRegistryBackend("registry.local:5000", new(MockDockerClient))
+
+	var calls [][]string
+	backend.run = recordingRunner(&calls)
+
+	err := backend.PushImage(context.Background(), "myapp:latest")
+
+	assert.Error(t, err)
+	assert.Empty(t, calls, "invalid references should be rejected before any docker call")
+}
+
+func TestRegistryBackend_PullImage(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockDocker.On("TagImage", mock.Anything, "registry.local:5000/myapp:production", "myapp:production").Return(nil)
+
+	var calls [][]string
+	backend := NewRegistryBackend("registry.local:5000", mockDocker)
+	backend.run = recordingRunner(&calls)
+
+	localRef, err := backend.PullImage(context.Background(), "myapp", "production")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:production", localRef)
+	assert.Equal(t, [][]string{{"pull", "registry.local:5000/myapp:production"}}, calls)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestRegistryBackend_CurrentImage(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockDocker.On("ImageTags", mock.Anything, "registry.local:5000/myapp:production").Return([]string{
+		"registry.local:5000/myapp:production",
+		"registry.local:5000/myapp:20250721-2118-f7a5a27",
+	}, nil)
+
+	var calls [][]string
+	backend := NewRegistryBackend("registry.local:5000", mockDocker)
+	backend.run = recordingRunner(&calls)
+
+	imageRef, err := backend.CurrentImage(context.Background(), "myapp", "production")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:20250721-2118-f7a5a27", imageRef)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestRegistryBackend_CurrentImage_NoCanonicalTag(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockDocker.On("ImageTags", mock.Anything, "registry.local:5000/myapp:production").Return([]string{
+		"registry.local:5000/myapp:production",
+	}, nil)
+
+	var calls [][]string
+	backend := NewRegistryBackend("registry.local:5000", mockDocker)
+	backend.run = recordingRunner(&calls)
+
+	_, err := backend.CurrentImage(context.Background(), "myapp", "production")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no canonical image tag found")
+}
@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/adhocore/jsonc"
+)
+
+// PromotionEntry describes a single promotion in a batch file
+type PromotionEntry struct {
+	App         string `json:"app,omitempty"` // Required for tag sources (e.g. v1.2.0)
+	Source      string `json:"source"`        // Image reference (app:tag) or version tag
+	Environment string `json:"environment"`   // Target environment
+}
+
+// LoadPromotionFile parses a batch promotion file (JSON5, top-level array of
+// entries) and validates that each entry has a source and environment.
+func LoadPromotionFile(path string) ([]PromotionEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read promotion file %s: %w", path, err)
+	}
+
+	j := jsonc.New()
+	var entries []PromotionEntry
+	if err := j.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse promotion file %s: %w", path, err)
+	}
+
+	for i, entry := range entries {
+		if entry.Source == "" {
+			return nil, fmt.Errorf("promotion file entry %d: source is required", i+1)
+		}
+		if entry.Environment == "" {
+			return nil, fmt.Errorf("promotion file entry %d: environment is required", i+1)
+		}
+		if !strings.Contains(entry.Source, ":") && entry.App == "" {
+			return nil, fmt.Errorf("promotion file entry %d: app is required for tag source %s", i+1, entry.Source)
+		}
+	}
+
+	return entries, nil
+}
+
+// PromoteApps promotes the same version tag across a fleet of apps (promote
+// --apps, a release train). By default failures don't stop the train; with
+// stopOnError the remaining apps are left untouched after the first failure.
+// With parallel the promotions run concurrently, each app needing only its
+// own S3 reads and writes.
+func (p *ImagePromoter) PromoteApps(ctx context.Context, apps []string, version, environment string, parallel, stopOnError bool) *BatchPromoteResult {
+	result := &BatchPromoteResult{Total: len(apps)}
+	entries := make([]BatchPromoteEntryResult, len(apps))
+
+	promoteOne := func(i int, app string) {
+		entries[i] = BatchPromoteEntryResult{
+			App:         app,
+			Source:      version,
+			Environment: environment,
+		}
+
+		promoteResult, err := p.PromoteFromTag(ctx, app, version, environment)
+		if err != nil {
+			LogError("Release train promotion of %s %s to %s failed: %v", app, version, environment, err)
+			entries[i].Status = "failed"
+			entries[i].Error = err.Error()
+		} else if promoteResult.Skipped {
+			entries[i].Status = "skipped"
+		} else {
+			entries[i].Status = "promoted"
+		}
+	}
+
+	if parallel {
+		var wg sync.WaitGroup
+		for i, app := range apps {
+			wg.Add(1)
+			go func(i int, app string) {
+				defer wg.Done()
+				promoteOne(i, app)
+			}(i, app)
+		}
+		wg.Wait()
+	} else {
+		for i, app := range apps {
+			promoteOne(i, app)
+			if stopOnError && entries[i].Status == "failed" {
+				entries = entries[:i+1]
+				break
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		switch entry.Status {
+		case "promoted":
+			result.Promoted++
+		case "skipped":
+			result.Skipped++
+		case "failed":
+			result.Failed++
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result
+}
+
+// PromoteBatch executes the entries in order, continuing past failures so a
+// partial release does not silently stop, and returns a per-entry summary.
+// With dryRun set, entries are only reported, not executed.
+func (p *ImagePromoter) PromoteBatch(ctx context.Context, entries []PromotionEntry, dryRun bool) *BatchPromoteResult {
+	result := &BatchPromoteResult{Total: len(entries)}
+
+	for _, entry := range entries {
+		entryResult := BatchPromoteEntryResult{
+			App:         entry.App,
+			Source:      entry.Source,
+			Environment: entry.Environment,
+		}
+
+		if dryRun {
+			LogInfo("Dry run: would promote %s to %s environment", entry.Source, entry.Environment)
+			entryResult.Status = "dry-run"
+			result.Entries = append(result.Entries, entryResult)
+			continue
+		}
+
+		var promoteResult *PromoteResult
+		var err error
+		if strings.Contains(entry.Source, ":") {
+			promoteResult, err = p.Promote(ctx, entry.Source, entry.Environment)
+		} else {
+			promoteResult, err = p.PromoteFromTag(ctx, entry.App, entry.Source, entry.Environment)
+		}
+
+		if err != nil {
+			LogError("Batch promotion of %s to %s failed: %v", entry.Source, entry.Environment, err)
+			entryResult.Status = "failed"
+			entryResult.Error = err.Error()
+			result.Failed++
+		} else if promoteResult.Skipped {
+			entryResult.Status = "skipped"
+			result.Skipped++
+		} else {
+			entryResult.Status = "promoted"
+			result.Promoted++
+		}
+
+		result.Entries = append(result.Entries, entryResult)
+	}
+
+	return result
+}
@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPromotionFile(t *testing.T) {
+	content := `[
+		// Coordinated release for the web stack
+		{"source": "myapp:20250721-2118-f7a5a27", "environment": "production"},
+		{"app": "api", "source": "v1.2.0", "environment": "staging"},
+	]`
+
+	tmpFile := filepath.Join(t.TempDir(), "promotions.json5")
+	err := os.WriteFile(tmpFile, []byte(content), 0644)
+	assert.NoError(t, err)
+
+	entries, err := LoadPromotionFile(tmpFile)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "myapp:20250721-2118-f7a5a27", entries[0].Source)
+	assert.Equal(t, "production", entries[0].Environment)
+	assert.Equal(t, "api", entries[1].App)
+	assert.Equal(t, "v1.2.0", entries[1].Source)
+}
+
+func TestLoadPromotionFile_MissingApp(t *testing.T) {
+	content := `[
+		{"source": "v1.2.0", "environment": "staging"},
+	]`
+
+	tmpFile := filepath.Join(t.TempDir(), "promotions.json5")
+	err := os.WriteFile(tmpFile, []byte(content), 0644)
+	assert.NoError(t, err)
+
+	_, err = LoadPromotionFile(tmpFile)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "app is required")
+}
+
+func TestPromoteBatch(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	// One promotable image, one entry pointing at a missing image
+	imageKey := "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"
+	mock.files[imageKey] = []byte("image")
+
+	promoter := NewImagePromoter(mock, "test-bucket")
+
+	entries := []PromotionEntry{
+		{Source: "myapp:20250721-2118-f7a5a27", Environment: "production"},
+		{Source: "ghost:20250721-2118-0000000", Environment: "production"},
+	}
+
+	result := promoter.PromoteBatch(ctx, entries, false)
+
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, 1, result.Promoted)
+	assert.Equal(t, 1, result.Failed)
+	assert.Len(t, result.Entries, 2)
+	assert.Equal(t, "promoted", result.Entries[0].Status)
+	assert.Equal(t, "failed", result.Entries[1].Status)
+	assert.Contains(t, result.Entries[1].Error, "not found")
+
+	// The live pointer was written for the successful entry only
+	exists, _ := mock.Exists(ctx, "test-bucket", GeneratePointerKey("myapp", "production"))
+	assert.True(t, exists)
+}
+
+func TestPromoteBatch_DryRun(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	promoter := NewImagePromoter(mock, "test-bucket")
+
+	entries := []PromotionEntry{
+		{Source: "myapp:20250721-2118-f7a5a27", Environment: "production"},
+	}
+
+	result := promoter.PromoteBatch(ctx, entries, true)
+
+	assert.Equal(t, 1, result.Total)
+	assert.Equal(t, 0, result.Promoted)
+	assert.Equal(t, "dry-run", result.Entries[0].Status)
+	assert.Empty(t, mock.files)
+}
+
+func trainTagFixture(mock *mockS3ClientForList, app string) {
+	imageKey := fmt.Sprintf("images/%s/202507/%s-20250721-2118-f7a5a27.tar.gz", app, app)
+	mock.files[imageKey] = []byte("image")
+	mock.files[GenerateTagKey(app, "v1.2.0")] = []byte(fmt.Sprintf(`{
+		"target_type": "image",
+		"target_path": "%s",
+		"source_image": "%s:20250721-2118-f7a5a27",
+		"git_hash": "f7a5a27",
+		"git_time": "20250721-2118"
+	}`, imageKey, app))
+}
+
+func TestPromoteApps(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+	trainTagFixture(mock, "app1")
+
+	promoter := NewImagePromoter(mock, "test-bucket")
+
+	result := promoter.PromoteApps(ctx, []string{"app1", "ghost"}, "v1.2.0", "production", false, false)
+
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, 1, result.Promoted)
+	assert.Equal(t, 1, result.Failed)
+	assert.Len(t, result.Entries, 2)
+	assert.Equal(t, "promoted", result.Entries[0].Status)
+	assert.Equal(t, "failed", result.Entries[1].Status)
+	assert.Contains(t, result.Entries[1].Error, "tag not found")
+
+	exists, _ := mock.Exists(ctx, "test-bucket", GeneratePointerKey("app1", "production"))
+	assert.True(t, exists)
+}
+
+func TestPromoteApps_StopOnError(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+	trainTagFixture(mock, "app1")
+
+	promoter := NewImagePromoter(mock, "test-bucket")
+
+	result := promoter.PromoteApps(ctx, []string{"ghost", "app1"}, "v1.2.0", "production", false, true)
+
+	// The train stops at the first failure; app1 is never promoted
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 0, result.Promoted)
+	assert.Len(t, result.Entries, 1)
+
+	exists, _ := mock.Exists(ctx, "test-bucket", GeneratePointerKey("app1", "production"))
+	assert.False(t, exists)
+}
+
+func TestPromoteApps_Parallel(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+	trainTagFixture(mock, "app1")
+	trainTagFixture(mock, "app2")
+
+	promoter := NewImagePromoter(mock, "test-bucket")
+
+	result := promoter.PromoteApps(ctx, []string{"app1", "app2"}, "v1.2.0", "production", true, false)
+
+	assert.Equal(t, 2, result.Promoted)
+	assert.Equal(t, 0, result.Failed)
+	for _, app := range []string{"app1", "app2"} {
+		exists, _ := mock.Exists(ctx, "test-bucket", GeneratePointerKey(app, "production"))
+		assert.True(t, exists)
+	}
+}
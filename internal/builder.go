@@ -1,8 +1,13 @@
 package internal
 
 import (
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
 )
 
 type ImageBuilder struct {
@@ -17,7 +22,42 @@ func NewImageBuilder(docker DockerClient, git GitClient) *ImageBuilder {
 	}
 }
 
-func (b *ImageBuilder) Build(ctx context.Context, appName string, contextPath string, dockerfile string, gitPath string, platform string) (*BuildResult, error) {
+// ResolveBuildPaths determines the git repository root and Docker build
+// context for a build invocation. Pass gitPath as "" when --path was not
+// given. Git root detection starts from the explicit --path when set,
+// otherwise from the context directory (so a subdirectory context inside a
+// larger repo resolves the enclosing repo), and finally falls back to the
+// current working directory. The context path itself is never changed by git
+// root detection - the context tar is built from the context dir only.
+func ResolveBuildPaths(git GitClient, gitPath, contextPath string) (string, string, error) {
+	if contextPath == "" {
+		contextPath = "."
+	}
+
+	// If --path is given but --context is not, build from the same directory
+	if gitPath != "" && contextPath == "." {
+		contextPath = gitPath
+	}
+
+	searchStart := gitPath
+	if searchStart == "" {
+		searchStart = contextPath
+	}
+
+	if repoRoot, err := git.FindRepositoryRoot(searchStart); err == nil {
+		LogDebug("Found git repository root from %s: %s", searchStart, repoRoot)
+		return repoRoot, contextPath, nil
+	}
+
+	if repoRoot, err := git.FindRepositoryRoot("."); err == nil {
+		LogDebug("Found git repository root from current directory: %s", repoRoot)
+		return repoRoot, contextPath, nil
+	}
+
+	return "", "", fmt.Errorf("could not find git repository from %s or current directory", searchStart)
+}
+
+func (b *ImageBuilder) Build(ctx context.Context, appName string, contextPath string, dockerfile string, gitPath string, platform string, cacheFrom []string, squash, pull bool) (*BuildResult, error) {
 	LogInfo("Starting build for app: %s", appName)
 	LogDebug("Build context: %s, Git path: %s, Dockerfile: %s, Platform: %s", contextPath, gitPath, dockerfile, platform)
 
@@ -55,16 +95,157 @@ func (b *ImageBuilder) Build(ctx context.Context, appName string, contextPath st
 	LogInfo("Building image %s with tag %s", appName, tag)
 
 	// Use contextPath for Docker build, gitPath for git operations
-	if err := b.docker.BuildImage(ctx, contextPath, dockerfile, []string{tag}, platform); err != nil {
+	if err := b.docker.BuildImage(ctx, contextPath, dockerfile, []string{tag}, platform, cacheFrom, squash, pull); err != nil {
 		LogError("Failed to build image %s: %v", tag, err)
 		return nil, fmt.Errorf("failed to build image: %w", err)
 	}
 
 	LogInfo("Successfully built %s", tag)
-	return &BuildResult{
+	result := &BuildResult{
 		ImageTag: tag,
 		AppName:  appName,
 		GitHash:  gitHash,
 		GitTime:  timestamp,
-	}, nil
+	}
+	if logger, ok := b.docker.(BuildLogger); ok {
+		result.BuildLog = logger.BuildLog()
+	}
+	return result, nil
+}
+
+// UnchangedSinceLatest reports whether the build context subtree is identical
+// to the one recorded by the latest pushed image for appName. It compares the
+// git tree hash of the subtree at HEAD with the tree hash at the commit stored
+// in the latest image's metadata, so monorepo CI can skip builds when nothing
+// in the service's directory changed.
+func UnchangedSinceLatest(ctx context.Context, s3 S3Client, git GitClient, bucket, appName, gitPath, contextPath string) (bool, error) {
+	prefix := fmt.Sprintf("images/%s/", appName)
+	keys, err := s3.List(ctx, bucket, prefix)
+	if err != nil {
+		LogError("Failed to list images for %s: %v", appName, err)
+		return false, fmt.Errorf("failed to list images for %s: %w", appName, err)
+	}
+
+	var metaKeys []string
+	for _, key := range keys {
+		if strings.HasSuffix(key, ".json") {
+			metaKeys = append(metaKeys, key)
+		}
+	}
+
+	if len(metaKeys) == 0 {
+		LogDebug("No pushed images found for %s - nothing to compare against", appName)
+		return false, nil
+	}
+
+	// Keys embed year-month and timestamp, so lexical order is build order
+	sort.Strings(metaKeys)
+	latestKey := metaKeys[len(metaKeys)-1]
+
+	data, err := s3.Download(ctx, bucket, latestKey)
+	if err != nil {
+		LogError("Failed to download metadata %s: %v", latestKey, err)
+		return false, fmt.Errorf("failed to download metadata %s: %w", latestKey, err)
+	}
+
+	metadata, err := ImageMetadataFromJSON(data)
+	if err != nil {
+		LogError("Failed to parse metadata %s: %v", latestKey, err)
+		return false, fmt.Errorf("failed to parse metadata %s: %w", latestKey, err)
+	}
+
+	headTree, err := git.GetTreeHash(gitPath, contextPath)
+	if err != nil {
+		LogError("Failed to get tree hash of %s: %v", contextPath, err)
+		return false, fmt.Errorf("failed to get tree hash of %s: %w", contextPath, err)
+	}
+
+	prevTree, err := git.GetTreeHashAtCommit(gitPath, contextPath, metadata.GitHash)
+	if err != nil {
+		// Shallow clones may not contain the old commit - treat as changed
+		LogDebug("Could not resolve tree at commit %s: %v", metadata.GitHash, err)
+		return false, nil
+	}
+
+	LogDebug("Tree hash at HEAD: %s, at %s: %s", headTree, metadata.GitHash, prevTree)
+	return headTree == prevTree, nil
+}
+
+// VerifyReproducible builds the image twice and compares the normalized,
+// compressed export digests. A mismatch means the Dockerfile produces
+// non-deterministic output (e.g. embedded timestamps or unordered operations)
+// that tar normalization alone cannot fix.
+func (b *ImageBuilder) VerifyReproducible(ctx context.Context, appName string, contextPath string, dockerfile string, gitPath string, platform string, cacheFrom []string, squash, pull bool) (*BuildResult, error) {
+	LogInfo("Verifying reproducibility for app: %s", appName)
+
+	result, err := b.Build(ctx, appName, contextPath, dockerfile, gitPath, platform, cacheFrom, squash, pull)
+	if err != nil {
+		return nil, err
+	}
+
+	firstDigest, err := b.exportedDigest(ctx, result.ImageTag, result.GitTime)
+	if err != nil {
+		LogError("Failed to compute digest of first build: %v", err)
+		return nil, fmt.Errorf("failed to compute digest of first build: %w", err)
+	}
+
+	LogDebug("First build digest: %s", firstDigest)
+	LogInfo("Rebuilding %s to verify reproducibility", result.ImageTag)
+
+	if _, err := b.Build(ctx, appName, contextPath, dockerfile, gitPath, platform, cacheFrom, squash, pull); err != nil {
+		LogError("Second build failed: %v", err)
+		return nil, fmt.Errorf("second build failed: %w", err)
+	}
+
+	secondDigest, err := b.exportedDigest(ctx, result.ImageTag, result.GitTime)
+	if err != nil {
+		LogError("Failed to compute digest of second build: %v", err)
+		return nil, fmt.Errorf("failed to compute digest of second build: %w", err)
+	}
+
+	LogDebug("Second build digest: %s", secondDigest)
+
+	if firstDigest != secondDigest {
+		LogError("Build is not reproducible: %s != %s", firstDigest, secondDigest)
+		return nil, fmt.Errorf("build is not reproducible: first digest %s, second digest %s", firstDigest, secondDigest)
+	}
+
+	LogInfo("Build is reproducible (digest: %s)", firstDigest)
+	return result, nil
+}
+
+// exportedDigest exports the image and computes the checksum of its
+// normalized, gzip-compressed form - the same pipeline push uses.
+func (b *ImageBuilder) exportedDigest(ctx context.Context, imageRef, gitTime string) (string, error) {
+	fixedTime, err := ParseGitTime(gitTime)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse git time: %w", err)
+	}
+
+	imageData, err := b.docker.ExportImage(ctx, imageRef)
+	if err != nil {
+		return "", fmt.Errorf("failed to export image: %w", err)
+	}
+	defer imageData.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		gzipWriter := gzip.NewWriter(pw)
+		gzipWriter.ModTime = time.Time{} // Set to zero time for deterministic output
+
+		if err := NormalizeTar(imageData, gzipWriter, fixedTime); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		gzipWriter.Close()
+		pw.Close()
+	}()
+
+	metadata, _, err := CalculateMetadata(pr, "", gitTime, imageRef, "")
+	if err != nil {
+		return "", err
+	}
+
+	return metadata.Checksum, nil
 }
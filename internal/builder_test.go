@@ -15,11 +15,11 @@ func TestImageBuilder_Build_Success(t *testing.T) {
 	mockGit.On("IsRepositoryDirty", ".").Return(false, nil)
 	mockGit.On("GetCurrentHash", ".").Return("abc1234", nil)
 	mockGit.On("GetCommitTimestamp", ".").Return("20250721-1430", nil)
-	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "").Return(nil)
+	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "", []string(nil), false, false).Return(nil)
 
 	builder := NewImageBuilder(mockDocker, mockGit)
 
-	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "")
+	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "", nil, false, false)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -31,6 +31,34 @@ func TestImageBuilder_Build_Success(t *testing.T) {
 	mockDocker.AssertExpectations(t)
 }
 
+// buildLogMock adds the optional BuildLogger capability to the standard mock
+type buildLogMock struct {
+	*MockDockerClient
+	log string
+}
+
+func (m *buildLogMock) BuildLog() string {
+	return m.log
+}
+
+func TestImageBuilder_Build_CapturesBuildLog(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("IsRepositoryDirty", ".").Return(false, nil)
+	mockGit.On("GetCurrentHash", ".").Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", ".").Return("20250721-1430", nil)
+	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "", []string(nil), false, false).Return(nil)
+
+	docker := &buildLogMock{MockDockerClient: mockDocker, log: "Step 1/1 : FROM alpine\nSuccessfully built abc123\n"}
+	builder := NewImageBuilder(docker, mockGit)
+
+	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "", nil, false, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, docker.log, result.BuildLog)
+}
+
 func TestImageBuilder_Build_DirtyRepository(t *testing.T) {
 	mockDocker := new(MockDockerClient)
 	mockGit := new(MockGitClient)
@@ -39,7 +67,7 @@ func TestImageBuilder_Build_DirtyRepository(t *testing.T) {
 
 	builder := NewImageBuilder(mockDocker, mockGit)
 
-	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "")
+	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "", nil, false, false)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -56,7 +84,7 @@ func TestImageBuilder_Build_GitHashError(t *testing.T) {
 
 	builder := NewImageBuilder(mockDocker, mockGit)
 
-	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "")
+	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "", nil, false, false)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -74,7 +102,7 @@ func TestImageBuilder_Build_GitTimestampError(t *testing.T) {
 
 	builder := NewImageBuilder(mockDocker, mockGit)
 
-	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "")
+	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "", nil, false, false)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -89,11 +117,11 @@ func TestImageBuilder_Build_DockerBuildError(t *testing.T) {
 	mockGit.On("IsRepositoryDirty", ".").Return(false, nil)
 	mockGit.On("GetCurrentHash", ".").Return("abc1234", nil)
 	mockGit.On("GetCommitTimestamp", ".").Return("20250721-1430", nil)
-	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "").Return(errors.New("docker build error"))
+	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "", []string(nil), false, false).Return(errors.New("docker build error"))
 
 	builder := NewImageBuilder(mockDocker, mockGit)
 
-	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "")
+	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "", nil, false, false)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -102,6 +130,109 @@ func TestImageBuilder_Build_DockerBuildError(t *testing.T) {
 	mockDocker.AssertExpectations(t)
 }
 
+func TestImageBuilder_VerifyReproducible_Identical(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("IsRepositoryDirty", ".").Return(false, nil)
+	mockGit.On("GetCurrentHash", ".").Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", ".").Return("20250721-1430", nil)
+	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "", []string(nil), false, false).Return(nil)
+
+	// Both exports produce identical content
+	mockDocker.On("ExportImage", context.Background(), "myapp:20250721-1430-abc1234").Return(createTestTar("image data"), nil).Once()
+	mockDocker.On("ExportImage", context.Background(), "myapp:20250721-1430-abc1234").Return(createTestTar("image data"), nil).Once()
+
+	builder := NewImageBuilder(mockDocker, mockGit)
+
+	result, err := builder.VerifyReproducible(context.Background(), "myapp", ".", "Dockerfile", ".", "", nil, false, false)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "myapp:20250721-1430-abc1234", result.ImageTag)
+	mockGit.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestImageBuilder_VerifyReproducible_Differs(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("IsRepositoryDirty", ".").Return(false, nil)
+	mockGit.On("GetCurrentHash", ".").Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", ".").Return("20250721-1430", nil)
+	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "", []string(nil), false, false).Return(nil)
+
+	// Second export produces different content (non-deterministic build)
+	mockDocker.On("ExportImage", context.Background(), "myapp:20250721-1430-abc1234").Return(createTestTar("image data"), nil).Once()
+	mockDocker.On("ExportImage", context.Background(), "myapp:20250721-1430-abc1234").Return(createTestTar("different image data"), nil).Once()
+
+	builder := NewImageBuilder(mockDocker, mockGit)
+
+	result, err := builder.VerifyReproducible(context.Background(), "myapp", ".", "Dockerfile", ".", "", nil, false, false)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "not reproducible")
+	mockGit.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestResolveBuildPaths_SubdirectoryContext(t *testing.T) {
+	mockGit := new(MockGitClient)
+
+	// Context is a subdirectory inside a larger repo; git detection should
+	// resolve the enclosing repo root without touching the context path
+	mockGit.On("FindRepositoryRoot", "./services/web").Return("/repo", nil)
+
+	gitPath, contextPath, err := ResolveBuildPaths(mockGit, "", "./services/web")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/repo", gitPath)
+	assert.Equal(t, "./services/web", contextPath)
+	mockGit.AssertExpectations(t)
+}
+
+func TestResolveBuildPaths_ExplicitPathWins(t *testing.T) {
+	mockGit := new(MockGitClient)
+
+	mockGit.On("FindRepositoryRoot", "/repo").Return("/repo", nil)
+
+	gitPath, contextPath, err := ResolveBuildPaths(mockGit, "/repo", "./services/web")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/repo", gitPath)
+	assert.Equal(t, "./services/web", contextPath)
+	mockGit.AssertExpectations(t)
+}
+
+func TestResolveBuildPaths_PathWithoutContext(t *testing.T) {
+	mockGit := new(MockGitClient)
+
+	mockGit.On("FindRepositoryRoot", "/repo").Return("/repo", nil)
+
+	gitPath, contextPath, err := ResolveBuildPaths(mockGit, "/repo", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/repo", gitPath)
+	assert.Equal(t, "/repo", contextPath)
+	mockGit.AssertExpectations(t)
+}
+
+func TestResolveBuildPaths_FallbackToCwd(t *testing.T) {
+	mockGit := new(MockGitClient)
+
+	mockGit.On("FindRepositoryRoot", "./build-context").Return("", errors.New("not a repository"))
+	mockGit.On("FindRepositoryRoot", ".").Return("/cwd-repo", nil)
+
+	gitPath, contextPath, err := ResolveBuildPaths(mockGit, "", "./build-context")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/cwd-repo", gitPath)
+	assert.Equal(t, "./build-context", contextPath)
+	mockGit.AssertExpectations(t)
+}
+
 func TestImageBuilder_Build_WithPlatform(t *testing.T) {
 	mockDocker := new(MockDockerClient)
 	mockGit := new(MockGitClient)
@@ -109,11 +240,11 @@ func TestImageBuilder_Build_WithPlatform(t *testing.T) {
 	mockGit.On("IsRepositoryDirty", ".").Return(false, nil)
 	mockGit.On("GetCurrentHash", ".").Return("abc1234", nil)
 	mockGit.On("GetCommitTimestamp", ".").Return("20250721-1430", nil)
-	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "linux/amd64").Return(nil)
+	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "linux/amd64", []string(nil), false, false).Return(nil)
 
 	builder := NewImageBuilder(mockDocker, mockGit)
 
-	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "linux/amd64")
+	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "linux/amd64", nil, false, false)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -121,3 +252,112 @@ func TestImageBuilder_Build_WithPlatform(t *testing.T) {
 	mockGit.AssertExpectations(t)
 	mockDocker.AssertExpectations(t)
 }
+
+func TestImageBuilder_Build_WithCacheFrom(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("IsRepositoryDirty", ".").Return(false, nil)
+	mockGit.On("GetCurrentHash", ".").Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", ".").Return("20250721-1430", nil)
+	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "", []string{"myapp:cache"}, false, false).Return(nil)
+
+	builder := NewImageBuilder(mockDocker, mockGit)
+
+	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "", []string{"myapp:cache"}, false, false)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	mockGit.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestImageBuilder_Build_WithSquashAndPull(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("IsRepositoryDirty", ".").Return(false, nil)
+	mockGit.On("GetCurrentHash", ".").Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", ".").Return("20250721-1430", nil)
+	mockDocker.On("BuildImage", context.Background(), ".", "Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "", []string(nil), true, true).Return(nil)
+
+	builder := NewImageBuilder(mockDocker, mockGit)
+
+	result, err := builder.Build(context.Background(), "myapp", ".", "Dockerfile", ".", "", nil, true, true)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	mockGit.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestUnchangedSinceLatest_Unchanged(t *testing.T) {
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockS3.On("List", context.Background(), "test-bucket", "images/myapp/").Return([]string{
+		"images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz",
+		"images/myapp/202507/myapp-20250721-1430-abc1234.json",
+	}, nil)
+	mockS3.On("Download", context.Background(), "test-bucket", "images/myapp/202507/myapp-20250721-1430-abc1234.json").Return(
+		[]byte(`{"git_hash": "abc1234", "image_tag": "myapp:20250721-1430-abc1234"}`), nil)
+	mockGit.On("GetTreeHash", ".", "./services/myapp").Return("tree-hash-1", nil)
+	mockGit.On("GetTreeHashAtCommit", ".", "./services/myapp", "abc1234").Return("tree-hash-1", nil)
+
+	unchanged, err := UnchangedSinceLatest(context.Background(), mockS3, mockGit, "test-bucket", "myapp", ".", "./services/myapp")
+
+	assert.NoError(t, err)
+	assert.True(t, unchanged)
+	mockS3.AssertExpectations(t)
+	mockGit.AssertExpectations(t)
+}
+
+func TestUnchangedSinceLatest_Changed(t *testing.T) {
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockS3.On("List", context.Background(), "test-bucket", "images/myapp/").Return([]string{
+		"images/myapp/202507/myapp-20250721-1430-abc1234.json",
+	}, nil)
+	mockS3.On("Download", context.Background(), "test-bucket", "images/myapp/202507/myapp-20250721-1430-abc1234.json").Return(
+		[]byte(`{"git_hash": "abc1234"}`), nil)
+	mockGit.On("GetTreeHash", ".", ".").Return("tree-hash-2", nil)
+	mockGit.On("GetTreeHashAtCommit", ".", ".", "abc1234").Return("tree-hash-1", nil)
+
+	unchanged, err := UnchangedSinceLatest(context.Background(), mockS3, mockGit, "test-bucket", "myapp", ".", ".")
+
+	assert.NoError(t, err)
+	assert.False(t, unchanged)
+}
+
+func TestUnchangedSinceLatest_NoImages(t *testing.T) {
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockS3.On("List", context.Background(), "test-bucket", "images/myapp/").Return([]string{}, nil)
+
+	unchanged, err := UnchangedSinceLatest(context.Background(), mockS3, mockGit, "test-bucket", "myapp", ".", ".")
+
+	assert.NoError(t, err)
+	assert.False(t, unchanged)
+	mockGit.AssertNotCalled(t, "GetTreeHash")
+}
+
+func TestUnchangedSinceLatest_UnresolvableCommit(t *testing.T) {
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockS3.On("List", context.Background(), "test-bucket", "images/myapp/").Return([]string{
+		"images/myapp/202507/myapp-20250721-1430-abc1234.json",
+	}, nil)
+	mockS3.On("Download", context.Background(), "test-bucket", "images/myapp/202507/myapp-20250721-1430-abc1234.json").Return(
+		[]byte(`{"git_hash": "abc1234"}`), nil)
+	mockGit.On("GetTreeHash", ".", ".").Return("tree-hash-1", nil)
+	// Shallow clones may not contain the old commit - treated as changed
+	mockGit.On("GetTreeHashAtCommit", ".", ".", "abc1234").Return("", errors.New("object not found"))
+
+	unchanged, err := UnchangedSinceLatest(context.Background(), mockS3, mockGit, "test-bucket", "myapp", ".", ".")
+
+	assert.NoError(t, err)
+	assert.False(t, unchanged)
+}
@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ImageCleaner deletes aged images from S3 while keeping anything that is
+// still referenced by a tag or environment pointer
+type ImageCleaner struct {
+	s3     S3Client
+	bucket string
+	list   *ListService
+}
+
+func NewImageCleaner(s3 S3Client, bucket string) *ImageCleaner {
+	return &ImageCleaner{
+		s3:     s3,
+		bucket: bucket,
+		list:   NewListService(s3, bucket),
+	}
+}
+
+// Cleanup deletes images (and their metadata) older than olderThan, based on
+// the commit timestamp embedded in the image tag. With keepPromoted set, any
+// image referenced by a tag or environment pointer is protected from deletion
+// regardless of age. With dryRun set, deletions are only reported.
+func (c *ImageCleaner) Cleanup(ctx context.Context, appName string, olderThan time.Duration, keepPromoted, dryRun bool) (*CleanupResult, error) {
+	images, err := c.list.ListImages(ctx, appName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	protected := make(map[string]bool)
+	if keepPromoted {
+		protected, err = c.ProtectedImageKeys(ctx, appName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	result := &CleanupResult{AppName: appName, DryRun: dryRun}
+
+	for _, img := range images {
+		// Tag format is <timestamp>-<hash>; the timestamp is the commit time
+		dashIndex := strings.LastIndex(img.Tag, "-")
+		if dashIndex == -1 {
+			continue
+		}
+		commitTime, err := time.Parse("20060102-1504", img.Tag[:dashIndex])
+		if err != nil {
+			LogDebug("Skipping image with unparseable timestamp %s: %v", img.Tag, err)
+			continue
+		}
+		if commitTime.After(cutoff) {
+			continue
+		}
+
+		if protected[img.S3Path] {
+			LogDebug("Keeping %s (referenced by a tag or environment)", img.S3Path)
+			result.Protected++
+			continue
+		}
+
+		if dryRun {
+			LogInfo("Would delete %s", img.S3Path)
+		} else {
+			LogInfo("Deleting %s", img.S3Path)
+			if err := c.s3.Delete(ctx, c.bucket, img.S3Path); err != nil {
+				LogError("Failed to delete image %s: %v", img.S3Path, err)
+				return nil, fmt.Errorf("failed to delete image %s: %w", img.S3Path, err)
+			}
+			metadataKey := GenerateMetadataKey(img.S3Path)
+			if err := c.s3.Delete(ctx, c.bucket, metadataKey); err != nil {
+				LogError("Failed to delete metadata %s: %v", metadataKey, err)
+				return nil, fmt.Errorf("failed to delete metadata %s: %w", metadataKey, err)
+			}
+		}
+		result.Deleted = append(result.Deleted, img.S3Path)
+	}
+
+	LogInfo("Cleanup for %s: %d deleted, %d protected", appName, len(result.Deleted), result.Protected)
+	return result, nil
+}
+
+// ProtectedImageKeys builds the set of image keys that must not be deleted
+// for an app by resolving every tag and every environment pointer (including
+// pending ones), following tag indirection. The set is reusable by any
+// deletion path that needs the same safety net.
+func (c *ImageCleaner) ProtectedImageKeys(ctx context.Context, appName string) (map[string]bool, error) {
+	protected := make(map[string]bool)
+
+	prefixes := []string{
+		fmt.Sprintf("tags/%s/", appName),
+		fmt.Sprintf("pointers/%s/", appName),
+	}
+
+	for _, prefix := range prefixes {
+		keys, err := c.s3.List(ctx, c.bucket, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+		}
+
+		for _, key := range keys {
+			if !strings.HasSuffix(key, ".json") {
+				continue
+			}
+
+			data, err := c.s3.Download(ctx, c.bucket, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to download pointer %s: %w", key, err)
+			}
+
+			pointer, err := PointerMetadataFromJSON(data)
+			if err != nil {
+				LogDebug("Skipping unparseable pointer %s: %v", key, err)
+				continue
+			}
+
+			imagePath, err := ResolveImagePath(ctx, c.s3, c.bucket, pointer)
+			if err != nil {
+				// Dangling pointers (e.g. to an already-deleted tag) protect nothing
+				LogDebug("Failed to resolve pointer %s: %v", key, err)
+				continue
+			}
+			protected[imagePath] = true
+		}
+	}
+
+	return protected, nil
+}
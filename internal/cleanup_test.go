@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanup_KeepPromoted(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"] = []byte("old")
+	mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.json"] = []byte("{}")
+	mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"] = []byte("tagged")
+	mock.files["images/myapp/202507/myapp-20250722-0900-cafe123.tar.gz"] = []byte("promoted")
+
+	// v1.0.0 protects the tagged image; production points at the tag, so both
+	// resolve to the same protected set entries
+	mock.files["tags/myapp/v1.0.0.json"] = []byte(`{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		"source_image": "myapp:20250721-2118-f7a5a27"
+	}`)
+	mock.files["pointers/myapp/production.json"] = []byte(`{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250722-0900-cafe123.tar.gz",
+		"source_image": "myapp:20250722-0900-cafe123"
+	}`)
+
+	cleaner := NewImageCleaner(mock, "test-bucket")
+
+	result, err := cleaner.Cleanup(ctx, "myapp", 24*time.Hour, true, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"}, result.Deleted)
+	assert.Equal(t, 2, result.Protected)
+
+	// Image and its metadata are gone; protected images remain
+	_, exists := mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"]
+	assert.False(t, exists)
+	_, exists = mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.json"]
+	assert.False(t, exists)
+	_, exists = mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"]
+	assert.True(t, exists)
+	_, exists = mock.files["images/myapp/202507/myapp-20250722-0900-cafe123.tar.gz"]
+	assert.True(t, exists)
+}
+
+func TestCleanup_DryRun(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"] = []byte("old")
+
+	cleaner := NewImageCleaner(mock, "test-bucket")
+
+	result, err := cleaner.Cleanup(ctx, "myapp", 24*time.Hour, false, true)
+
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []string{"images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"}, result.Deleted)
+
+	_, exists := mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"]
+	assert.True(t, exists)
+}
+
+func TestProtectedImageKeys_FollowsTagIndirection(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	mock.files["tags/myapp/v1.0.0.json"] = []byte(`{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		"source_image": "myapp:20250721-2118-f7a5a27"
+	}`)
+	// Environment points at the tag, not directly at the image
+	mock.files["pointers/myapp/production.json"] = []byte(`{
+		"target_type": "tag",
+		"target_path": "tags/myapp/v1.0.0.json",
+		"source_tag": "v1.0.0"
+	}`)
+
+	cleaner := NewImageCleaner(mock, "test-bucket")
+
+	protected, err := cleaner.ProtectedImageKeys(ctx, "myapp")
+
+	assert.NoError(t, err)
+	assert.True(t, protected["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"])
+}
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,20 +15,36 @@ type Config struct {
 	Profiles       map[string]Profile `json:"profiles"`
 	Docker         DockerConfig       `json:"docker"`
 	Naming         NamingConfig       `json:"naming"`
+	Audit          AuditConfig        `json:"audit"`
+	Promotion      PromotionConfig    `json:"promotion"`
 	Defaults       DefaultsConfig     `json:"defaults"`
 }
 
 type Profile struct {
-	Bucket    string `json:"bucket"`
-	Region    string `json:"region"`
-	Endpoint  string `json:"endpoint"`
-	AccessKey string `json:"access_key"`
-	SecretKey string `json:"secret_key"`
+	Bucket     string `json:"bucket"`
+	Region     string `json:"region"`
+	Endpoint   string `json:"endpoint"`
+	AccessKey  string `json:"access_key"`
+	SecretKey  string `json:"secret_key"`
+	RoleArn    string `json:"role_arn"`    // STS role to assume on top of the base credentials
+	ExternalID string `json:"external_id"` // Optional external ID for the assume-role call
+
+	Compression      string `json:"compression"`       // Overrides docker.compression for this profile
+	CompressionLevel int    `json:"compression_level"` // Overrides docker.compression_level for this profile
+
+	DisableServerSideCopy bool `json:"disable_server_side_copy"` // Force download-and-reupload for providers without CopyObject
+
+	DisableEndpointDetection bool `json:"disable_endpoint_detection"` // Skip bucket-subdomain endpoint detection (self-hosted MinIO)
+
+	RateLimit float64 `json:"rate_limit"` // Cap on S3 requests per second (0 = unlimited), for rate-limited providers
 }
 
 type DockerConfig struct {
-	Timeout     string `json:"timeout"`
-	Compression string `json:"compression"`
+	Timeout          string   `json:"timeout"`
+	Compression      string   `json:"compression"`
+	CompressionLevel int      `json:"compression_level"` // gzip level 1-9 (0 = library default)
+	Builder          string   `json:"builder"`           // "legacy" (default) or "buildkit"
+	CacheFrom        []string `json:"cache_from"`        // Default cache sources for builds
 }
 
 type NamingConfig struct {
@@ -36,30 +53,68 @@ type NamingConfig struct {
 	PathTemplate     string `json:"path_template"`
 }
 
+type AuditConfig struct {
+	Chain bool `json:"chain"` // Link audit events with prev_hash for tamper evidence (audit verify)
+}
+
+type PromotionConfig struct {
+	RequireApproval []string `json:"require_approval"`             // Environments where promotions need a second approver (s3dock approve)
+	RequireConfirm  []string `json:"require_confirm_environments"` // Environments where promote asks for interactive confirmation
+}
+
 type DefaultsConfig struct {
-	RetryCount int    `json:"retry_count"`
-	LogLevel   string `json:"log_level"`
+	RetryCount    int    `json:"retry_count"`
+	LogLevel      string `json:"log_level"`
+	ImmutableTags *bool  `json:"immutable_tags"` // Defaults to true when unset
+	FallbackTag   string `json:"fallback_tag"`   // Tag tried by current --fallback (defaults to "latest")
 }
 
 type ResolvedConfig struct {
-	Bucket    string
-	Region    string
-	Endpoint  string
-	AccessKey string
-	SecretKey string
+	Bucket     string
+	Region     string
+	Endpoint   string
+	AccessKey  string
+	SecretKey  string
+	RoleArn    string
+	ExternalID string
+
+	DisableServerSideCopy bool
+
+	DisableEndpointDetection bool
 
-	DockerTimeout     string
-	DockerCompression string
+	RateLimit float64
+
+	DockerTimeout          string
+	DockerCompression      string
+	DockerCompressionLevel int
+	DockerBuilder          string
+	DockerCacheFrom        []string
 
 	IncludeGitBranch bool
 	TimestampFormat  string
 	PathTemplate     string
 
-	RetryCount int
-	LogLevel   string
+	AuditChain bool
+
+	RequireApproval []string
+	RequireConfirm  []string
+
+	RetryCount    int
+	LogLevel      string
+	ImmutableTags bool
+	FallbackTag   string
 }
 
 func LoadConfig(configPath string) (*Config, error) {
+	// Teams can share a single config stored in the bucket itself
+	if strings.HasPrefix(configPath, "s3://") {
+		data, err := fetchRemoteConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+		return parseConfig(data, configPath)
+	}
+
 	var actualPath string
 	var err error
 
@@ -68,6 +123,7 @@ func LoadConfig(configPath string) (*Config, error) {
 	} else {
 		actualPath, err = findConfigFile()
 		if err != nil {
+			warnDefaultConfig()
 			return getDefaultConfig(), nil
 		}
 	}
@@ -77,18 +133,67 @@ func LoadConfig(configPath string) (*Config, error) {
 		if configPath != "" {
 			return nil, fmt.Errorf("failed to read config file %s: %w", actualPath, err)
 		}
+		warnDefaultConfig()
 		return getDefaultConfig(), nil
 	}
 
+	return parseConfig(data, actualPath)
+}
+
+// parseConfig unmarshals config bytes and expands file:/env: indirections.
+// source names the origin (path or s3:// reference) for error messages.
+func parseConfig(data []byte, source string) (*Config, error) {
 	j := jsonc.New()
 	var config Config
 	if err := j.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file %s: %w", actualPath, err)
+		return nil, fmt.Errorf("failed to parse config file %s: %w", source, err)
+	}
+
+	// Any config string may use file:/env: indirection
+	if err := expandConfigIndirections(&config); err != nil {
+		return nil, fmt.Errorf("failed to resolve config indirection in %s: %w", source, err)
 	}
 
 	return &config, nil
 }
 
+// parseS3ConfigRef splits an s3://bucket/key config reference
+func parseS3ConfigRef(ref string) (string, string, error) {
+	trimmed := strings.TrimPrefix(ref, "s3://")
+	parts := strings.SplitN(trimmed, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid remote config reference %s (expected s3://bucket/key)", ref)
+	}
+	return parts[0], parts[1], nil
+}
+
+// fetchRemoteConfig downloads a config object referenced as s3://bucket/key.
+// The config itself can't supply credentials for its own retrieval, so the
+// fetch bootstraps from the default AWS credential chain and region/endpoint
+// environment variables only.
+func fetchRemoteConfig(ref string) ([]byte, error) {
+	bucket, key, err := parseS3ConfigRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	s3Client, err := NewS3Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client for remote config: %w", err)
+	}
+
+	LogDebug("Fetching remote config from s3://%s/%s", bucket, key)
+
+	data, err := s3Client.Download(ctx, bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download remote config %s: %w", ref, err)
+	}
+
+	return data, nil
+}
+
 func findConfigFile() (string, error) {
 	homeDir, _ := os.UserHomeDir()
 
@@ -107,6 +212,33 @@ func findConfigFile() (string, error) {
 	return "", fmt.Errorf("no config file found")
 }
 
+// quietWarnings suppresses advisory warnings like the missing-config notice
+// (--quiet). defaultConfigWarned keeps the notice to once per run even though
+// LoadConfig may be called several times.
+var (
+	quietWarnings       bool
+	defaultConfigWarned bool
+)
+
+// SetQuiet suppresses advisory stderr warnings (--quiet).
+func SetQuiet(quiet bool) {
+	quietWarnings = quiet
+}
+
+// warnDefaultConfig tells the user that no config file was found and the
+// built-in defaults are in effect, which otherwise surprises people expecting
+// an error.
+func warnDefaultConfig() {
+	if quietWarnings || defaultConfigWarned {
+		return
+	}
+	defaultConfigWarned = true
+
+	defaults := getDefaultConfig()
+	profile := defaults.Profiles[defaults.DefaultProfile]
+	fmt.Fprintf(os.Stderr, "Warning: no config file found, using built-in defaults: bucket=%s, region=%s\n", profile.Bucket, profile.Region)
+}
+
 func getDefaultConfig() *Config {
 	return &Config{
 		DefaultProfile: "default",
@@ -132,10 +264,36 @@ func getDefaultConfig() *Config {
 	}
 }
 
+// ConfigSource identifies where a resolved config value came from
+type ConfigSource string
+
+const (
+	SourceFlag    ConfigSource = "flag"
+	SourceEnv     ConfigSource = "env"
+	SourceConfig  ConfigSource = "config"
+	SourceDefault ConfigSource = "default"
+)
+
+// ConfigSources records the provenance of each resolved connection field
+type ConfigSources struct {
+	Bucket    ConfigSource
+	Region    ConfigSource
+	Endpoint  ConfigSource
+	AccessKey ConfigSource
+	SecretKey ConfigSource
+}
+
 func ResolveConfig(configPath, profileName, bucketOverride string) (*ResolvedConfig, error) {
+	resolved, _, err := ResolveConfigWithSources(configPath, profileName, bucketOverride)
+	return resolved, err
+}
+
+// ResolveConfigWithSources resolves the effective configuration and reports
+// where each connection value came from (flag, env, config, or default).
+func ResolveConfigWithSources(configPath, profileName, bucketOverride string) (*ResolvedConfig, *ConfigSources, error) {
 	config, err := LoadConfig(configPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	profile := profileName
@@ -149,69 +307,140 @@ func ResolveConfig(configPath, profileName, bucketOverride string) (*ResolvedCon
 
 	profileConfig, exists := config.Profiles[profile]
 	if !exists {
-		return nil, fmt.Errorf("profile '%s' not found in config", profile)
+		return nil, nil, fmt.Errorf("profile '%s' not found in config", profile)
+	}
+
+	immutableTags := true
+	if config.Defaults.ImmutableTags != nil {
+		immutableTags = *config.Defaults.ImmutableTags
+	}
+
+	fallbackTag := config.Defaults.FallbackTag
+	if fallbackTag == "" {
+		fallbackTag = "latest"
+	}
+
+	// Profile compression settings override the global docker section
+	compression := config.Docker.Compression
+	if profileConfig.Compression != "" {
+		compression = profileConfig.Compression
+	}
+	compressionLevel := config.Docker.CompressionLevel
+	if profileConfig.CompressionLevel != 0 {
+		compressionLevel = profileConfig.CompressionLevel
+	}
+
+	bucket, bucketSource := resolveBucket(bucketOverride, profileConfig.Bucket)
+	region, regionSource := resolveRegion(profileConfig.Region)
+	endpoint, endpointSource := resolveEndpoint(profileConfig.Endpoint)
+	accessKey, accessKeySource := resolveAccessKey(profileConfig.AccessKey)
+	secretKey, secretKeySource := resolveSecretKey(profileConfig.SecretKey)
+
+	// Credential values may be indirect (env:, file:, kms: prefixes)
+	accessKey, err = resolveSecretValue(accessKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve access key: %w", err)
+	}
+	secretKey, err = resolveSecretValue(secretKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve secret key: %w", err)
 	}
 
 	resolved := &ResolvedConfig{
-		Bucket:            resolveBucket(bucketOverride, profileConfig.Bucket),
-		Region:            resolveRegion(profileConfig.Region),
-		Endpoint:          resolveEndpoint(profileConfig.Endpoint),
-		AccessKey:         resolveAccessKey(profileConfig.AccessKey),
-		SecretKey:         resolveSecretKey(profileConfig.SecretKey),
-		DockerTimeout:     config.Docker.Timeout,
-		DockerCompression: config.Docker.Compression,
-		IncludeGitBranch:  config.Naming.IncludeGitBranch,
-		TimestampFormat:   config.Naming.TimestampFormat,
-		PathTemplate:      config.Naming.PathTemplate,
-		RetryCount:        config.Defaults.RetryCount,
-		LogLevel:          config.Defaults.LogLevel,
+		Bucket:                bucket,
+		Region:                region,
+		Endpoint:              endpoint,
+		AccessKey:             accessKey,
+		SecretKey:             secretKey,
+		RoleArn:               profileConfig.RoleArn,
+		ExternalID:            profileConfig.ExternalID,
+		DisableServerSideCopy: profileConfig.DisableServerSideCopy,
+
+		DisableEndpointDetection: profileConfig.DisableEndpointDetection,
+		RateLimit:                profileConfig.RateLimit,
+		DockerTimeout:            config.Docker.Timeout,
+		DockerCompression:        compression,
+		DockerCompressionLevel:   compressionLevel,
+		DockerBuilder:            config.Docker.Builder,
+		DockerCacheFrom:          config.Docker.CacheFrom,
+		IncludeGitBranch:         config.Naming.IncludeGitBranch,
+		TimestampFormat:          config.Naming.TimestampFormat,
+		PathTemplate:             config.Naming.PathTemplate,
+		AuditChain:               config.Audit.Chain,
+		RequireApproval:          config.Promotion.RequireApproval,
+		RequireConfirm:           config.Promotion.RequireConfirm,
+		RetryCount:               config.Defaults.RetryCount,
+		LogLevel:                 config.Defaults.LogLevel,
+		ImmutableTags:            immutableTags,
+		FallbackTag:              fallbackTag,
+	}
+
+	sources := &ConfigSources{
+		Bucket:    bucketSource,
+		Region:    regionSource,
+		Endpoint:  endpointSource,
+		AccessKey: accessKeySource,
+		SecretKey: secretKeySource,
 	}
 
-	return resolved, nil
+	return resolved, sources, nil
 }
 
-func resolveBucket(override, configValue string) string {
+func resolveBucket(override, configValue string) (string, ConfigSource) {
 	if override != "" {
-		return override
+		return override, SourceFlag
 	}
 	if env := os.Getenv("S3DOCK_BUCKET"); env != "" {
-		return env
+		return env, SourceEnv
 	}
 	if configValue != "" {
-		return configValue
+		return configValue, SourceConfig
 	}
-	return "s3dock-containers"
+	return "s3dock-containers", SourceDefault
 }
 
-func resolveRegion(configValue string) string {
+func resolveRegion(configValue string) (string, ConfigSource) {
 	if env := os.Getenv("AWS_REGION"); env != "" {
-		return env
+		return env, SourceEnv
 	}
 	if configValue != "" {
-		return configValue
+		return configValue, SourceConfig
 	}
-	return "us-east-1"
+	return "us-east-1", SourceDefault
 }
 
-func resolveEndpoint(configValue string) string {
+func resolveEndpoint(configValue string) (string, ConfigSource) {
 	if env := os.Getenv("AWS_ENDPOINT_URL"); env != "" {
-		return env
+		return env, SourceEnv
 	}
-	return configValue
+	return configValue, SourceConfig
 }
 
-func resolveAccessKey(configValue string) string {
+func resolveAccessKey(configValue string) (string, ConfigSource) {
 	if env := os.Getenv("AWS_ACCESS_KEY_ID"); env != "" {
-		return env
+		return env, SourceEnv
 	}
-	return configValue
+	return configValue, SourceConfig
 }
 
-func resolveSecretKey(configValue string) string {
+func resolveSecretKey(configValue string) (string, ConfigSource) {
 	if env := os.Getenv("AWS_SECRET_ACCESS_KEY"); env != "" {
-		return env
+		return env, SourceEnv
+	}
+	return configValue, SourceConfig
+}
+
+// MaskSecret redacts a credential for display (config show), keeping only the
+// last four characters so a key can still be identified when output is pasted
+// into issues or chat. Short values are fully masked.
+func MaskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= 8 {
+		return "***"
 	}
-	return configValue
+	return "***" + value[len(value)-4:]
 }
 
 func (c *Config) GetProfileNames() []string {
@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ConfirmPrompter asks the operator to confirm a destructive action. The CLI
+// implementation reads from stdin; tests substitute a canned answer.
+type ConfirmPrompter interface {
+	Confirm(prompt string) (bool, error)
+}
+
+// StdinPrompter is the interactive y/N prompt used by promote --confirm
+type StdinPrompter struct {
+	In  io.Reader
+	Out io.Writer
+}
+
+func (p *StdinPrompter) Confirm(prompt string) (bool, error) {
+	fmt.Fprintf(p.Out, "%s [y/N]: ", prompt)
+
+	scanner := bufio.NewScanner(p.In)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return false, fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		return false, nil // EOF counts as a decline
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	return answer == "y" || answer == "yes", nil
+}
+
+// ConfirmPromotion shows what an environment currently points to alongside
+// the proposed new target and asks for confirmation (promote --confirm).
+// Returns false when the operator declines.
+func ConfirmPromotion(ctx context.Context, s3 S3Client, bucket, appName, environment, newTarget string, prompter ConfirmPrompter) (bool, error) {
+	current, err := NewCurrentService(s3, bucket).GetCurrentImage(ctx, appName, environment)
+	if err != nil {
+		if !errors.Is(err, ErrEnvironmentNotFound) {
+			return false, fmt.Errorf("failed to resolve current target for %s/%s: %w", appName, environment, err)
+		}
+		current = "(none)"
+	}
+
+	prompt := fmt.Sprintf("Promote to %s/%s\n  current: %s\n  new:     %s\nProceed?", appName, environment, current, newTarget)
+	return prompter.Confirm(prompt)
+}
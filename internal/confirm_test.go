@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdinPrompter_Confirm(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected bool
+	}{
+		{"y\n", true},
+		{"yes\n", true},
+		{"Y\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false}, // EOF declines
+	}
+
+	for _, tc := range cases {
+		var out bytes.Buffer
+		prompter := &StdinPrompter{In: strings.NewReader(tc.input), Out: &out}
+
+		ok, err := prompter.Confirm("Proceed?")
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, ok, "input %q", tc.input)
+		assert.Contains(t, out.String(), "[y/N]")
+	}
+}
+
+// stubPrompter records the prompt it was shown and returns a canned answer
+type stubPrompter struct {
+	answer bool
+	prompt string
+}
+
+func (p *stubPrompter) Confirm(prompt string) (bool, error) {
+	p.prompt = prompt
+	return p.answer, nil
+}
+
+func TestConfirmPromotion_ShowsCurrentAndNewTarget(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+	mock.files[GeneratePointerKey("myapp", "production")] = []byte(`{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		"source_image": "myapp:20250721-2118-f7a5a27"
+	}`)
+
+	prompter := &stubPrompter{answer: true}
+
+	ok, err := ConfirmPromotion(ctx, mock, "test-bucket", "myapp", "production", "myapp:20250722-0900-def5678", prompter)
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Contains(t, prompter.prompt, "myapp:20250721-2118-f7a5a27")
+	assert.Contains(t, prompter.prompt, "myapp:20250722-0900-def5678")
+}
+
+func TestConfirmPromotion_Abort(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	prompter := &stubPrompter{answer: false}
+
+	ok, err := ConfirmPromotion(ctx, mock, "test-bucket", "myapp", "production", "myapp:20250722-0900-def5678", prompter)
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, prompter.prompt, "(none)", "a missing environment should show as (none)")
+}
@@ -2,11 +2,17 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
 )
 
+// ErrEnvironmentNotFound marks "environment isn't configured" errors so
+// callers (current --exit-code handling) can distinguish them from transport
+// failures without parsing error strings.
+var ErrEnvironmentNotFound = errors.New("environment pointer not found")
+
 type CurrentService struct {
 	s3     S3Client
 	bucket string
@@ -23,6 +29,40 @@ func NewCurrentService(s3 S3Client, bucket string) *CurrentService {
 func (c *CurrentService) GetCurrentImage(ctx context.Context, appName, environment string) (string, error) {
 	LogInfo("Getting current image for %s in %s environment", appName, environment)
 
+	imageS3Path, err := c.resolveEnvironmentImagePath(ctx, appName, environment)
+	if err != nil {
+		return "", err
+	}
+
+	// Extract image reference from S3 path
+	imageRef, err := c.extractImageReferenceFromPath(imageS3Path)
+	if err != nil {
+		LogError("Failed to extract image reference from path: %v", err)
+		return "", fmt.Errorf("failed to extract image reference from path: %w", err)
+	}
+
+	LogInfo("Current image for %s in %s: %s", appName, environment, imageRef)
+	return imageRef, nil
+}
+
+// GetCurrentImagePath retrieves the concrete S3 object key behind an
+// environment pointer (current --format s3path), resolving any tag
+// indirection along the way.
+func (c *CurrentService) GetCurrentImagePath(ctx context.Context, appName, environment string) (string, error) {
+	LogInfo("Getting current image path for %s in %s environment", appName, environment)
+
+	imageS3Path, err := c.resolveEnvironmentImagePath(ctx, appName, environment)
+	if err != nil {
+		return "", err
+	}
+
+	LogInfo("Current image path for %s in %s: %s", appName, environment, imageS3Path)
+	return imageS3Path, nil
+}
+
+// resolveEnvironmentImagePath follows an environment pointer down to the
+// S3 path of the concrete image archive
+func (c *CurrentService) resolveEnvironmentImagePath(ctx context.Context, appName, environment string) (string, error) {
 	// Get environment pointer
 	envKey := GeneratePointerKey(appName, environment)
 	LogDebug("Looking for environment pointer at: %s", envKey)
@@ -35,7 +75,7 @@ func (c *CurrentService) GetCurrentImage(ctx context.Context, appName, environme
 
 	if !exists {
 		LogError("Environment pointer not found: %s/%s", appName, environment)
-		return "", fmt.Errorf("environment pointer not found: %s/%s", appName, environment)
+		return "", fmt.Errorf("%w: %s/%s", ErrEnvironmentNotFound, appName, environment)
 	}
 
 	// Download environment pointer
@@ -61,20 +101,171 @@ func (c *CurrentService) GetCurrentImage(ctx context.Context, appName, environme
 		return "", fmt.Errorf("failed to resolve image path: %w", err)
 	}
 
-	// Extract image reference from S3 path
-	imageRef, err := c.extractImageReferenceFromPath(imageS3Path)
+	return imageS3Path, nil
+}
+
+// GetImageMetadata resolves the pointer chain for a target (environment or
+// version tag) and returns the stored image metadata without downloading the
+// image itself (pull --only-metadata).
+func (c *CurrentService) GetImageMetadata(ctx context.Context, appName, target string) (*ImageMetadata, error) {
+	LogInfo("Getting image metadata for %s/%s", appName, target)
+
+	// Environments and tags live under different prefixes; try the
+	// environment pointer first, then fall back to a tag
+	pointerKey := GeneratePointerKey(appName, target)
+	exists, err := c.s3.Exists(ctx, c.bucket, pointerKey)
 	if err != nil {
-		LogError("Failed to extract image reference from path: %v", err)
-		return "", fmt.Errorf("failed to extract image reference from path: %w", err)
+		LogError("Failed to check environment pointer existence: %v", err)
+		return nil, fmt.Errorf("failed to check environment pointer existence: %w", err)
 	}
 
-	LogInfo("Current image for %s in %s: %s", appName, environment, imageRef)
-	return imageRef, nil
+	if !exists {
+		pointerKey = GenerateTagKey(appName, target)
+		exists, err = c.s3.Exists(ctx, c.bucket, pointerKey)
+		if err != nil {
+			LogError("Failed to check tag existence: %v", err)
+			return nil, fmt.Errorf("failed to check tag existence: %w", err)
+		}
+		if !exists {
+			LogError("No environment or tag named %s for %s", target, appName)
+			return nil, fmt.Errorf("no environment or tag named %s for %s", target, appName)
+		}
+	}
+
+	LogDebug("Downloading pointer from: %s", pointerKey)
+	pointerData, err := c.s3.Download(ctx, c.bucket, pointerKey)
+	if err != nil {
+		LogError("Failed to download pointer: %v", err)
+		return nil, fmt.Errorf("failed to download pointer: %w", err)
+	}
+
+	pointer, err := PointerMetadataFromJSON(pointerData)
+	if err != nil {
+		LogError("Failed to parse pointer: %v", err)
+		return nil, fmt.Errorf("failed to parse pointer: %w", err)
+	}
+
+	imageS3Path, err := ResolveImagePath(ctx, c.s3, c.bucket, pointer)
+	if err != nil {
+		LogError("Failed to resolve image path: %v", err)
+		return nil, fmt.Errorf("failed to resolve image path: %w", err)
+	}
+
+	metadataKey := GenerateMetadataKey(imageS3Path)
+	LogDebug("Downloading image metadata from: %s", metadataKey)
+	metadataData, err := c.s3.Download(ctx, c.bucket, metadataKey)
+	if err != nil {
+		LogError("Failed to download image metadata: %v", err)
+		return nil, fmt.Errorf("failed to download image metadata: %w", err)
+	}
+
+	metadata, err := ImageMetadataFromJSON(metadataData)
+	if err != nil {
+		LogError("Failed to parse image metadata: %v", err)
+		return nil, fmt.Errorf("failed to parse image metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// Sources reported by GetCurrentImageWithFallback
+const (
+	CurrentSourceEnvironment = "environment"
+	CurrentSourceTag         = "tag"
+	CurrentSourceLatest      = "latest"
+)
+
+// GetCurrentImageWithFallback resolves the current image for an environment,
+// falling back to the default tag and then to the newest pushed image when
+// the environment pointer does not exist. It returns the image reference and
+// which source in the chain resolved it. This smooths first-deploy scenarios
+// where nothing has been promoted yet.
+func (c *CurrentService) GetCurrentImageWithFallback(ctx context.Context, appName, environment, fallbackTag string) (string, string, error) {
+	envKey := GeneratePointerKey(appName, environment)
+	exists, err := c.s3.Exists(ctx, c.bucket, envKey)
+	if err != nil {
+		LogError("Failed to check environment pointer existence: %v", err)
+		return "", "", fmt.Errorf("failed to check environment pointer existence: %w", err)
+	}
+
+	if exists {
+		imageRef, err := c.GetCurrentImage(ctx, appName, environment)
+		if err != nil {
+			return "", "", err
+		}
+		return imageRef, CurrentSourceEnvironment, nil
+	}
+
+	LogInfo("Environment pointer %s/%s not found, trying fallback tag %s", appName, environment, fallbackTag)
+	imageRef, err := c.imageFromTag(ctx, appName, fallbackTag)
+	if err == nil {
+		return imageRef, CurrentSourceTag, nil
+	}
+	LogDebug("Fallback tag %s did not resolve: %v", fallbackTag, err)
+
+	LogInfo("Falling back to latest pushed image for %s", appName)
+	imageRef, err = c.latestImage(ctx, appName)
+	if err != nil {
+		LogError("No fallback source resolved for %s/%s: %v", appName, environment, err)
+		return "", "", fmt.Errorf("no image found for %s/%s via environment pointer, tag '%s', or latest image: %w", appName, environment, fallbackTag, err)
+	}
+	return imageRef, CurrentSourceLatest, nil
+}
+
+// imageFromTag resolves a semantic version tag pointer to an image reference
+func (c *CurrentService) imageFromTag(ctx context.Context, appName, version string) (string, error) {
+	tagKey := GenerateTagKey(appName, version)
+
+	exists, err := c.s3.Exists(ctx, c.bucket, tagKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to check tag existence: %w", err)
+	}
+	if !exists {
+		return "", fmt.Errorf("tag not found: %s/%s", appName, version)
+	}
+
+	pointerData, err := c.s3.Download(ctx, c.bucket, tagKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to download tag pointer: %w", err)
+	}
+
+	pointer, err := PointerMetadataFromJSON(pointerData)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse tag pointer: %w", err)
+	}
+
+	imageS3Path, err := ResolveImagePath(ctx, c.s3, c.bucket, pointer)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve image path: %w", err)
+	}
+
+	return imageReferenceFromPath(imageS3Path)
+}
+
+// latestImage returns the most recently pushed image for an app
+func (c *CurrentService) latestImage(ctx context.Context, appName string) (string, error) {
+	list := NewListService(c.s3, c.bucket)
+	images, err := list.ListImages(ctx, appName, "")
+	if err != nil {
+		return "", err
+	}
+	if len(images) == 0 {
+		return "", fmt.Errorf("no images found for %s", appName)
+	}
+
+	// ListImages sorts newest first
+	return imageReferenceFromPath(images[0].S3Path)
 }
 
 // extractImageReferenceFromPath converts an S3 image path to an image reference
 // Example: images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz -> myapp:20250721-1430-abc1234
 func (c *CurrentService) extractImageReferenceFromPath(s3Path string) (string, error) {
+	return imageReferenceFromPath(s3Path)
+}
+
+// imageReferenceFromPath converts an S3 image path to an image reference
+// Example: images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz -> myapp:20250721-1430-abc1234
+func imageReferenceFromPath(s3Path string) (string, error) {
 	// Validate that the path ends with .tar.gz
 	if !strings.HasSuffix(s3Path, ".tar.gz") {
 		return "", fmt.Errorf("invalid image path format: must end with .tar.gz")
@@ -101,6 +101,81 @@ func TestGetCurrentImage_Success_TagPointer(t *testing.T) {
 	mockS3.AssertExpectations(t)
 }
 
+func TestGetCurrentImagePath_ImagePointer(t *testing.T) {
+	mockS3 := &MockS3Client{}
+	bucket := "test-bucket"
+	service := NewCurrentService(mockS3, bucket)
+
+	appName := "myapp"
+	environment := "production"
+	envKey := GeneratePointerKey(appName, environment)
+
+	pointer := &PointerMetadata{
+		TargetType: TargetTypeImage,
+		TargetPath: "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz",
+		PromotedAt: time.Now(),
+		PromotedBy: "testuser",
+		GitHash:    "abc1234",
+		GitTime:    "20250721-1430",
+	}
+
+	pointerData, _ := json.Marshal(pointer)
+
+	mockS3.On("Exists", mock.Anything, bucket, envKey).Return(true, nil)
+	mockS3.On("Download", mock.Anything, bucket, envKey).Return(pointerData, nil)
+
+	ctx := context.Background()
+	s3Path, err := service.GetCurrentImagePath(ctx, appName, environment)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz", s3Path)
+	mockS3.AssertExpectations(t)
+}
+
+func TestGetCurrentImagePath_TagPointer(t *testing.T) {
+	mockS3 := &MockS3Client{}
+	bucket := "test-bucket"
+	service := NewCurrentService(mockS3, bucket)
+
+	appName := "myapp"
+	environment := "production"
+	envKey := GeneratePointerKey(appName, environment)
+	tagKey := "tags/myapp/v1.2.0.json"
+
+	// Environment pointer goes through a tag before reaching the image
+	envPointer := &PointerMetadata{
+		TargetType: TargetTypeTag,
+		TargetPath: tagKey,
+		PromotedAt: time.Now(),
+		PromotedBy: "testuser",
+		GitHash:    "abc1234",
+		GitTime:    "20250721-1430",
+	}
+
+	tagPointer := &PointerMetadata{
+		TargetType: TargetTypeImage,
+		TargetPath: "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz",
+		PromotedAt: time.Now(),
+		PromotedBy: "testuser",
+		GitHash:    "abc1234",
+		GitTime:    "20250721-1430",
+	}
+
+	envPointerData, _ := json.Marshal(envPointer)
+	tagPointerData, _ := json.Marshal(tagPointer)
+
+	mockS3.On("Exists", mock.Anything, bucket, envKey).Return(true, nil)
+	mockS3.On("Download", mock.Anything, bucket, envKey).Return(envPointerData, nil)
+	mockS3.On("Download", mock.Anything, bucket, tagKey).Return(tagPointerData, nil)
+
+	ctx := context.Background()
+	s3Path, err := service.GetCurrentImagePath(ctx, appName, environment)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz", s3Path)
+	mockS3.AssertExpectations(t)
+}
+
 func TestGetCurrentImage_EnvironmentNotFound(t *testing.T) {
 	mockS3 := &MockS3Client{}
 	bucket := "test-bucket"
@@ -118,6 +193,7 @@ func TestGetCurrentImage_EnvironmentNotFound(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "environment pointer not found")
+	assert.True(t, errors.Is(err, ErrEnvironmentNotFound))
 	assert.Empty(t, imageRef)
 	mockS3.AssertExpectations(t)
 }
@@ -293,3 +369,193 @@ func TestExtractImageReferenceFromPath_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCurrentImageWithFallback_EnvironmentPointer(t *testing.T) {
+	mockS3 := &MockS3Client{}
+	bucket := "test-bucket"
+	service := NewCurrentService(mockS3, bucket)
+
+	appName := "myapp"
+	environment := "production"
+	envKey := GeneratePointerKey(appName, environment)
+
+	pointer := &PointerMetadata{
+		TargetType: TargetTypeImage,
+		TargetPath: "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz",
+		PromotedAt: time.Now(),
+		PromotedBy: "testuser",
+		GitHash:    "abc1234",
+		GitTime:    "20250721-1430",
+	}
+	pointerData, _ := json.Marshal(pointer)
+
+	mockS3.On("Exists", mock.Anything, bucket, envKey).Return(true, nil)
+	mockS3.On("Download", mock.Anything, bucket, envKey).Return(pointerData, nil)
+
+	ctx := context.Background()
+	imageRef, source, err := service.GetCurrentImageWithFallback(ctx, appName, environment, "latest")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:20250721-1430-abc1234", imageRef)
+	assert.Equal(t, CurrentSourceEnvironment, source)
+	mockS3.AssertExpectations(t)
+}
+
+func TestGetCurrentImageWithFallback_Tag(t *testing.T) {
+	mockS3 := &MockS3Client{}
+	bucket := "test-bucket"
+	service := NewCurrentService(mockS3, bucket)
+
+	appName := "myapp"
+	environment := "production"
+	envKey := GeneratePointerKey(appName, environment)
+	tagKey := GenerateTagKey(appName, "latest")
+
+	pointer := &PointerMetadata{
+		TargetType: TargetTypeImage,
+		TargetPath: "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz",
+		PromotedAt: time.Now(),
+		PromotedBy: "testuser",
+		GitHash:    "abc1234",
+		GitTime:    "20250721-1430",
+	}
+	pointerData, _ := json.Marshal(pointer)
+
+	mockS3.On("Exists", mock.Anything, bucket, envKey).Return(false, nil)
+	mockS3.On("Exists", mock.Anything, bucket, tagKey).Return(true, nil)
+	mockS3.On("Download", mock.Anything, bucket, tagKey).Return(pointerData, nil)
+
+	ctx := context.Background()
+	imageRef, source, err := service.GetCurrentImageWithFallback(ctx, appName, environment, "latest")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:20250721-1430-abc1234", imageRef)
+	assert.Equal(t, CurrentSourceTag, source)
+	mockS3.AssertExpectations(t)
+}
+
+func TestGetCurrentImageWithFallback_LatestImage(t *testing.T) {
+	mockS3 := &MockS3Client{}
+	bucket := "test-bucket"
+	service := NewCurrentService(mockS3, bucket)
+
+	appName := "myapp"
+	environment := "production"
+	envKey := GeneratePointerKey(appName, environment)
+	tagKey := GenerateTagKey(appName, "latest")
+
+	mockS3.On("Exists", mock.Anything, bucket, envKey).Return(false, nil)
+	mockS3.On("Exists", mock.Anything, bucket, tagKey).Return(false, nil)
+	mockS3.On("List", mock.Anything, bucket, "images/myapp/").Return([]string{
+		"images/myapp/202507/myapp-20250720-0900-0ld1234.tar.gz",
+		"images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz",
+		"images/myapp/202507/myapp-20250721-1430-abc1234.json",
+	}, nil)
+
+	ctx := context.Background()
+	imageRef, source, err := service.GetCurrentImageWithFallback(ctx, appName, environment, "latest")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:20250721-1430-abc1234", imageRef)
+	assert.Equal(t, CurrentSourceLatest, source)
+	mockS3.AssertExpectations(t)
+}
+
+func TestGetCurrentImageWithFallback_NothingResolves(t *testing.T) {
+	mockS3 := &MockS3Client{}
+	bucket := "test-bucket"
+	service := NewCurrentService(mockS3, bucket)
+
+	appName := "myapp"
+	environment := "production"
+	envKey := GeneratePointerKey(appName, environment)
+	tagKey := GenerateTagKey(appName, "latest")
+
+	mockS3.On("Exists", mock.Anything, bucket, envKey).Return(false, nil)
+	mockS3.On("Exists", mock.Anything, bucket, tagKey).Return(false, nil)
+	mockS3.On("List", mock.Anything, bucket, "images/myapp/").Return([]string{}, nil)
+
+	ctx := context.Background()
+	imageRef, source, err := service.GetCurrentImageWithFallback(ctx, appName, environment, "latest")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no image found")
+	assert.Empty(t, imageRef)
+	assert.Empty(t, source)
+	mockS3.AssertExpectations(t)
+}
+
+func TestGetImageMetadata_EnvironmentTarget(t *testing.T) {
+	mockS3 := &MockS3Client{}
+	bucket := "test-bucket"
+	service := NewCurrentService(mockS3, bucket)
+
+	appName := "myapp"
+	envKey := GeneratePointerKey(appName, "production")
+	imagePath := "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz"
+
+	pointer := &PointerMetadata{
+		TargetType: TargetTypeImage,
+		TargetPath: imagePath,
+		GitHash:    "abc1234",
+		GitTime:    "20250721-1430",
+	}
+	pointerData, _ := json.Marshal(pointer)
+
+	metadata := &ImageMetadata{
+		Checksum: "check-sum",
+		Size:     42,
+		GitHash:  "abc1234",
+	}
+	metadataData, _ := metadata.ToJSON()
+
+	mockS3.On("Exists", mock.Anything, bucket, envKey).Return(true, nil)
+	mockS3.On("Download", mock.Anything, bucket, envKey).Return(pointerData, nil)
+	mockS3.On("Download", mock.Anything, bucket, GenerateMetadataKey(imagePath)).Return(metadataData, nil)
+
+	ctx := context.Background()
+	result, err := service.GetImageMetadata(ctx, appName, "production")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "check-sum", result.Checksum)
+	assert.Equal(t, int64(42), result.Size)
+	mockS3.AssertExpectations(t)
+}
+
+func TestGetImageMetadata_TagTarget(t *testing.T) {
+	mockS3 := &MockS3Client{}
+	bucket := "test-bucket"
+	service := NewCurrentService(mockS3, bucket)
+
+	appName := "myapp"
+	envKey := GeneratePointerKey(appName, "v1.2.0")
+	tagKey := GenerateTagKey(appName, "v1.2.0")
+	imagePath := "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz"
+
+	pointer := &PointerMetadata{
+		TargetType: TargetTypeImage,
+		TargetPath: imagePath,
+		GitHash:    "abc1234",
+		GitTime:    "20250721-1430",
+	}
+	pointerData, _ := json.Marshal(pointer)
+
+	metadata := &ImageMetadata{
+		Checksum: "check-sum",
+		Size:     42,
+		GitHash:  "abc1234",
+	}
+	metadataData, _ := metadata.ToJSON()
+
+	mockS3.On("Exists", mock.Anything, bucket, envKey).Return(false, nil)
+	mockS3.On("Exists", mock.Anything, bucket, tagKey).Return(true, nil)
+	mockS3.On("Download", mock.Anything, bucket, tagKey).Return(pointerData, nil)
+	mockS3.On("Download", mock.Anything, bucket, GenerateMetadataKey(imagePath)).Return(metadataData, nil)
+
+	ctx := context.Background()
+	result, err := service.GetImageMetadata(ctx, appName, "v1.2.0")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "check-sum", result.Checksum)
+	mockS3.AssertExpectations(t)
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package internal
+
+import (
+	"syscall"
+)
+
+// availableDiskSpace returns the number of bytes available to the current
+// user on the filesystem containing path.
+func availableDiskSpace(path string) (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
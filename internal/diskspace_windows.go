@@ -0,0 +1,21 @@
+//go:build windows
+
+package internal
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// availableDiskSpace returns the number of bytes available to the current
+// user on the filesystem containing path.
+func availableDiskSpace(path string) (int64, error) {
+	var freeBytesAvailable uint64
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	if err := windows.GetDiskFreeSpaceEx(pathPtr, &freeBytesAvailable, nil, nil); err != nil {
+		return 0, err
+	}
+	return int64(freeBytesAvailable), nil
+}
@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -12,11 +13,18 @@ import (
 	"strings"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/client"
 )
 
 type DockerClientImpl struct {
-	client *client.Client
+	client        *client.Client
+	buildkit      bool     // Route native-platform builds through buildx/BuildKit
+	cacheFrom     []string // BuildKit cache import sources
+	cacheTo       []string // BuildKit cache export destinations
+	derefSymlinks bool     // Include symlink targets in the build context
+	ignoreFile    string   // Alternate ignore file (build --dockerignore)
+	buildLog      string   // Output captured from the most recent SDK build
 }
 
 func NewDockerClient() (*DockerClientImpl, error) {
@@ -28,14 +36,44 @@ func NewDockerClient() (*DockerClientImpl, error) {
 	return &DockerClientImpl{client: cli}, nil
 }
 
-// readDockerignore reads and parses .dockerignore patterns
-func readDockerignore(contextPath string) ([]string, error) {
-	dockerignorePath := filepath.Join(contextPath, ".dockerignore")
+// UseBuildKit routes all builds through docker buildx (BuildKit), which
+// supports secrets, cache mounts, and registry cache import/export. The
+// legacy SDK builder stays the default for compatibility; this is selected
+// via docker.builder = "buildkit" in the config.
+func (d *DockerClientImpl) UseBuildKit(cacheFrom, cacheTo []string) {
+	d.buildkit = true
+	d.cacheFrom = cacheFrom
+	d.cacheTo = cacheTo
+}
+
+// SetDereferenceSymlinks makes the build context walker resolve symlinks and
+// tar their targets' contents instead of skipping them. Symlinks are skipped
+// by default because sending them as links often breaks builds.
+func (d *DockerClientImpl) SetDereferenceSymlinks(deref bool) {
+	d.derefSymlinks = deref
+}
 
-	file, err := os.Open(dockerignorePath)
+// SetIgnoreFile points the build context walker at an alternate ignore file
+// (build --dockerignore) instead of .dockerignore in the context root, for
+// monorepos with per-service ignore files.
+func (d *DockerClientImpl) SetIgnoreFile(path string) {
+	d.ignoreFile = path
+}
+
+// readDockerignore reads and parses .dockerignore patterns from the context root
+func readDockerignore(contextPath string) ([]string, error) {
+	patterns, err := readDockerignoreFile(filepath.Join(contextPath, ".dockerignore"))
 	if os.IsNotExist(err) {
 		return nil, nil // .dockerignore doesn't exist
 	}
+	return patterns, err
+}
+
+// readDockerignoreFile parses ignore patterns from an explicit file path.
+// Unlike the default .dockerignore lookup, a missing file is an error here:
+// the caller asked for that specific file.
+func readDockerignoreFile(path string) ([]string, error) {
+	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +99,7 @@ func shouldIgnore(path string, patterns []string) bool {
 	for _, pattern := range patterns {
 		// Normalize pattern
 		normalizedPattern := strings.ReplaceAll(pattern, string(os.PathSeparator), "/")
-		
+
 		// Handle directory patterns (ending with /)
 		if strings.HasSuffix(normalizedPattern, "/") {
 			dirPattern := strings.TrimSuffix(normalizedPattern, "/")
@@ -116,6 +154,31 @@ func (d *DockerClientImpl) ImportImage(ctx context.Context, tarStream io.Reader)
 	return err
 }
 
+// ListImages returns all local image references in repo:tag form. Untagged
+// (dangling) images are skipped since they can't match the s3dock naming scheme.
+func (d *DockerClientImpl) ListImages(ctx context.Context) ([]string, error) {
+	images, err := d.client.ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []string
+	for _, img := range images {
+		for _, tag := range img.RepoTags {
+			if tag == "<none>:<none>" {
+				continue
+			}
+			refs = append(refs, tag)
+		}
+	}
+	return refs, nil
+}
+
+func (d *DockerClientImpl) RemoveImage(ctx context.Context, imageRef string) error {
+	_, err := d.client.ImageRemove(ctx, imageRef, image.RemoveOptions{})
+	return err
+}
+
 func (d *DockerClientImpl) ImageExists(ctx context.Context, imageRef string) (bool, error) {
 	_, _, err := d.client.ImageInspectWithRaw(ctx, imageRef)
 	if err != nil {
@@ -127,7 +190,44 @@ func (d *DockerClientImpl) ImageExists(ctx context.Context, imageRef string) (bo
 	return true, nil
 }
 
-func (d *DockerClientImpl) BuildImage(ctx context.Context, contextPath string, dockerfile string, tags []string, platform string) error {
+func (d *DockerClientImpl) ImageID(ctx context.Context, imageRef string) (string, error) {
+	inspect, _, err := d.client.ImageInspectWithRaw(ctx, imageRef)
+	if err != nil {
+		return "", err
+	}
+	return inspect.ID, nil
+}
+
+// ImageTags returns all repo:tag references attached to the image.
+func (d *DockerClientImpl) ImageTags(ctx context.Context, imageRef string) ([]string, error) {
+	inspect, _, err := d.client.ImageInspectWithRaw(ctx, imageRef)
+	if err != nil {
+		return nil, err
+	}
+	return inspect.RepoTags, nil
+}
+
+// ImageArchitecture returns the image's platform as os/arch (e.g. linux/amd64).
+func (d *DockerClientImpl) ImageArchitecture(ctx context.Context, imageRef string) (string, error) {
+	inspect, _, err := d.client.ImageInspectWithRaw(ctx, imageRef)
+	if err != nil {
+		return "", err
+	}
+	return inspect.Os + "/" + inspect.Architecture, nil
+}
+
+func (d *DockerClientImpl) TagImage(ctx context.Context, sourceRef, targetRef string) error {
+	return d.client.ImageTag(ctx, sourceRef, targetRef)
+}
+
+// RemoveTag untags a single reference. Docker only deletes the underlying
+// image once its last tag is removed, so this is safe on multi-tagged images.
+func (d *DockerClientImpl) RemoveTag(ctx context.Context, imageRef string) error {
+	_, err := d.client.ImageRemove(ctx, imageRef, image.RemoveOptions{})
+	return err
+}
+
+func (d *DockerClientImpl) BuildImage(ctx context.Context, contextPath string, dockerfile string, tags []string, platform string, cacheFrom []string, squash, pull bool) error {
 	// Convert dockerfile to absolute path for existence check
 	dockerfilePath := dockerfile
 	if !filepath.IsAbs(dockerfile) {
@@ -151,22 +251,45 @@ func (d *DockerClientImpl) BuildImage(ctx context.Context, contextPath string, d
 	LogDebug("Using dockerfile path relative to context: %s", dockerfileRelative)
 
 	// Use docker buildx for cross-platform builds (when platform is specified)
+	// or when the BuildKit builder is selected via config.
 	// The Docker SDK's ImageBuild doesn't properly handle cross-platform multi-stage builds
-	if platform != "" {
-		return d.buildWithBuildx(ctx, contextPath, dockerfileRelative, tags, platform)
+	if platform != "" || d.buildkit {
+		if squash {
+			return fmt.Errorf("--squash is not supported by the BuildKit builder")
+		}
+		return d.buildWithBuildx(ctx, contextPath, dockerfileRelative, tags, platform, cacheFrom, pull)
 	}
 
 	// Use Docker SDK for native platform builds
-	return d.buildWithSDK(ctx, contextPath, dockerfileRelative, tags)
+	return d.buildWithSDK(ctx, contextPath, dockerfileRelative, tags, cacheFrom, squash, pull)
 }
 
-// buildWithBuildx uses docker buildx CLI for cross-platform builds
-func (d *DockerClientImpl) buildWithBuildx(ctx context.Context, contextPath string, dockerfile string, tags []string, platform string) error {
-	args := []string{"buildx", "build", "--load", "--platform", platform, "-f", dockerfile}
+// buildxArgs assembles the docker buildx argument list for a build
+func buildxArgs(dockerfile string, tags []string, platform string, cacheFrom, cacheTo []string, pull bool) []string {
+	args := []string{"buildx", "build", "--load"}
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+	if pull {
+		args = append(args, "--pull")
+	}
+	args = append(args, "-f", dockerfile)
 	for _, tag := range tags {
 		args = append(args, "-t", tag)
 	}
-	args = append(args, ".")
+	for _, cache := range cacheFrom {
+		args = append(args, "--cache-from", cache)
+	}
+	for _, cache := range cacheTo {
+		args = append(args, "--cache-to", cache)
+	}
+	return append(args, ".")
+}
+
+// buildWithBuildx uses docker buildx CLI for cross-platform and BuildKit builds
+func (d *DockerClientImpl) buildWithBuildx(ctx context.Context, contextPath string, dockerfile string, tags []string, platform string, cacheFrom []string, pull bool) error {
+	combinedCacheFrom := append(append([]string{}, d.cacheFrom...), cacheFrom...)
+	args := buildxArgs(dockerfile, tags, platform, combinedCacheFrom, d.cacheTo, pull)
 
 	LogDebug("Running: docker %s", strings.Join(args, " "))
 
@@ -179,7 +302,7 @@ func (d *DockerClientImpl) buildWithBuildx(ctx context.Context, contextPath stri
 }
 
 // buildWithSDK uses Docker SDK for native platform builds
-func (d *DockerClientImpl) buildWithSDK(ctx context.Context, contextPath string, dockerfile string, tags []string) error {
+func (d *DockerClientImpl) buildWithSDK(ctx context.Context, contextPath string, dockerfile string, tags []string, cacheFrom []string, squash, pull bool) error {
 	tarReader, err := d.createBuildContext(contextPath)
 	if err != nil {
 		return err
@@ -189,6 +312,9 @@ func (d *DockerClientImpl) buildWithSDK(ctx context.Context, contextPath string,
 	buildOptions := types.ImageBuildOptions{
 		Tags:       tags,
 		Dockerfile: dockerfile,
+		CacheFrom:  cacheFrom,
+		Squash:     squash,
+		PullParent: pull,
 	}
 
 	response, err := d.client.ImageBuild(ctx, tarReader, buildOptions)
@@ -197,18 +323,78 @@ func (d *DockerClientImpl) buildWithSDK(ctx context.Context, contextPath string,
 	}
 	defer response.Body.Close()
 
-	_, err = io.Copy(os.Stdout, response.Body)
+	// Echo build output only in text mode; in JSON mode raw build chatter
+	// would corrupt the machine-readable stream
+	var echo io.Writer
+	if !IsJSONOutput() {
+		echo = os.Stdout
+	}
+	buildLog, err := decodeBuildStream(response.Body, echo)
+	d.buildLog = buildLog
 	return err
 }
 
+// BuildLog returns the output captured from the most recent BuildImage call.
+// Only the SDK builder captures output; buildx writes directly to the
+// terminal and leaves this empty.
+func (d *DockerClientImpl) BuildLog() string {
+	return d.buildLog
+}
+
+// buildStreamMessage is one JSON message from the Docker build response
+type buildStreamMessage struct {
+	Stream string `json:"stream"`
+	Error  string `json:"error"`
+}
+
+// decodeBuildStream parses the Docker build JSON stream, echoing build text
+// to echo (nil suppresses echoing) and returning the captured plain-text
+// log. A failed build step arrives as an error message inside the stream
+// rather than an HTTP error, so it must be surfaced here or the build fails
+// silently.
+func decodeBuildStream(body io.Reader, echo io.Writer) (string, error) {
+	var log strings.Builder
+	decoder := json.NewDecoder(body)
+	for {
+		var msg buildStreamMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return log.String(), fmt.Errorf("failed to parse build output: %w", err)
+		}
+
+		if msg.Stream != "" {
+			log.WriteString(msg.Stream)
+			if echo != nil {
+				fmt.Fprint(echo, msg.Stream)
+			}
+		}
+		if msg.Error != "" {
+			log.WriteString(msg.Error + "\n")
+			return log.String(), fmt.Errorf("build failed: %s", msg.Error)
+		}
+	}
+	return log.String(), nil
+}
+
 func (d *DockerClientImpl) createBuildContext(contextPath string) (io.ReadCloser, error) {
-	// Read .dockerignore patterns
-	patterns, err := readDockerignore(contextPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read .dockerignore: %w", err)
+	// Read ignore patterns, from --dockerignore when set
+	var patterns []string
+	var err error
+	if d.ignoreFile != "" {
+		patterns, err = readDockerignoreFile(d.ignoreFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ignore file %s: %w", d.ignoreFile, err)
+		}
+	} else {
+		patterns, err = readDockerignore(contextPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read .dockerignore: %w", err)
+		}
 	}
 
-	LogDebug("Loaded %d .dockerignore patterns: %v", len(patterns), patterns)
+	LogDebug("Loaded %d ignore patterns: %v", len(patterns), patterns)
 
 	pr, pw := io.Pipe()
 
@@ -218,6 +404,13 @@ func (d *DockerClientImpl) createBuildContext(contextPath string) (io.ReadCloser
 		tw := tar.NewWriter(pw)
 		defer tw.Close()
 
+		// Seed the loop guard with the context root so a symlink pointing
+		// back at the context can't recurse forever
+		visited := map[string]bool{}
+		if real, err := filepath.EvalSymlinks(contextPath); err == nil {
+			visited[real] = true
+		}
+
 		walkCount := 0
 		err := filepath.Walk(contextPath, func(path string, info os.FileInfo, err error) error {
 			walkCount++
@@ -274,10 +467,14 @@ func (d *DockerClientImpl) createBuildContext(contextPath string) (io.ReadCloser
 				return nil
 			}
 
-			// Skip symlinks as they can cause tar issues
+			// Skip symlinks as they can cause tar issues, unless the caller
+			// asked for them to be dereferenced
 			if info.Mode()&os.ModeSymlink != 0 {
-				LogDebug("Skipping symlink: %s", relPath)
-				return nil
+				if !d.derefSymlinks {
+					LogDebug("Skipping symlink: %s", relPath)
+					return nil
+				}
+				return addDereferencedEntry(tw, patterns, relPath, path, visited)
 			}
 
 			header, err := tar.FileInfoHeader(info, "")
@@ -314,6 +511,98 @@ func (d *DockerClientImpl) createBuildContext(contextPath string) (io.ReadCloser
 	return pr, nil
 }
 
+// addDereferencedEntry resolves a symlink and writes its target's contents to
+// the tar under the link's own path. Directory targets are walked recursively;
+// visited tracks resolved paths so symlink loops terminate.
+func addDereferencedEntry(tw *tar.Writer, patterns []string, relPath, linkPath string, visited map[string]bool) error {
+	realPath, err := filepath.EvalSymlinks(linkPath)
+	if err != nil {
+		LogDebug("Skipping broken symlink %s: %v", relPath, err)
+		return nil
+	}
+
+	if visited[realPath] {
+		LogDebug("Skipping symlink loop at %s -> %s", relPath, realPath)
+		return nil
+	}
+	visited[realPath] = true
+
+	info, err := os.Stat(realPath)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return writeTarFile(tw, relPath, realPath, info)
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = relPath
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(realPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		childRel := filepath.Join(relPath, entry.Name())
+		if shouldIgnore(childRel, patterns) || len(childRel) > 90 {
+			continue
+		}
+
+		childPath := filepath.Join(realPath, entry.Name())
+		childInfo, err := os.Lstat(childPath)
+		if err != nil {
+			return err
+		}
+
+		if childInfo.Mode()&os.ModeSymlink != 0 || childInfo.IsDir() {
+			if err := addDereferencedEntry(tw, patterns, childRel, childPath, visited); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if childInfo.Size() > 100*1024*1024 {
+			continue
+		}
+
+		if err := writeTarFile(tw, childRel, childPath, childInfo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeTarFile writes a single regular file into the tar under relPath
+func writeTarFile(tw *tar.Writer, relPath, path string, info os.FileInfo) error {
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = relPath
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(tw, file)
+	return err
+}
+
 func (d *DockerClientImpl) Close() error {
 	return d.client.Close()
 }
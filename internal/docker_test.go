@@ -1,7 +1,13 @@
 package internal
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,3 +38,187 @@ func TestDockerClient_ExportImage(t *testing.T) {
 	_, err = client.ExportImage(context.Background(), "nonexistent:image")
 	assert.Error(t, err)
 }
+
+func TestBuildxArgs_Basic(t *testing.T) {
+	args := buildxArgs("Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "", nil, nil, false)
+
+	assert.Equal(t, []string{
+		"buildx", "build", "--load",
+		"-f", "Dockerfile",
+		"-t", "myapp:20250721-1430-abc1234",
+		".",
+	}, args)
+}
+
+func TestBuildxArgs_PlatformAndCache(t *testing.T) {
+	args := buildxArgs("Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "linux/amd64",
+		[]string{"type=registry,ref=myapp:cache"},
+		[]string{"type=registry,ref=myapp:cache,mode=max"}, false)
+
+	assert.Equal(t, []string{
+		"buildx", "build", "--load",
+		"--platform", "linux/amd64",
+		"-f", "Dockerfile",
+		"-t", "myapp:20250721-1430-abc1234",
+		"--cache-from", "type=registry,ref=myapp:cache",
+		"--cache-to", "type=registry,ref=myapp:cache,mode=max",
+		".",
+	}, args)
+}
+
+// readTarEntries drains a tar stream into a map of entry name to content
+func readTarEntries(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+
+	entries := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+
+		content, err := io.ReadAll(tr)
+		assert.NoError(t, err)
+		entries[header.Name] = string(content)
+	}
+	return entries
+}
+
+func TestCreateBuildContext_DereferenceSymlinks(t *testing.T) {
+	tmp := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmp, "real.txt"), []byte("hello"), 0644)
+	assert.NoError(t, err)
+	err = os.Symlink("real.txt", filepath.Join(tmp, "link.txt"))
+	assert.NoError(t, err)
+
+	d := &DockerClientImpl{derefSymlinks: true}
+	rc, err := d.createBuildContext(tmp)
+	assert.NoError(t, err)
+	defer rc.Close()
+
+	entries := readTarEntries(t, rc)
+	assert.Equal(t, "hello", entries["real.txt"])
+	assert.Equal(t, "hello", entries["link.txt"], "symlink target content should be included")
+}
+
+func TestCreateBuildContext_SkipsSymlinksByDefault(t *testing.T) {
+	tmp := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmp, "real.txt"), []byte("hello"), 0644)
+	assert.NoError(t, err)
+	err = os.Symlink("real.txt", filepath.Join(tmp, "link.txt"))
+	assert.NoError(t, err)
+
+	d := &DockerClientImpl{}
+	rc, err := d.createBuildContext(tmp)
+	assert.NoError(t, err)
+	defer rc.Close()
+
+	entries := readTarEntries(t, rc)
+	assert.Equal(t, "hello", entries["real.txt"])
+	_, found := entries["link.txt"]
+	assert.False(t, found, "symlinks should be skipped unless dereferencing is enabled")
+}
+
+func TestCreateBuildContext_CustomIgnoreFile(t *testing.T) {
+	tmp := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmp, "keep.txt"), []byte("keep"), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmp, "secret.txt"), []byte("secret"), 0644)
+	assert.NoError(t, err)
+
+	// The ignore file lives outside the context, as in a monorepo layout
+	ignoreFile := filepath.Join(t.TempDir(), "service.dockerignore")
+	err = os.WriteFile(ignoreFile, []byte("# service ignores\nsecret.txt\n"), 0644)
+	assert.NoError(t, err)
+
+	d := &DockerClientImpl{ignoreFile: ignoreFile}
+	rc, err := d.createBuildContext(tmp)
+	assert.NoError(t, err)
+	defer rc.Close()
+
+	entries := readTarEntries(t, rc)
+	assert.Equal(t, "keep", entries["keep.txt"])
+	_, found := entries["secret.txt"]
+	assert.False(t, found, "patterns from the custom ignore file should apply")
+}
+
+func TestCreateBuildContext_CustomIgnoreFileMissing(t *testing.T) {
+	tmp := t.TempDir()
+
+	// Unlike the default .dockerignore, an explicitly requested file must exist
+	d := &DockerClientImpl{ignoreFile: filepath.Join(tmp, "nope.dockerignore")}
+	_, err := d.createBuildContext(tmp)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read ignore file")
+}
+
+func TestCreateBuildContext_DereferenceSymlinkLoop(t *testing.T) {
+	tmp := t.TempDir()
+	err := os.Mkdir(filepath.Join(tmp, "dir"), 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmp, "dir", "file.txt"), []byte("inner"), 0644)
+	assert.NoError(t, err)
+	// dir/loop points back at dir, which would recurse forever if unguarded
+	err = os.Symlink("../dir", filepath.Join(tmp, "dir", "loop"))
+	assert.NoError(t, err)
+
+	d := &DockerClientImpl{derefSymlinks: true}
+	rc, err := d.createBuildContext(tmp)
+	assert.NoError(t, err)
+	defer rc.Close()
+
+	entries := readTarEntries(t, rc)
+	assert.Equal(t, "inner", entries["dir/file.txt"])
+}
+
+func TestBuildxArgs_Pull(t *testing.T) {
+	args := buildxArgs("Dockerfile", []string{"myapp:20250721-1430-abc1234"}, "", nil, nil, true)
+
+	assert.Equal(t, []string{
+		"buildx", "build", "--load",
+		"--pull",
+		"-f", "Dockerfile",
+		"-t", "myapp:20250721-1430-abc1234",
+		".",
+	}, args)
+}
+
+func TestDecodeBuildStream_Success(t *testing.T) {
+	stream := `{"stream":"Step 1/2 : FROM alpine\n"}` + "\n" +
+		`{"stream":" ---> abc123\n"}` + "\n" +
+		`{"stream":"Successfully built abc123\n"}` + "\n"
+
+	var echoed bytes.Buffer
+	log, err := decodeBuildStream(strings.NewReader(stream), &echoed)
+
+	assert.NoError(t, err)
+	assert.Contains(t, log, "Step 1/2 : FROM alpine")
+	assert.Contains(t, log, "Successfully built abc123")
+	assert.Equal(t, log, echoed.String())
+}
+
+func TestDecodeBuildStream_BuildError(t *testing.T) {
+	// A failed build step arrives as an error message inside the stream,
+	// not as an HTTP error
+	stream := `{"stream":"Step 1/2 : FROM alpine\n"}` + "\n" +
+		`{"stream":"Step 2/2 : RUN false\n"}` + "\n" +
+		`{"errorDetail":{"code":1,"message":"The command '/bin/sh -c false' returned a non-zero code: 1"},"error":"The command '/bin/sh -c false' returned a non-zero code: 1"}` + "\n"
+
+	log, err := decodeBuildStream(strings.NewReader(stream), nil)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "returned a non-zero code: 1")
+	assert.Contains(t, log, "Step 2/2 : RUN false")
+	assert.Contains(t, log, "returned a non-zero code: 1")
+}
+
+func TestDecodeBuildStream_NoEcho(t *testing.T) {
+	stream := `{"stream":"quiet output\n"}` + "\n"
+
+	log, err := decodeBuildStream(strings.NewReader(stream), nil)
+
+	assert.NoError(t, err)
+	assert.Contains(t, log, "quiet output")
+}
@@ -1,7 +1,10 @@
 package internal
 
 import (
+	"path/filepath"
+
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 )
 
 type GitClientImpl struct{}
@@ -16,7 +19,7 @@ func (g *GitClientImpl) GetCurrentHash(path string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	
+
 	repo, err := git.PlainOpen(repoRoot)
 	if err != nil {
 		return "", err
@@ -36,7 +39,7 @@ func (g *GitClientImpl) GetCommitTimestamp(path string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	
+
 	repo, err := git.PlainOpen(repoRoot)
 	if err != nil {
 		return "", err
@@ -55,6 +58,80 @@ func (g *GitClientImpl) GetCommitTimestamp(path string) (string, error) {
 	return commit.Committer.When.Format("20060102-1504"), nil
 }
 
+// GetTreeHash returns the git tree hash of subdir at HEAD. The tree hash
+// changes only when the subtree's content changes, so it tells whether a path
+// was touched between two commits regardless of unrelated history.
+func (g *GitClientImpl) GetTreeHash(path, subdir string) (string, error) {
+	repoRoot, err := g.FindRepositoryRoot(path)
+	if err != nil {
+		return "", err
+	}
+
+	repo, err := git.PlainOpen(repoRoot)
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return treeHashAt(repo, repoRoot, ref.Hash(), subdir)
+}
+
+// GetTreeHashAtCommit returns the git tree hash of subdir at the given commit.
+// commitHash may be abbreviated, matching the short hashes stored in metadata.
+func (g *GitClientImpl) GetTreeHashAtCommit(path, subdir, commitHash string) (string, error) {
+	repoRoot, err := g.FindRepositoryRoot(path)
+	if err != nil {
+		return "", err
+	}
+
+	repo, err := git.PlainOpen(repoRoot)
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(commitHash))
+	if err != nil {
+		return "", err
+	}
+
+	return treeHashAt(repo, repoRoot, *hash, subdir)
+}
+
+func treeHashAt(repo *git.Repository, repoRoot string, commitHash plumbing.Hash, subdir string) (string, error) {
+	commit, err := repo.CommitObject(commitHash)
+	if err != nil {
+		return "", err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", err
+	}
+
+	if subdir != "" {
+		abs, err := filepath.Abs(subdir)
+		if err != nil {
+			return "", err
+		}
+		rel, err := filepath.Rel(repoRoot, abs)
+		if err != nil {
+			return "", err
+		}
+		if rel != "." {
+			tree, err = tree.Tree(filepath.ToSlash(rel))
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return tree.Hash.String(), nil
+}
+
 func (g *GitClientImpl) FindRepositoryRoot(startPath string) (string, error) {
 	repo, err := git.PlainOpenWithOptions(startPath, &git.PlainOpenOptions{
 		DetectDotGit: true,
@@ -77,7 +154,7 @@ func (g *GitClientImpl) IsRepositoryDirty(path string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	
+
 	repo, err := git.PlainOpen(repoRoot)
 	if err != nil {
 		return false, err
@@ -0,0 +1,258 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type HistoryService struct {
+	s3           S3Client
+	bucket       string
+	pathTemplate string
+}
+
+func NewHistoryService(s3 S3Client, bucket string) *HistoryService {
+	return &HistoryService{
+		s3:     s3,
+		bucket: bucket,
+	}
+}
+
+// SetPathTemplate overrides the image key layout (naming.path_template in
+// the config) used when rebuilding pointers from audit history.
+func (h *HistoryService) SetPathTemplate(template string) {
+	h.pathTemplate = template
+}
+
+// Summary aggregates the audit events for an app recorded since the given
+// time: counts by event type, the most active users, the push skip rate, and
+// promotions per environment.
+func (h *HistoryService) Summary(ctx context.Context, appName string, since time.Time) (*AuditSummaryResult, error) {
+	LogInfo("Summarizing audit events for %s since %s", appName, since.Format(time.RFC3339))
+
+	prefix := fmt.Sprintf("audit/%s/", appName)
+	keys, err := h.s3.List(ctx, h.bucket, prefix)
+	if err != nil {
+		LogError("Failed to list audit events: %v", err)
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	result := &AuditSummaryResult{
+		AppName:         appName,
+		Since:           since.Format(time.RFC3339),
+		EventCounts:     map[string]int{},
+		PromotionsByEnv: map[string]int{},
+	}
+	userCounts := map[string]int{}
+
+	for _, key := range keys {
+		data, err := h.s3.Download(ctx, h.bucket, key)
+		if err != nil {
+			LogError("Failed to download audit event %s: %v", key, err)
+			return nil, fmt.Errorf("failed to download audit event %s: %w", key, err)
+		}
+
+		event, err := AuditEventFromJSON(data)
+		if err != nil {
+			LogDebug("Skipping unparseable audit event %s: %v", key, err)
+			continue
+		}
+
+		if event.Timestamp.Before(since) {
+			continue
+		}
+
+		result.Events++
+		result.EventCounts[string(event.EventType)]++
+		userCounts[event.User]++
+
+		switch event.EventType {
+		case EventTypePush:
+			result.Pushes++
+			if pushWasSkipped(event.Details) {
+				result.PushesSkipped++
+			}
+		case EventTypePromotion:
+			if env := promotionEnvironment(event.Details); env != "" {
+				result.PromotionsByEnv[env]++
+			}
+		}
+	}
+
+	if result.Pushes > 0 {
+		result.PushSkipRate = float64(result.PushesSkipped) / float64(result.Pushes)
+	}
+
+	for user, count := range userCounts {
+		result.TopUsers = append(result.TopUsers, UserActivity{User: user, Events: count})
+	}
+	sort.Slice(result.TopUsers, func(i, j int) bool {
+		if result.TopUsers[i].Events != result.TopUsers[j].Events {
+			return result.TopUsers[i].Events > result.TopUsers[j].Events
+		}
+		return result.TopUsers[i].User < result.TopUsers[j].User
+	})
+
+	return result, nil
+}
+
+// VerifyChain walks an app's audit events in order and validates the hash
+// chain written when audit.chain is enabled, reporting the first break. Events
+// without a recorded prev_hash (written before chaining was enabled) are
+// counted as unchained rather than treated as breaks.
+func (h *HistoryService) VerifyChain(ctx context.Context, appName string) (*AuditChainResult, error) {
+	LogInfo("Verifying audit chain for %s", appName)
+
+	prefix := fmt.Sprintf("audit/%s/", appName)
+	keys, err := h.s3.List(ctx, h.bucket, prefix)
+	if err != nil {
+		LogError("Failed to list audit events: %v", err)
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	sort.Strings(keys)
+
+	result := &AuditChainResult{
+		AppName: appName,
+		Intact:  true,
+	}
+
+	var prevData []byte
+	for _, key := range keys {
+		data, err := h.s3.Download(ctx, h.bucket, key)
+		if err != nil {
+			LogError("Failed to download audit event %s: %v", key, err)
+			return nil, fmt.Errorf("failed to download audit event %s: %w", key, err)
+		}
+
+		event, err := AuditEventFromJSON(data)
+		if err != nil {
+			result.Intact = false
+			result.BrokenAt = key
+			result.Detail = fmt.Sprintf("unparseable audit event: %v", err)
+			return result, nil
+		}
+
+		result.Events++
+		if event.PrevHash == "" {
+			result.Unchained++
+		} else if prevData == nil {
+			result.Intact = false
+			result.BrokenAt = key
+			result.Detail = "event records a previous hash but no earlier event exists"
+			return result, nil
+		} else if expected := AuditEventHash(prevData); event.PrevHash != expected {
+			result.Intact = false
+			result.BrokenAt = key
+			result.Detail = fmt.Sprintf("prev_hash %s does not match hash of previous event %s", event.PrevHash, expected)
+			return result, nil
+		} else {
+			result.Chained++
+		}
+
+		prevData = data
+	}
+
+	return result, nil
+}
+
+// Replay rebuilds a lost environment pointer from the app's audit history
+// (audit replay): the latest promotion event for the environment supplies the
+// git coordinates, and the concrete image they identify is re-promoted. The
+// rebuild is itself recorded as a fresh promotion audit event.
+func (h *HistoryService) Replay(ctx context.Context, appName, environment string) (*AuditReplayResult, error) {
+	event, err := h.latestPromotion(ctx, appName, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	imageRef := fmt.Sprintf("%s:%s-%s", appName, event.GitTime, event.GitHash)
+	LogInfo("Replaying promotion of %s to %s from audit event at %s", imageRef, environment, event.Timestamp.Format(time.RFC3339))
+
+	promoter := NewImagePromoter(h.s3, h.bucket)
+	promoter.SetPathTemplate(h.pathTemplate)
+
+	promoteResult, err := promoter.Promote(ctx, imageRef, environment)
+	if err != nil {
+		LogError("Failed to rebuild environment pointer: %v", err)
+		return nil, fmt.Errorf("failed to rebuild environment pointer: %w", err)
+	}
+
+	return &AuditReplayResult{
+		AppName:        appName,
+		Environment:    environment,
+		ImageRef:       imageRef,
+		EventTimestamp: event.Timestamp.Format(time.RFC3339),
+		Skipped:        promoteResult.Skipped,
+	}, nil
+}
+
+// latestPromotion returns the most recent promotion audit event targeting
+// the given environment
+func (h *HistoryService) latestPromotion(ctx context.Context, appName, environment string) (*AuditEvent, error) {
+	prefix := fmt.Sprintf("audit/%s/", appName)
+	keys, err := h.s3.List(ctx, h.bucket, prefix)
+	if err != nil {
+		LogError("Failed to list audit events: %v", err)
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	var latest *AuditEvent
+	for _, key := range keys {
+		data, err := h.s3.Download(ctx, h.bucket, key)
+		if err != nil {
+			LogError("Failed to download audit event %s: %v", key, err)
+			return nil, fmt.Errorf("failed to download audit event %s: %w", key, err)
+		}
+
+		event, err := AuditEventFromJSON(data)
+		if err != nil {
+			LogDebug("Skipping unparseable audit event %s: %v", key, err)
+			continue
+		}
+
+		if event.EventType != EventTypePromotion {
+			continue
+		}
+		if promotionEnvironment(event.Details) != environment {
+			continue
+		}
+		if latest == nil || event.Timestamp.After(latest.Timestamp) {
+			latest = event
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("no promotion audit events found for %s/%s", appName, environment)
+	}
+	return latest, nil
+}
+
+// Details is deserialized as a generic map; re-marshal into the typed
+// structs to read event-specific fields.
+
+func pushWasSkipped(details interface{}) bool {
+	var parsed PushEventDetails
+	if !remarshalDetails(details, &parsed) {
+		return false
+	}
+	return parsed.WasSkipped
+}
+
+func promotionEnvironment(details interface{}) string {
+	var parsed PromotionEventDetails
+	if !remarshalDetails(details, &parsed) {
+		return ""
+	}
+	return parsed.Environment
+}
+
+func remarshalDetails(details interface{}, target interface{}) bool {
+	data, err := json.Marshal(details)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, target) == nil
+}
@@ -0,0 +1,322 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func seedAuditEvent(t *testing.T, mock *mockS3ClientForList, event *AuditEvent) {
+	t.Helper()
+	key := GenerateAuditKey(event.AppName, event.Timestamp, event.EventType, event.GitHash)
+	data, err := event.ToJSON()
+	if err != nil {
+		t.Fatalf("failed to serialize audit event: %v", err)
+	}
+	mock.files[key] = data
+}
+
+func TestHistoryService_Summary(t *testing.T) {
+	mock := newMockS3ClientForList()
+	now := time.Now()
+
+	seedAuditEvent(t, mock, &AuditEvent{
+		EventType: EventTypePush,
+		Timestamp: now.Add(-1 * time.Hour),
+		User:      "alice",
+		AppName:   "myapp",
+		GitHash:   "abc1234",
+		Details:   PushEventDetails{ImageReference: "myapp:a", WasSkipped: false},
+	})
+	seedAuditEvent(t, mock, &AuditEvent{
+		EventType: EventTypePush,
+		Timestamp: now.Add(-2 * time.Hour),
+		User:      "alice",
+		AppName:   "myapp",
+		GitHash:   "def5678",
+		Details:   PushEventDetails{ImageReference: "myapp:b", WasSkipped: true},
+	})
+	seedAuditEvent(t, mock, &AuditEvent{
+		EventType: EventTypePromotion,
+		Timestamp: now.Add(-3 * time.Hour),
+		User:      "bob",
+		AppName:   "myapp",
+		GitHash:   "abc1234",
+		Details:   PromotionEventDetails{Environment: "production", Source: "myapp:a"},
+	})
+	seedAuditEvent(t, mock, &AuditEvent{
+		EventType: EventTypePromotion,
+		Timestamp: now.Add(-4 * time.Hour),
+		User:      "alice",
+		AppName:   "myapp",
+		GitHash:   "abc1234",
+		Details:   PromotionEventDetails{Environment: "staging", Source: "myapp:a"},
+	})
+	// Outside the window; must not be counted
+	seedAuditEvent(t, mock, &AuditEvent{
+		EventType: EventTypeTag,
+		Timestamp: now.Add(-48 * time.Hour),
+		User:      "carol",
+		AppName:   "myapp",
+		GitHash:   "old1234",
+		Details:   TagEventDetails{Version: "v1.0.0"},
+	})
+
+	service := NewHistoryService(mock, "test-bucket")
+	result, err := service.Summary(context.Background(), "myapp", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("Summary failed: %v", err)
+	}
+
+	if result.Events != 4 {
+		t.Errorf("Expected 4 events in window, got %d", result.Events)
+	}
+	if result.EventCounts["push"] != 2 {
+		t.Errorf("Expected 2 push events, got %d", result.EventCounts["push"])
+	}
+	if result.EventCounts["promotion"] != 2 {
+		t.Errorf("Expected 2 promotion events, got %d", result.EventCounts["promotion"])
+	}
+	if result.EventCounts["tag"] != 0 {
+		t.Errorf("Expected tag event outside window to be excluded, got %d", result.EventCounts["tag"])
+	}
+	if result.Pushes != 2 || result.PushesSkipped != 1 {
+		t.Errorf("Expected 2 pushes with 1 skipped, got %d/%d", result.Pushes, result.PushesSkipped)
+	}
+	if result.PushSkipRate != 0.5 {
+		t.Errorf("Expected push skip rate 0.5, got %f", result.PushSkipRate)
+	}
+	if result.PromotionsByEnv["production"] != 1 || result.PromotionsByEnv["staging"] != 1 {
+		t.Errorf("Unexpected promotions by environment: %v", result.PromotionsByEnv)
+	}
+	if len(result.TopUsers) != 2 {
+		t.Fatalf("Expected 2 users, got %d", len(result.TopUsers))
+	}
+	if result.TopUsers[0].User != "alice" || result.TopUsers[0].Events != 3 {
+		t.Errorf("Expected alice first with 3 events, got %s with %d", result.TopUsers[0].User, result.TopUsers[0].Events)
+	}
+}
+
+func TestHistoryService_Summary_NoEvents(t *testing.T) {
+	mock := newMockS3ClientForList()
+
+	service := NewHistoryService(mock, "test-bucket")
+	result, err := service.Summary(context.Background(), "myapp", time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("Summary failed: %v", err)
+	}
+
+	if result.Events != 0 {
+		t.Errorf("Expected 0 events, got %d", result.Events)
+	}
+	if result.PushSkipRate != 0 {
+		t.Errorf("Expected zero skip rate with no pushes, got %f", result.PushSkipRate)
+	}
+}
+
+func TestHistoryService_Replay_RebuildsPointer(t *testing.T) {
+	mock := newMockS3ClientForList()
+	now := time.Now()
+
+	// An older production promotion that must be superseded
+	seedAuditEvent(t, mock, &AuditEvent{
+		EventType: EventTypePromotion,
+		Timestamp: now.Add(-48 * time.Hour),
+		User:      "alice",
+		AppName:   "myapp",
+		GitHash:   "old1234",
+		GitTime:   "20250601-0900",
+		Details:   PromotionEventDetails{Environment: "production", Source: "myapp:20250601-0900-old1234"},
+	})
+	// The latest production promotion; its git coordinates drive the rebuild
+	seedAuditEvent(t, mock, &AuditEvent{
+		EventType: EventTypePromotion,
+		Timestamp: now.Add(-1 * time.Hour),
+		User:      "bob",
+		AppName:   "myapp",
+		GitHash:   "abc1234",
+		GitTime:   "20250721-1430",
+		Details:   PromotionEventDetails{Environment: "production", Source: "myapp:20250721-1430-abc1234"},
+	})
+	// A staging promotion that must be ignored
+	seedAuditEvent(t, mock, &AuditEvent{
+		EventType: EventTypePromotion,
+		Timestamp: now.Add(-30 * time.Minute),
+		User:      "carol",
+		AppName:   "myapp",
+		GitHash:   "def5678",
+		GitTime:   "20250722-0039",
+		Details:   PromotionEventDetails{Environment: "staging", Source: "myapp:20250722-0039-def5678"},
+	})
+
+	// The concrete image from the latest event still exists
+	imageKey := "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz"
+	mock.files[imageKey] = []byte("image data")
+
+	service := NewHistoryService(mock, "test-bucket")
+	result, err := service.Replay(context.Background(), "myapp", "production")
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	if result.ImageRef != "myapp:20250721-1430-abc1234" {
+		t.Errorf("Expected latest production image, got %s", result.ImageRef)
+	}
+	if result.Skipped {
+		t.Error("Expected pointer to be rebuilt, not skipped")
+	}
+
+	data, ok := mock.files["pointers/myapp/production.json"]
+	if !ok {
+		t.Fatal("Expected environment pointer to be written")
+	}
+	pointer, err := PointerMetadataFromJSON(data)
+	if err != nil {
+		t.Fatalf("Failed to parse rebuilt pointer: %v", err)
+	}
+	if pointer.TargetPath != imageKey {
+		t.Errorf("Expected pointer target %s, got %s", imageKey, pointer.TargetPath)
+	}
+	if pointer.GitHash != "abc1234" {
+		t.Errorf("Expected git hash abc1234, got %s", pointer.GitHash)
+	}
+}
+
+func TestHistoryService_Replay_NoPromotionEvents(t *testing.T) {
+	mock := newMockS3ClientForList()
+
+	// Pushes alone are not enough to rebuild a pointer
+	seedAuditEvent(t, mock, &AuditEvent{
+		EventType: EventTypePush,
+		Timestamp: time.Now().Add(-1 * time.Hour),
+		User:      "alice",
+		AppName:   "myapp",
+		GitHash:   "abc1234",
+		Details:   PushEventDetails{ImageReference: "myapp:a"},
+	})
+
+	service := NewHistoryService(mock, "test-bucket")
+	_, err := service.Replay(context.Background(), "myapp", "production")
+	if err == nil {
+		t.Fatal("Expected error when no promotion events exist")
+	}
+}
+
+func chainedAuditEvents(t *testing.T, mock *mockS3ClientForList, count int) []string {
+	t.Helper()
+	base := time.Date(2025, 7, 21, 14, 30, 0, 0, time.UTC)
+	var prevData []byte
+	var keys []string
+	for i := 0; i < count; i++ {
+		event := &AuditEvent{
+			EventType: EventTypePush,
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			User:      "alice",
+			AppName:   "myapp",
+			GitHash:   fmt.Sprintf("hash%03d", i),
+			Details:   PushEventDetails{ImageReference: "myapp:x"},
+		}
+		if prevData != nil {
+			event.PrevHash = AuditEventHash(prevData)
+		}
+		key := GenerateAuditKey(event.AppName, event.Timestamp, event.EventType, event.GitHash)
+		data, err := event.ToJSON()
+		if err != nil {
+			t.Fatalf("failed to serialize audit event: %v", err)
+		}
+		mock.files[key] = data
+		prevData = data
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestHistoryService_VerifyChain_Intact(t *testing.T) {
+	mock := newMockS3ClientForList()
+	chainedAuditEvents(t, mock, 3)
+
+	service := NewHistoryService(mock, "test-bucket")
+	result, err := service.VerifyChain(context.Background(), "myapp")
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+
+	if !result.Intact {
+		t.Errorf("Expected intact chain, broken at %s: %s", result.BrokenAt, result.Detail)
+	}
+	if result.Events != 3 || result.Chained != 2 || result.Unchained != 1 {
+		t.Errorf("Unexpected counts: events=%d chained=%d unchained=%d", result.Events, result.Chained, result.Unchained)
+	}
+}
+
+func TestHistoryService_VerifyChain_DetectsTampering(t *testing.T) {
+	mock := newMockS3ClientForList()
+	keys := chainedAuditEvents(t, mock, 3)
+
+	// Tamper with the middle event after the chain was written
+	tampered, err := AuditEventFromJSON(mock.files[keys[1]])
+	if err != nil {
+		t.Fatalf("failed to parse seeded event: %v", err)
+	}
+	tampered.User = "mallory"
+	data, _ := tampered.ToJSON()
+	mock.files[keys[1]] = data
+
+	service := NewHistoryService(mock, "test-bucket")
+	result, err := service.VerifyChain(context.Background(), "myapp")
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+
+	if result.Intact {
+		t.Fatal("Expected tampering to break the chain")
+	}
+	if result.BrokenAt != keys[2] {
+		t.Errorf("Expected break at %s, got %s", keys[2], result.BrokenAt)
+	}
+}
+
+func TestS3AuditLogger_ChainsEvents(t *testing.T) {
+	t.Setenv("S3DOCK_AUDIT_CHAIN", "1")
+
+	mock := newMockS3ClientForList()
+	logger := NewS3AuditLogger(mock, "test-bucket")
+
+	first := &AuditEvent{
+		EventType: EventTypePush,
+		Timestamp: time.Date(2025, 7, 21, 14, 30, 0, 0, time.UTC),
+		User:      "alice",
+		AppName:   "myapp",
+		GitHash:   "abc1234",
+		Details:   PushEventDetails{ImageReference: "myapp:a"},
+	}
+	if err := logger.LogEvent(context.Background(), first); err != nil {
+		t.Fatalf("LogEvent failed: %v", err)
+	}
+	if first.PrevHash != "" {
+		t.Errorf("First event should have no prev_hash, got %s", first.PrevHash)
+	}
+
+	firstKey := GenerateAuditKey("myapp", first.Timestamp, EventTypePush, "abc1234")
+	firstData := mock.files[firstKey]
+	if firstData == nil {
+		t.Fatal("First event was not uploaded")
+	}
+
+	second := &AuditEvent{
+		EventType: EventTypePush,
+		Timestamp: time.Date(2025, 7, 21, 14, 31, 0, 0, time.UTC),
+		User:      "alice",
+		AppName:   "myapp",
+		GitHash:   "def5678",
+		Details:   PushEventDetails{ImageReference: "myapp:b"},
+	}
+	if err := logger.LogEvent(context.Background(), second); err != nil {
+		t.Fatalf("LogEvent failed: %v", err)
+	}
+
+	if second.PrevHash != AuditEventHash(firstData) {
+		t.Errorf("Second event prev_hash %s does not match hash of first event", second.PrevHash)
+	}
+}
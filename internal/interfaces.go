@@ -8,8 +8,15 @@ import (
 type DockerClient interface {
 	ExportImage(ctx context.Context, imageRef string) (io.ReadCloser, error)
 	ImportImage(ctx context.Context, tarStream io.Reader) error
-	BuildImage(ctx context.Context, contextPath string, dockerfile string, tags []string, platform string) error
+	BuildImage(ctx context.Context, contextPath string, dockerfile string, tags []string, platform string, cacheFrom []string, squash, pull bool) error
 	ImageExists(ctx context.Context, imageRef string) (bool, error)
+	ImageID(ctx context.Context, imageRef string) (string, error)
+	ImageTags(ctx context.Context, imageRef string) ([]string, error)
+	ImageArchitecture(ctx context.Context, imageRef string) (string, error)
+	TagImage(ctx context.Context, sourceRef, targetRef string) error
+	RemoveTag(ctx context.Context, imageRef string) error
+	ListImages(ctx context.Context) ([]string, error)
+	RemoveImage(ctx context.Context, imageRef string) error
 }
 
 type S3Client interface {
@@ -23,9 +30,25 @@ type S3Client interface {
 	List(ctx context.Context, bucket, prefix string) ([]string, error)
 }
 
+// BuildLogger is an optional DockerClient capability. Clients that capture
+// the build output stream expose it here so Build can attach the log to its
+// result instead of losing it to interleaved stdout.
+type BuildLogger interface {
+	BuildLog() string
+}
+
+// ETagClient is an optional S3Client capability. Clients that can report an
+// object's ETag with a HEAD request implement it, letting callers compare
+// ETags instead of re-downloading an object to re-checksum it.
+type ETagClient interface {
+	HeadETag(ctx context.Context, bucket, key string) (string, error)
+}
+
 type GitClient interface {
 	GetCurrentHash(path string) (string, error)
 	GetCommitTimestamp(path string) (string, error)
+	GetTreeHash(path, subdir string) (string, error)
+	GetTreeHashAtCommit(path, subdir, commitHash string) (string, error)
 	IsRepositoryDirty(path string) (bool, error)
 	FindRepositoryRoot(startPath string) (string, error)
 }
@@ -6,12 +6,17 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 )
 
 // ListService provides listing functionality for images, tags, and environments
 type ListService struct {
-	s3     S3Client
-	bucket string
+	s3           S3Client
+	bucket       string
+	pathTemplate string
+	emit         func(interface{})
 }
 
 // ImageInfo contains information about a pushed image
@@ -20,6 +25,9 @@ type ImageInfo struct {
 	Tag       string // e.g., 20250721-2118-f7a5a27
 	S3Path    string
 	YearMonth string
+	Checksum  string // Stored checksum from metadata (set by EnrichChecksums)
+
+	DockerfileHash string // Dockerfile fingerprint from metadata (set by EnrichChecksums)
 }
 
 // TagInfo contains information about a semantic version tag
@@ -27,17 +35,23 @@ type TagInfo struct {
 	AppName     string
 	Version     string // e.g., v1.2.0
 	TargetImage string // e.g., myapp:20250721-2118-f7a5a27
+	TargetPath  string // S3 key of the target image
 	S3Path      string
+	Dangling    bool // True if the target image no longer exists (set by MarkDanglingTags)
 }
 
 // EnvInfo contains information about an environment pointer
 type EnvInfo struct {
-	AppName     string
-	Environment string
-	TargetType  TargetType // "image" or "tag"
-	TargetPath  string
-	SourceTag   string // If promoted from a tag
-	SourceImage string // Resolved image reference
+	AppName       string
+	Environment   string
+	TargetType    TargetType // "image" or "tag"
+	TargetPath    string
+	SourceTag     string    // If promoted from a tag
+	SourceImage   string    // Resolved image reference
+	PromotedBy    string    // User recorded in the pointer
+	PromotedAt    time.Time // When the pointer was written
+	ResolvedImage string    // Currently-resolved image reference (set by ResolveEnvironments)
+	Drifted       bool      // True if ResolvedImage differs from the recorded SourceImage
 }
 
 func NewListService(s3 S3Client, bucket string) *ListService {
@@ -47,14 +61,24 @@ func NewListService(s3 S3Client, bucket string) *ListService {
 	}
 }
 
+// SetPathTemplate overrides the image key layout (naming.path_template in the
+// config) used to list and parse image keys.
+func (l *ListService) SetPathTemplate(template string) {
+	l.pathTemplate = template
+}
+
+// SetEmitter installs a callback invoked with each list element as it is
+// parsed, enabling streaming output. Elements are emitted in listing order,
+// before any final sorting.
+func (l *ListService) SetEmitter(emit func(interface{})) {
+	l.emit = emit
+}
+
 // ListImages returns all images for an app, optionally filtered by year-month
 func (l *ListService) ListImages(ctx context.Context, appName string, yearMonth string) ([]ImageInfo, error) {
 	LogInfo("Listing images for %s", appName)
 
-	prefix := fmt.Sprintf("images/%s/", appName)
-	if yearMonth != "" {
-		prefix = fmt.Sprintf("images/%s/%s/", appName, yearMonth)
-	}
+	prefix := ExpandPathPrefix(l.pathTemplate, appName, yearMonth)
 
 	LogDebug("Listing S3 objects with prefix: %s", prefix)
 	keys, err := l.s3.List(ctx, l.bucket, prefix)
@@ -75,6 +99,9 @@ func (l *ListService) ListImages(ctx context.Context, appName string, yearMonth
 			LogDebug("Skipping invalid image path %s: %v", key, err)
 			continue
 		}
+		if l.emit != nil {
+			l.emit(info.ToJSON())
+		}
 		images = append(images, info)
 	}
 
@@ -87,6 +114,22 @@ func (l *ListService) ListImages(ctx context.Context, appName string, yearMonth
 	return images, nil
 }
 
+// GroupImagesByMonth buckets images by YearMonth (list images
+// --group-by-month). Months are returned newest first; within each month the
+// images keep their listing order (newest first).
+func GroupImagesByMonth(images []ImageInfo) ([]string, map[string][]ImageInfo) {
+	groups := make(map[string][]ImageInfo)
+	var months []string
+	for _, img := range images {
+		if _, seen := groups[img.YearMonth]; !seen {
+			months = append(months, img.YearMonth)
+		}
+		groups[img.YearMonth] = append(groups[img.YearMonth], img)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(months)))
+	return months, groups
+}
+
 // ListTags returns all semantic version tags for an app
 func (l *ListService) ListTags(ctx context.Context, appName string) ([]TagInfo, error) {
 	LogInfo("Listing tags for %s", appName)
@@ -124,12 +167,17 @@ func (l *ListService) ListTags(ctx context.Context, appName string) ([]TagInfo,
 			continue
 		}
 
-		tags = append(tags, TagInfo{
+		info := TagInfo{
 			AppName:     appName,
 			Version:     version,
 			TargetImage: pointer.SourceImage,
+			TargetPath:  pointer.TargetPath,
 			S3Path:      key,
-		})
+		}
+		if l.emit != nil {
+			l.emit(info.ToJSON())
+		}
+		tags = append(tags, info)
 	}
 
 	// Sort by version (semantic versioning aware would be better, but string sort works for v-prefixed versions)
@@ -141,6 +189,60 @@ func (l *ListService) ListTags(ctx context.Context, appName string) ([]TagInfo,
 	return tags, nil
 }
 
+// MarkDanglingTags checks each tag's target image in S3 and flags tags whose
+// target no longer exists (deleted or archived). Such tags are cleanup
+// candidates: pulling or promoting through them would fail.
+func (l *ListService) MarkDanglingTags(ctx context.Context, tags []TagInfo) error {
+	for i := range tags {
+		exists, err := l.s3.Exists(ctx, l.bucket, tags[i].TargetPath)
+		if err != nil {
+			return fmt.Errorf("failed to check tag target %s: %w", tags[i].TargetPath, err)
+		}
+		tags[i].Dangling = !exists
+	}
+	return nil
+}
+
+// ListAll gathers an app's images, tags, and environments in one call so a
+// dashboard can fetch the complete state with a single invocation. The three
+// listings run in parallel.
+func (l *ListService) ListAll(ctx context.Context, appName string) ([]ImageInfo, []TagInfo, []EnvInfo, error) {
+	LogInfo("Listing images, tags, and environments for %s", appName)
+
+	var (
+		wg     sync.WaitGroup
+		images []ImageInfo
+		tags   []TagInfo
+		envs   []EnvInfo
+		imgErr error
+		tagErr error
+		envErr error
+	)
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		images, imgErr = l.ListImages(ctx, appName, "")
+	}()
+	go func() {
+		defer wg.Done()
+		tags, tagErr = l.ListTags(ctx, appName)
+	}()
+	go func() {
+		defer wg.Done()
+		envs, envErr = l.ListEnvironments(ctx, appName)
+	}()
+	wg.Wait()
+
+	for _, err := range []error{imgErr, tagErr, envErr} {
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return images, tags, envs, nil
+}
+
 // ListEnvironments returns all environment pointers for an app
 func (l *ListService) ListEnvironments(ctx context.Context, appName string) ([]EnvInfo, error) {
 	LogInfo("Listing environments for %s", appName)
@@ -159,6 +261,10 @@ func (l *ListService) ListEnvironments(ctx context.Context, appName string) ([]E
 		if !strings.HasSuffix(key, ".json") {
 			continue
 		}
+		// Pending pointers are scheduled promotions, not live environments
+		if strings.HasSuffix(key, ".pending.json") {
+			continue
+		}
 
 		// Extract environment from path: pointers/myapp/production.json -> production
 		base := filepath.Base(key)
@@ -185,6 +291,8 @@ func (l *ListService) ListEnvironments(ctx context.Context, appName string) ([]E
 			TargetPath:  pointer.TargetPath,
 			SourceTag:   pointer.SourceTag,
 			SourceImage: pointer.SourceImage,
+			PromotedBy:  pointer.PromotedBy,
+			PromotedAt:  pointer.PromotedAt,
 		})
 	}
 
@@ -197,44 +305,265 @@ func (l *ListService) ListEnvironments(ctx context.Context, appName string) ([]E
 	return envs, nil
 }
 
-// ListApps returns all apps that have images, tags, or environments
-func (l *ListService) ListApps(ctx context.Context) ([]string, error) {
-	LogInfo("Listing all apps")
+// FindByImageID scans an app's image metadata for entries whose recorded
+// Docker image ID matches. Without an index this is a brute-force scan over
+// every metadata object, so it is scoped to a single app and the downloads
+// run in parallel.
+func (l *ListService) FindByImageID(ctx context.Context, appName, imageID string) ([]ImageInfo, error) {
+	LogInfo("Searching %s images for image ID %s", appName, imageID)
 
-	appSet := make(map[string]bool)
+	images, err := l.ListImages(ctx, appName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	var matches []ImageInfo
+	var wg sync.WaitGroup
+
+	for _, img := range images {
+		wg.Add(1)
+		go func(img ImageInfo) {
+			defer wg.Done()
 
-	// Check images
-	imageKeys, err := l.s3.List(ctx, l.bucket, "images/")
-	if err == nil {
-		for _, key := range imageKeys {
-			parts := strings.Split(key, "/")
-			if len(parts) >= 2 {
-				appSet[parts[1]] = true
+			metadataKey := GenerateMetadataKey(img.S3Path)
+			metadataData, err := l.s3.Download(ctx, l.bucket, metadataKey)
+			if err != nil {
+				LogDebug("Failed to download metadata %s: %v", metadataKey, err)
+				return
 			}
-		}
+
+			metadata, err := ImageMetadataFromJSON(metadataData)
+			if err != nil {
+				LogDebug("Failed to parse metadata %s: %v", metadataKey, err)
+				return
+			}
+
+			if metadata.ImageID != "" && metadata.ImageID == imageID {
+				mu.Lock()
+				matches = append(matches, img)
+				mu.Unlock()
+			}
+		}(img)
 	}
+	wg.Wait()
+
+	// Parallel collection order is nondeterministic - sort newest first like ListImages
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Tag > matches[j].Tag
+	})
 
-	// Check tags
-	tagKeys, err := l.s3.List(ctx, l.bucket, "tags/")
-	if err == nil {
-		for _, key := range tagKeys {
-			parts := strings.Split(key, "/")
-			if len(parts) >= 2 {
-				appSet[parts[1]] = true
+	LogInfo("Found %d images matching image ID", len(matches))
+	return matches, nil
+}
+
+// EnrichChecksums downloads each image's sibling metadata and fills in the
+// stored checksum (list images --checksum). Like FindByImageID this is one
+// metadata download per image, so the fetches run in parallel. Images whose
+// metadata is missing or unparseable keep an empty checksum.
+func (l *ListService) EnrichChecksums(ctx context.Context, images []ImageInfo) {
+	var wg sync.WaitGroup
+	for i := range images {
+		wg.Add(1)
+		go func(img *ImageInfo) {
+			defer wg.Done()
+
+			metadataKey := GenerateMetadataKey(img.S3Path)
+			metadataData, err := l.s3.Download(ctx, l.bucket, metadataKey)
+			if err != nil {
+				LogDebug("Failed to download metadata %s: %v", metadataKey, err)
+				return
 			}
-		}
+
+			metadata, err := ImageMetadataFromJSON(metadataData)
+			if err != nil {
+				LogDebug("Failed to parse metadata %s: %v", metadataKey, err)
+				return
+			}
+
+			img.Checksum = metadata.Checksum
+			img.DockerfileHash = metadata.DockerfileHash
+		}(&images[i])
 	}
+	wg.Wait()
+}
 
-	// Check pointers
-	pointerKeys, err := l.s3.List(ctx, l.bucket, "pointers/")
-	if err == nil {
-		for _, key := range pointerKeys {
-			parts := strings.Split(key, "/")
-			if len(parts) >= 2 {
-				appSet[parts[1]] = true
+// ResolveEnvironments follows each environment's pointer chain (env -> tag -> image)
+// and fills in ResolvedImage with the currently-resolved image reference. For
+// tag-promoted environments the recorded SourceImage is a snapshot taken at
+// promote time, so Drifted is set when the tag has since moved to a different image.
+// Resolution runs in parallel since each environment requires separate S3 reads.
+func (l *ListService) ResolveEnvironments(ctx context.Context, envs []EnvInfo) {
+	var wg sync.WaitGroup
+	for i := range envs {
+		wg.Add(1)
+		go func(env *EnvInfo) {
+			defer wg.Done()
+
+			pointer := &PointerMetadata{
+				TargetType: env.TargetType,
+				TargetPath: env.TargetPath,
 			}
+
+			imageS3Path, err := ResolveImagePath(ctx, l.s3, l.bucket, pointer)
+			if err != nil {
+				LogDebug("Failed to resolve environment %s: %v", env.Environment, err)
+				return
+			}
+
+			resolvedImage, err := imageReferenceFromPath(imageS3Path)
+			if err != nil {
+				LogDebug("Failed to extract image reference for %s: %v", env.Environment, err)
+				return
+			}
+
+			env.ResolvedImage = resolvedImage
+			env.Drifted = env.SourceImage != "" && env.SourceImage != resolvedImage
+		}(&envs[i])
+	}
+	wg.Wait()
+}
+
+// FormatEnvironmentsWide renders environments as an aligned table with one
+// row per environment: environment, target type, resolved image, source tag,
+// promoted-by, and promoted-at. Call ResolveEnvironments first to populate
+// the resolved image column.
+func FormatEnvironmentsWide(envs []EnvInfo) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ENVIRONMENT\tTYPE\tRESOLVED IMAGE\tSOURCE TAG\tPROMOTED BY\tPROMOTED AT")
+	for _, env := range envs {
+		resolved := env.ResolvedImage
+		if resolved == "" {
+			resolved = env.SourceImage
+		}
+		sourceTag := env.SourceTag
+		if sourceTag == "" {
+			sourceTag = "-"
+		}
+		promotedAt := "-"
+		if !env.PromotedAt.IsZero() {
+			promotedAt = env.PromotedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			env.Environment, env.TargetType, resolved, sourceTag, env.PromotedBy, promotedAt)
+	}
+	w.Flush()
+
+	return sb.String()
+}
+
+// graphChainTag names the intermediate tag node of an environment's pointer
+// chain, falling back to the raw target path for tags recorded before
+// source_tag existed.
+func graphChainTag(env EnvInfo) string {
+	if env.SourceTag != "" {
+		return env.SourceTag
+	}
+	return env.TargetPath
+}
+
+// graphChainImage names the terminal image node of an environment's pointer
+// chain, preferring the currently-resolved image over the promote-time snapshot.
+func graphChainImage(env EnvInfo) string {
+	if env.ResolvedImage != "" {
+		return env.ResolvedImage
+	}
+	return env.SourceImage
+}
+
+// FormatEnvironmentsGraph renders each environment's pointer chain as ASCII
+// (list envs --graph): env -> tag -> image for tag-promoted environments,
+// env -> image for direct promotions. Call ResolveEnvironments first.
+func FormatEnvironmentsGraph(appName string, envs []EnvInfo) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n", appName)
+	for _, env := range envs {
+		if env.TargetType == TargetTypeTag {
+			fmt.Fprintf(&sb, "  %s -> %s -> %s\n", env.Environment, graphChainTag(env), graphChainImage(env))
+		} else {
+			fmt.Fprintf(&sb, "  %s -> %s\n", env.Environment, graphChainImage(env))
 		}
 	}
+	return sb.String()
+}
+
+// FormatEnvironmentsDOT renders the pointer chains as a Graphviz digraph
+// (list envs --graph --format dot), suitable for piping to dot -Tpng.
+func FormatEnvironmentsDOT(appName string, envs []EnvInfo) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %q {\n", appName)
+	sb.WriteString("  rankdir=LR;\n")
+	for _, env := range envs {
+		if env.TargetType == TargetTypeTag {
+			tag := graphChainTag(env)
+			fmt.Fprintf(&sb, "  %q -> %q;\n", env.Environment, tag)
+			fmt.Fprintf(&sb, "  %q -> %q;\n", tag, graphChainImage(env))
+		} else {
+			fmt.Fprintf(&sb, "  %q -> %q;\n", env.Environment, graphChainImage(env))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// ListApps returns all apps that have images, tags, or environments.
+// When includeArchived is set, apps whose only remaining objects live under
+// archive/ are included as well. When appScope is non-empty, listing is
+// narrowed to that app's prefixes, turning full-bucket scans into cheap
+// prefix checks. The per-prefix scans are independent and run in parallel.
+func (l *ListService) ListApps(ctx context.Context, includeArchived bool, appScope string) ([]string, error) {
+	LogInfo("Listing all apps")
+
+	prefixes := []string{"images/", "tags/", "pointers/"}
+	if includeArchived {
+		prefixes = append(prefixes, "archive/")
+	}
+	if appScope != "" {
+		for i, prefix := range prefixes {
+			prefixes[i] = prefix + appScope + "/"
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	appSet := make(map[string]bool)
+	var listErr error
+
+	for _, prefix := range prefixes {
+		wg.Add(1)
+		go func(prefix string) {
+			defer wg.Done()
+
+			keys, err := l.s3.List(ctx, l.bucket, prefix)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				// An inaccessible bucket must not masquerade as an empty
+				// one, so the error is surfaced instead of skipped
+				LogError("Failed to list prefix %s: %v", prefix, err)
+				if listErr == nil {
+					listErr = fmt.Errorf("failed to list prefix %s: %w", prefix, err)
+				}
+				return
+			}
+
+			for _, key := range keys {
+				parts := strings.Split(key, "/")
+				if len(parts) >= 2 {
+					appSet[parts[1]] = true
+				}
+			}
+		}(prefix)
+	}
+	wg.Wait()
+
+	if listErr != nil {
+		return nil, listErr
+	}
 
 	var apps []string
 	for app := range appSet {
@@ -246,9 +575,47 @@ func (l *ListService) ListApps(ctx context.Context) ([]string, error) {
 	return apps, nil
 }
 
+// LatestImages looks up each app's newest image tag (list apps
+// --with-latest). Each lookup is one listing, so they run in parallel. Apps
+// without any parseable images map to "".
+func (l *ListService) LatestImages(ctx context.Context, apps []string) map[string]string {
+	latest := make(map[string]string, len(apps))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, app := range apps {
+		wg.Add(1)
+		go func(app string) {
+			defer wg.Done()
+
+			tag := ""
+			images, err := l.ListImages(ctx, app, "")
+			if err != nil {
+				LogDebug("Failed to list images for %s: %v", app, err)
+			} else if len(images) > 0 {
+				// ListImages sorts newest first
+				tag = images[0].Tag
+			}
+
+			mu.Lock()
+			latest[app] = tag
+			mu.Unlock()
+		}(app)
+	}
+	wg.Wait()
+
+	return latest
+}
+
 // parseImagePath extracts image info from an S3 path
 // Example: images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz
 func (l *ListService) parseImagePath(s3Path string) (ImageInfo, error) {
+	// Custom layouts can't rely on fixed path segments, so recover the info
+	// from the canonical filename instead
+	if l.pathTemplate != "" && l.pathTemplate != DefaultPathTemplate {
+		return parseImageFilename(s3Path)
+	}
+
 	parts := strings.Split(s3Path, "/")
 	if len(parts) < 4 {
 		return ImageInfo{}, fmt.Errorf("invalid image path format")
@@ -276,6 +643,34 @@ func (l *ListService) parseImagePath(s3Path string) (ImageInfo, error) {
 	}, nil
 }
 
+// parseImageFilename recovers image info from the canonical filename alone
+// (<app>-<git_time>-<git_hash>.tar.gz), independent of the directory layout.
+// Year-month is derived from the git time rather than the path.
+func parseImageFilename(s3Path string) (ImageInfo, error) {
+	base := strings.TrimSuffix(filepath.Base(s3Path), ".tar.gz")
+
+	// Tag is the last three dash-separated fields: YYYYMMDD-HHMM-hash
+	parts := strings.Split(base, "-")
+	if len(parts) < 4 {
+		return ImageInfo{}, fmt.Errorf("invalid image filename format: %s", base)
+	}
+
+	appName := strings.Join(parts[:len(parts)-3], "-")
+	tag := strings.Join(parts[len(parts)-3:], "-")
+
+	yearMonth := ""
+	if len(tag) >= 6 {
+		yearMonth = tag[:6]
+	}
+
+	return ImageInfo{
+		AppName:   appName,
+		Tag:       tag,
+		S3Path:    s3Path,
+		YearMonth: yearMonth,
+	}, nil
+}
+
 // GetTagForEnvironment returns the semantic version tag for an environment (if promoted via tag)
 func (l *ListService) GetTagForEnvironment(ctx context.Context, appName, environment string) (string, error) {
 	LogInfo("Getting tag for %s in %s environment", appName, environment)
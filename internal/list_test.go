@@ -2,9 +2,11 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"io"
 	"strings"
 	"testing"
+	"time"
 )
 
 // MockS3Client for testing list functionality
@@ -96,6 +98,42 @@ func TestListImages(t *testing.T) {
 	}
 }
 
+func TestGroupImagesByMonth(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"] = []byte("image1")
+	mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"] = []byte("image2")
+	mock.files["images/myapp/202506/myapp-20250615-0930-def5678.tar.gz"] = []byte("image3")
+
+	listService := NewListService(mock, "test-bucket")
+
+	images, err := listService.ListImages(ctx, "myapp", "")
+	if err != nil {
+		t.Fatalf("ListImages failed: %v", err)
+	}
+
+	months, groups := GroupImagesByMonth(images)
+
+	if len(months) != 2 {
+		t.Fatalf("Expected 2 months, got %d", len(months))
+	}
+	if months[0] != "202507" || months[1] != "202506" {
+		t.Errorf("Expected months newest first [202507 202506], got %v", months)
+	}
+	if len(groups["202507"]) != 2 {
+		t.Errorf("Expected 2 images in 202507, got %d", len(groups["202507"]))
+	}
+	if len(groups["202506"]) != 1 {
+		t.Errorf("Expected 1 image in 202506, got %d", len(groups["202506"]))
+	}
+
+	// Within a month, listing order (newest first) is preserved
+	if groups["202507"][0].Tag != "20250721-2118-f7a5a27" {
+		t.Errorf("Expected newest image first in 202507, got %s", groups["202507"][0].Tag)
+	}
+}
+
 func TestListTags(t *testing.T) {
 	ctx := context.Background()
 	mock := newMockS3ClientForList()
@@ -186,6 +224,106 @@ func TestListEnvironments(t *testing.T) {
 	}
 }
 
+func TestResolveEnvironments(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	// Direct image promotion - resolved image matches the recorded source
+	prodPointer := `{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		"source_image": "myapp:20250721-2118-f7a5a27"
+	}`
+	// Tag promotion where the tag has since moved to a newer image
+	stagingPointer := `{
+		"target_type": "tag",
+		"target_path": "tags/myapp/v1.0.0.json",
+		"source_image": "myapp:20250720-1045-abc1234",
+		"source_tag": "v1.0.0"
+	}`
+	movedTag := `{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250722-0900-def5678.tar.gz",
+		"source_image": "myapp:20250722-0900-def5678"
+	}`
+	mock.files["pointers/myapp/production.json"] = []byte(prodPointer)
+	mock.files["pointers/myapp/staging.json"] = []byte(stagingPointer)
+	mock.files["tags/myapp/v1.0.0.json"] = []byte(movedTag)
+
+	listService := NewListService(mock, "test-bucket")
+
+	envs, err := listService.ListEnvironments(ctx, "myapp")
+	if err != nil {
+		t.Fatalf("ListEnvironments failed: %v", err)
+	}
+
+	listService.ResolveEnvironments(ctx, envs)
+
+	var prodEnv, stagingEnv *EnvInfo
+	for i := range envs {
+		if envs[i].Environment == "production" {
+			prodEnv = &envs[i]
+		}
+		if envs[i].Environment == "staging" {
+			stagingEnv = &envs[i]
+		}
+	}
+
+	if prodEnv == nil || stagingEnv == nil {
+		t.Fatal("Expected both production and staging environments")
+	}
+
+	if prodEnv.ResolvedImage != "myapp:20250721-2118-f7a5a27" {
+		t.Errorf("Expected production resolved image 'myapp:20250721-2118-f7a5a27', got '%s'", prodEnv.ResolvedImage)
+	}
+	if prodEnv.Drifted {
+		t.Error("Expected production to not be drifted")
+	}
+
+	if stagingEnv.ResolvedImage != "myapp:20250722-0900-def5678" {
+		t.Errorf("Expected staging resolved image 'myapp:20250722-0900-def5678', got '%s'", stagingEnv.ResolvedImage)
+	}
+	if !stagingEnv.Drifted {
+		t.Error("Expected staging to be drifted after the tag moved")
+	}
+}
+
+func TestFormatEnvironmentsWide(t *testing.T) {
+	envs := []EnvInfo{
+		{
+			AppName:       "myapp",
+			Environment:   "production",
+			TargetType:    TargetTypeTag,
+			TargetPath:    "tags/myapp/v1.0.0.json",
+			SourceTag:     "v1.0.0",
+			SourceImage:   "myapp:20250720-1045-abc1234",
+			PromotedBy:    "alice",
+			PromotedAt:    time.Date(2025, 7, 21, 14, 30, 0, 0, time.UTC),
+			ResolvedImage: "myapp:20250721-2118-f7a5a27",
+		},
+		{
+			AppName:     "myapp",
+			Environment: "staging",
+			TargetType:  TargetTypeImage,
+			TargetPath:  "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+			SourceImage: "myapp:20250721-2118-f7a5a27",
+			PromotedBy:  "bob",
+		},
+	}
+
+	output := FormatEnvironmentsWide(envs)
+
+	for _, expected := range []string{
+		"ENVIRONMENT", "PROMOTED BY", "PROMOTED AT",
+		"production", "tag", "myapp:20250721-2118-f7a5a27", "v1.0.0", "alice", "2025-07-21T14:30:00Z",
+		"staging", "image", "bob",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected wide output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
 func TestListApps(t *testing.T) {
 	ctx := context.Background()
 	mock := newMockS3ClientForList()
@@ -197,7 +335,7 @@ func TestListApps(t *testing.T) {
 
 	listService := NewListService(mock, "test-bucket")
 
-	apps, err := listService.ListApps(ctx)
+	apps, err := listService.ListApps(ctx, false, "")
 	if err != nil {
 		t.Fatalf("ListApps failed: %v", err)
 	}
@@ -215,6 +353,137 @@ func TestListApps(t *testing.T) {
 	}
 }
 
+func TestListApps_IncludeArchived(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	// One live app and one app that only exists under archive/
+	mock.files["images/liveapp/202507/liveapp-20250721-2118-f7a5a27.tar.gz"] = []byte("image")
+	mock.files["archive/oldapp/202505/oldapp-20250501-1000-aaa1111-archived-on-20250601-0900.tar.gz"] = []byte("image")
+
+	listService := NewListService(mock, "test-bucket")
+
+	// Without the flag, the archived-only app is invisible
+	apps, err := listService.ListApps(ctx, false, "")
+	if err != nil {
+		t.Fatalf("ListApps failed: %v", err)
+	}
+	if len(apps) != 1 || apps[0] != "liveapp" {
+		t.Errorf("Expected only liveapp, got %v", apps)
+	}
+
+	// With the flag, both apps appear
+	apps, err = listService.ListApps(ctx, true, "")
+	if err != nil {
+		t.Fatalf("ListApps with archived failed: %v", err)
+	}
+	if len(apps) != 2 {
+		t.Fatalf("Expected 2 apps, got %v", apps)
+	}
+	if apps[0] != "liveapp" || apps[1] != "oldapp" {
+		t.Errorf("Expected [liveapp oldapp], got %v", apps)
+	}
+}
+
+// failingListMock errors List calls for one prefix while behaving normally
+// everywhere else
+type failingListMock struct {
+	*mockS3ClientForList
+	failPrefix string
+}
+
+func (m *failingListMock) List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	if strings.HasPrefix(prefix, m.failPrefix) {
+		return nil, errors.New("access denied")
+	}
+	return m.mockS3ClientForList.List(ctx, bucket, prefix)
+}
+
+func TestListApps_ListError(t *testing.T) {
+	ctx := context.Background()
+	mock := &failingListMock{
+		mockS3ClientForList: newMockS3ClientForList(),
+		failPrefix:          "tags/",
+	}
+	mock.files["images/app1/202507/app1-20250721-2118-f7a5a27.tar.gz"] = []byte("image")
+
+	listService := NewListService(mock, "test-bucket")
+
+	// An inaccessible prefix must be reported, not treated as empty
+	_, err := listService.ListApps(ctx, false, "")
+	if err == nil {
+		t.Fatal("Expected ListApps to surface the list error")
+	}
+	if !strings.Contains(err.Error(), "tags/") || !strings.Contains(err.Error(), "access denied") {
+		t.Errorf("Expected error to name the failing prefix, got: %v", err)
+	}
+}
+
+func TestLatestImages(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"] = []byte("image1")
+	mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"] = []byte("image2")
+	mock.files["images/myapp/202506/myapp-20250615-0930-def5678.tar.gz"] = []byte("image3")
+	mock.files["images/otherapp/202507/otherapp-20250710-1200-xyz9999.tar.gz"] = []byte("image4")
+	mock.files["images/otherapp/202507/otherapp-20250722-0800-aaa1111.tar.gz"] = []byte("image5")
+	mock.files["pointers/emptyapp/production.json"] = []byte("{}")
+
+	listService := NewListService(mock, "test-bucket")
+
+	latest := listService.LatestImages(ctx, []string{"myapp", "otherapp", "emptyapp"})
+
+	if latest["myapp"] != "20250721-2118-f7a5a27" {
+		t.Errorf("Expected newest myapp tag, got %s", latest["myapp"])
+	}
+	if latest["otherapp"] != "20250722-0800-aaa1111" {
+		t.Errorf("Expected newest otherapp tag, got %s", latest["otherapp"])
+	}
+	if latest["emptyapp"] != "" {
+		t.Errorf("Expected empty tag for app without images, got %s", latest["emptyapp"])
+	}
+}
+
+func TestFindByImageID(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"] = []byte("image1")
+	mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.json"] = []byte(`{
+		"image_tag": "myapp:20250721-2118-f7a5a27",
+		"image_id": "sha256:aaa111"
+	}`)
+	mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"] = []byte("image2")
+	mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.json"] = []byte(`{
+		"image_tag": "myapp:20250720-1045-abc1234",
+		"image_id": "sha256:bbb222"
+	}`)
+
+	listService := NewListService(mock, "test-bucket")
+
+	matches, err := listService.FindByImageID(ctx, "myapp", "sha256:aaa111")
+	if err != nil {
+		t.Fatalf("FindByImageID failed: %v", err)
+	}
+
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Tag != "20250721-2118-f7a5a27" {
+		t.Errorf("Expected match tag '20250721-2118-f7a5a27', got '%s'", matches[0].Tag)
+	}
+
+	// Unknown ID matches nothing
+	matches, err = listService.FindByImageID(ctx, "myapp", "sha256:unknown")
+	if err != nil {
+		t.Fatalf("FindByImageID failed: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("Expected no matches, got %d", len(matches))
+	}
+}
+
 func TestGetTagForEnvironment(t *testing.T) {
 	ctx := context.Background()
 	mock := newMockS3ClientForList()
@@ -256,3 +525,209 @@ func TestGetTagForEnvironment(t *testing.T) {
 		t.Errorf("Expected empty tag for direct promotion, got '%s'", tag)
 	}
 }
+
+func TestMarkDanglingTags(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	// v1.0.0 targets an image that exists, v2.0.0 targets a deleted one
+	mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"] = []byte("image")
+	mock.files["tags/myapp/v1.0.0.json"] = []byte(`{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		"source_image": "myapp:20250721-2118-f7a5a27"
+	}`)
+	mock.files["tags/myapp/v2.0.0.json"] = []byte(`{
+		"target_type": "image",
+		"target_path": "images/myapp/202506/myapp-20250615-0930-def5678.tar.gz",
+		"source_image": "myapp:20250615-0930-def5678"
+	}`)
+
+	listService := NewListService(mock, "test-bucket")
+
+	tags, err := listService.ListTags(ctx, "myapp")
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+
+	if err := listService.MarkDanglingTags(ctx, tags); err != nil {
+		t.Fatalf("MarkDanglingTags failed: %v", err)
+	}
+
+	for _, tag := range tags {
+		switch tag.Version {
+		case "v1.0.0":
+			if tag.Dangling {
+				t.Errorf("v1.0.0 should not be dangling")
+			}
+		case "v2.0.0":
+			if !tag.Dangling {
+				t.Errorf("v2.0.0 should be dangling")
+			}
+		}
+	}
+}
+
+func TestListApps_ScopedToApp(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	mock.files["images/app1/202507/app1-20250721-2118-f7a5a27.tar.gz"] = []byte("image")
+	mock.files["tags/app2/v1.0.0.json"] = []byte("{}")
+
+	listService := NewListService(mock, "test-bucket")
+
+	apps, err := listService.ListApps(ctx, false, "app1")
+	if err != nil {
+		t.Fatalf("ListApps failed: %v", err)
+	}
+
+	if len(apps) != 1 || apps[0] != "app1" {
+		t.Errorf("Expected only app1, got %v", apps)
+	}
+}
+
+func TestListImages_EmitsStreamElements(t *testing.T) {
+	mock := newMockS3ClientForList()
+	mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"] = []byte("data")
+	mock.files["images/myapp/202507/myapp-20250722-0900-abc1234.tar.gz"] = []byte("data")
+
+	service := NewListService(mock, "test-bucket")
+
+	var emitted []interface{}
+	service.SetEmitter(func(item interface{}) {
+		emitted = append(emitted, item)
+	})
+
+	images, err := service.ListImages(context.Background(), "myapp", "")
+
+	if err != nil {
+		t.Fatalf("ListImages failed: %v", err)
+	}
+	if len(images) != 2 {
+		t.Errorf("Expected 2 images, got %d", len(images))
+	}
+	if len(emitted) != 2 {
+		t.Errorf("Expected 2 emitted elements, got %d", len(emitted))
+	}
+	for _, item := range emitted {
+		if _, ok := item.(ImageInfoJSON); !ok {
+			t.Errorf("Expected emitted element to be ImageInfoJSON, got %T", item)
+		}
+	}
+}
+
+func TestListAll(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"] = []byte("image1")
+	mock.files["images/myapp/202506/myapp-20250615-0930-def5678.tar.gz"] = []byte("image2")
+	tagData := `{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		"source_image": "myapp:20250721-2118-f7a5a27"
+	}`
+	mock.files["tags/myapp/v1.0.0.json"] = []byte(tagData)
+	mock.files["pointers/myapp/production.json"] = []byte(tagData)
+
+	listService := NewListService(mock, "test-bucket")
+
+	images, tags, envs, err := listService.ListAll(ctx, "myapp")
+	if err != nil {
+		t.Fatalf("ListAll failed: %v", err)
+	}
+
+	if len(images) != 2 {
+		t.Errorf("Expected 2 images, got %d", len(images))
+	}
+	if len(tags) != 1 {
+		t.Errorf("Expected 1 tag, got %d", len(tags))
+	}
+	if len(envs) != 1 {
+		t.Errorf("Expected 1 environment, got %d", len(envs))
+	}
+}
+
+func TestEnrichChecksums(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockS3ClientForList()
+
+	mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"] = []byte("image1")
+	mock.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.json"] = []byte(`{
+		"checksum": "aaa111",
+		"image_tag": "myapp:20250721-2118-f7a5a27"
+	}`)
+	// Second image has no metadata object - its checksum stays empty
+	mock.files["images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"] = []byte("image2")
+
+	listService := NewListService(mock, "test-bucket")
+
+	images, err := listService.ListImages(ctx, "myapp", "")
+	if err != nil {
+		t.Fatalf("ListImages failed: %v", err)
+	}
+	if len(images) != 2 {
+		t.Fatalf("Expected 2 images, got %d", len(images))
+	}
+
+	listService.EnrichChecksums(ctx, images)
+
+	byTag := map[string]string{}
+	for _, img := range images {
+		byTag[img.Tag] = img.Checksum
+	}
+	if byTag["20250721-2118-f7a5a27"] != "aaa111" {
+		t.Errorf("Expected checksum 'aaa111', got '%s'", byTag["20250721-2118-f7a5a27"])
+	}
+	if byTag["20250720-1045-abc1234"] != "" {
+		t.Errorf("Expected empty checksum for image without metadata, got '%s'", byTag["20250720-1045-abc1234"])
+	}
+
+	// Checksum carries through to the JSON form
+	if images[0].ToJSON().Checksum != images[0].Checksum {
+		t.Errorf("Expected ToJSON to carry the checksum")
+	}
+}
+
+func TestFormatEnvironmentsGraph(t *testing.T) {
+	envs := []EnvInfo{
+		{Environment: "production", TargetType: TargetTypeTag, SourceTag: "v1.2.0", ResolvedImage: "myapp:20250721-2118-f7a5a27"},
+		{Environment: "staging", TargetType: TargetTypeImage, SourceImage: "myapp:20250722-0910-abc1234"},
+	}
+
+	out := FormatEnvironmentsGraph("myapp", envs)
+
+	for _, want := range []string{
+		"myapp\n",
+		"  production -> v1.2.0 -> myapp:20250721-2118-f7a5a27\n",
+		"  staging -> myapp:20250722-0910-abc1234\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("graph output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatEnvironmentsDOT(t *testing.T) {
+	envs := []EnvInfo{
+		{Environment: "production", TargetType: TargetTypeTag, SourceTag: "v1.2.0", ResolvedImage: "myapp:20250721-2118-f7a5a27"},
+		{Environment: "staging", TargetType: TargetTypeImage, SourceImage: "myapp:20250722-0910-abc1234"},
+	}
+
+	out := FormatEnvironmentsDOT("myapp", envs)
+
+	for _, want := range []string{
+		`digraph "myapp" {`,
+		`"production" -> "v1.2.0";`,
+		`"v1.2.0" -> "myapp:20250721-2118-f7a5a27";`,
+		`"staging" -> "myapp:20250722-0910-abc1234";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "}\n") {
+		t.Errorf("DOT output not closed:\n%s", out)
+	}
+}
@@ -92,6 +92,9 @@ func (l *logger) log(level, msg string, args ...interface{}) {
 	}
 
 	logMsg := fmt.Sprintf("[%s] %s %s", level, timestamp, formattedMsg)
+	if traceID != "" {
+		logMsg = fmt.Sprintf("[%s] %s [trace:%s] %s", level, timestamp, traceID, formattedMsg)
+	}
 	fmt.Fprintln(os.Stderr, logMsg)
 }
 
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryS3Client is a map-backed, thread-safe S3Client. It backs the
+// --backend memory dev mode for trying s3dock without any cloud, and is
+// exported so tests can run commands end-to-end against a real (if
+// ephemeral) store instead of hand-rolled per-file mocks. State lives for
+// the lifetime of the process.
+type MemoryS3Client struct {
+	mu      sync.RWMutex
+	objects map[string]map[string][]byte // bucket -> key -> data
+}
+
+func NewMemoryS3Client() *MemoryS3Client {
+	return &MemoryS3Client{
+		objects: make(map[string]map[string][]byte),
+	}
+}
+
+// Put seeds an object directly, bypassing the reader plumbing (test setup)
+func (m *MemoryS3Client) Put(bucket, key string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.objects[bucket] == nil {
+		m.objects[bucket] = make(map[string][]byte)
+	}
+	m.objects[bucket][key] = data
+}
+
+func (m *MemoryS3Client) Upload(ctx context.Context, bucket, key string, data io.Reader) error {
+	buf, err := io.ReadAll(data)
+	if err != nil {
+		return fmt.Errorf("failed to read upload data: %w", err)
+	}
+	m.Put(bucket, key, buf)
+	return nil
+}
+
+func (m *MemoryS3Client) UploadWithProgress(ctx context.Context, bucket, key string, data io.Reader, size int64, description string) error {
+	return m.Upload(ctx, bucket, key, data)
+}
+
+func (m *MemoryS3Client) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.objects[bucket][key]
+	return ok, nil
+}
+
+func (m *MemoryS3Client) Download(ctx context.Context, bucket, key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.objects[bucket][key]
+	if !ok {
+		return nil, fmt.Errorf("object not found: s3://%s/%s", bucket, key)
+	}
+	// Copy so callers can't mutate the stored object
+	out := make([]byte, len(data))
+	copy(out, data)
+	return out, nil
+}
+
+func (m *MemoryS3Client) DownloadStream(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	data, err := m.Download(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *MemoryS3Client) Copy(ctx context.Context, bucket, srcKey, dstKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[bucket][srcKey]
+	if !ok {
+		return fmt.Errorf("object not found: s3://%s/%s", bucket, srcKey)
+	}
+	out := make([]byte, len(data))
+	copy(out, data)
+	m.objects[bucket][dstKey] = out
+	return nil
+}
+
+// Delete is idempotent like S3's: deleting a missing key is not an error
+func (m *MemoryS3Client) Delete(ctx context.Context, bucket, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects[bucket], key)
+	return nil
+}
+
+func (m *MemoryS3Client) List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var keys []string
+	for key := range m.objects[bucket] {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// HeadETag reports the MD5 of the stored object, matching the ETag S3
+// returns for single-part uploads.
+func (m *MemoryS3Client) HeadETag(ctx context.Context, bucket, key string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.objects[bucket][key]
+	if !ok {
+		return "", fmt.Errorf("object not found: s3://%s/%s", bucket, key)
+	}
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// memoryBackend, when set, redirects NewBackendS3Client to a process-local
+// in-memory store. main sets it once at startup from --backend memory.
+var memoryBackend *MemoryS3Client
+
+// UseMemoryBackend switches this process to the in-memory S3 backend
+// (--backend memory). Repeated calls keep the same store.
+func UseMemoryBackend() {
+	if memoryBackend == nil {
+		memoryBackend = NewMemoryS3Client()
+	}
+}
+
+// NewBackendS3Client returns the S3 client selected for this invocation:
+// the shared in-memory store under --backend memory, otherwise a real AWS
+// client.
+func NewBackendS3Client(ctx context.Context) (S3Client, error) {
+	if memoryBackend != nil {
+		return memoryBackend, nil
+	}
+	return NewS3Client(ctx)
+}
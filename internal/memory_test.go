@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryS3Client_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	client := NewMemoryS3Client()
+
+	err := client.Upload(ctx, "test-bucket", "images/myapp/file.tar.gz", strings.NewReader("image data"))
+	assert.NoError(t, err)
+
+	exists, err := client.Exists(ctx, "test-bucket", "images/myapp/file.tar.gz")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	data, err := client.Download(ctx, "test-bucket", "images/myapp/file.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, "image data", string(data))
+
+	stream, err := client.DownloadStream(ctx, "test-bucket", "images/myapp/file.tar.gz")
+	assert.NoError(t, err)
+	streamed, err := io.ReadAll(stream)
+	stream.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, "image data", string(streamed))
+
+	_, err = client.Download(ctx, "test-bucket", "missing-key")
+	assert.Error(t, err)
+}
+
+func TestMemoryS3Client_List(t *testing.T) {
+	ctx := context.Background()
+	client := NewMemoryS3Client()
+	client.Put("test-bucket", "images/myapp/b.tar.gz", []byte("b"))
+	client.Put("test-bucket", "images/myapp/a.tar.gz", []byte("a"))
+	client.Put("test-bucket", "tags/myapp/v1.0.0.json", []byte("{}"))
+
+	keys, err := client.List(ctx, "test-bucket", "images/")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"images/myapp/a.tar.gz", "images/myapp/b.tar.gz"}, keys)
+}
+
+func TestMemoryS3Client_CopyAndDelete(t *testing.T) {
+	ctx := context.Background()
+	client := NewMemoryS3Client()
+	client.Put("test-bucket", "images/myapp/file.tar.gz", []byte("image data"))
+
+	err := client.Copy(ctx, "test-bucket", "images/myapp/file.tar.gz", "archive/myapp/file.tar.gz")
+	assert.NoError(t, err)
+
+	data, err := client.Download(ctx, "test-bucket", "archive/myapp/file.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, "image data", string(data))
+
+	err = client.Delete(ctx, "test-bucket", "images/myapp/file.tar.gz")
+	assert.NoError(t, err)
+	exists, err := client.Exists(ctx, "test-bucket", "images/myapp/file.tar.gz")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// Deleting a missing key is idempotent, like S3
+	err = client.Delete(ctx, "test-bucket", "images/myapp/file.tar.gz")
+	assert.NoError(t, err)
+
+	err = client.Copy(ctx, "test-bucket", "missing-key", "somewhere")
+	assert.Error(t, err)
+}
+
+func TestMemoryS3Client_HeadETag(t *testing.T) {
+	ctx := context.Background()
+	client := NewMemoryS3Client()
+	client.Put("test-bucket", "images/myapp/file.tar.gz", []byte("image data"))
+
+	etag, err := client.HeadETag(ctx, "test-bucket", "images/myapp/file.tar.gz")
+
+	assert.NoError(t, err)
+	// MD5 of "image data"
+	assert.Equal(t, "e09a574ca3760a3e28a3e5920fe4627e", etag)
+
+	_, err = client.HeadETag(ctx, "test-bucket", "missing-key")
+	assert.Error(t, err)
+}
+
+func TestMemoryS3Client_ConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	client := NewMemoryS3Client()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := "images/myapp/" + string(rune('a'+i)) + ".tar.gz"
+			_ = client.Upload(ctx, "test-bucket", key, strings.NewReader("data"))
+			_, _ = client.List(ctx, "test-bucket", "images/")
+		}(i)
+	}
+	wg.Wait()
+
+	keys, err := client.List(ctx, "test-bucket", "images/")
+	assert.NoError(t, err)
+	assert.Len(t, keys, 10)
+}
+
+func TestMemoryS3Client_WorksWithListService(t *testing.T) {
+	ctx := context.Background()
+	client := NewMemoryS3Client()
+	client.Put("test-bucket", "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz", []byte("image"))
+
+	listService := NewListService(client, "test-bucket")
+
+	images, err := listService.ListImages(ctx, "myapp", "")
+
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, "20250721-2118-f7a5a27", images[0].Tag)
+}
+
+func TestNewBackendS3Client_Memory(t *testing.T) {
+	defer func() { memoryBackend = nil }()
+
+	UseMemoryBackend()
+
+	client, err := NewBackendS3Client(context.Background())
+	assert.NoError(t, err)
+	assert.Same(t, memoryBackend, client)
+
+	// Repeated calls keep the same store
+	UseMemoryBackend()
+	again, err := NewBackendS3Client(context.Background())
+	assert.NoError(t, err)
+	assert.Same(t, client, again)
+}
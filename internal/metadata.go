@@ -2,20 +2,47 @@ package internal
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"time"
 )
 
+// ImageMetadataSchemaVersion is the schema stamped on newly written image
+// metadata. ImageMetadataFromJSON upgrades older objects in-memory, so code
+// past that point only ever sees the current version.
+const ImageMetadataSchemaVersion = 1
+
 type ImageMetadata struct {
-	Checksum  string    `json:"checksum"`
+	SchemaVersion int    `json:"schema_version,omitempty"`
+	Checksum      string `json:"checksum"`
+
+	// ChecksumSHA256 is recorded alongside the legacy MD5 Checksum so
+	// comparisons can upgrade to the stronger algorithm once both sides
+	// carry it (see CompareChecksums).
+	ChecksumSHA256 string `json:"checksum_sha256,omitempty"`
+
 	Size      int64     `json:"size"`
 	CreatedAt time.Time `json:"created_at"`
 	GitHash   string    `json:"git_hash"`
 	GitTime   string    `json:"git_time"`
 	ImageTag  string    `json:"image_tag"`
 	AppName   string    `json:"app_name"`
+	ImageID   string    `json:"image_id,omitempty"` // Docker image ID (sha256:...), if recorded at push time
+
+	// DockerfileHash fingerprints the Dockerfile used for the build (push
+	// --dockerfile-hash-label), distinguishing pushes from the same commit
+	// built with different Dockerfiles.
+	DockerfileHash string `json:"dockerfile_hash,omitempty"`
+
+	Extra map[string]string `json:"extra,omitempty"` // Free-form annotations attached via push --meta key=value
+
+	// ETag is the S3 ETag of the uploaded image object, recorded after upload
+	// so later operations can detect changes with a cheap HEAD instead of
+	// re-downloading to re-checksum.
+	ETag string `json:"etag,omitempty"`
 }
 
 func (m *ImageMetadata) ToJSON() ([]byte, error) {
@@ -27,31 +54,73 @@ func ImageMetadataFromJSON(data []byte) (*ImageMetadata, error) {
 	if err := json.Unmarshal(data, &metadata); err != nil {
 		return nil, err
 	}
+	migrateImageMetadata(&metadata)
 	return &metadata, nil
 }
 
+// migrateImageMetadata upgrades metadata written by older clients in-memory.
+// v0 objects predate the schema_version field but otherwise carry all current
+// fields, so only the version itself needs stamping today; future format
+// changes (checksum algorithms, compression fields) default here.
+func migrateImageMetadata(m *ImageMetadata) {
+	if m.SchemaVersion > ImageMetadataSchemaVersion {
+		// Written by a newer client; unknown fields were silently dropped
+		// during parsing, so flag it rather than failing outright
+		LogInfo("Image metadata has schema version %d, newer than the supported %d - fields added by newer clients are ignored", m.SchemaVersion, ImageMetadataSchemaVersion)
+		return
+	}
+	if m.SchemaVersion >= ImageMetadataSchemaVersion {
+		return
+	}
+	m.SchemaVersion = ImageMetadataSchemaVersion
+}
+
 func CalculateMetadata(data io.Reader, gitHash, gitTime, imageTag, appName string) (*ImageMetadata, int64, error) {
-	hasher := md5.New()
-	size, err := io.Copy(hasher, data)
+	md5Hasher := md5.New()
+	sha256Hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(md5Hasher, sha256Hasher), data)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to calculate checksum: %w", err)
 	}
 
-	checksum := fmt.Sprintf("%x", hasher.Sum(nil))
-
 	metadata := &ImageMetadata{
-		Checksum:  checksum,
-		Size:      size,
-		CreatedAt: time.Now(),
-		GitHash:   gitHash,
-		GitTime:   gitTime,
-		ImageTag:  imageTag,
-		AppName:   appName,
+		SchemaVersion:  ImageMetadataSchemaVersion,
+		Checksum:       fmt.Sprintf("%x", md5Hasher.Sum(nil)),
+		ChecksumSHA256: fmt.Sprintf("%x", sha256Hasher.Sum(nil)),
+		Size:           size,
+		CreatedAt:      time.Now(),
+		GitHash:        gitHash,
+		GitTime:        gitTime,
+		ImageTag:       imageTag,
+		AppName:        appName,
 	}
 
 	return metadata, size, nil
 }
 
+// CompareChecksums compares two metadata records using the strongest
+// algorithm present in both, falling back to the legacy MD5 checksum. This
+// keeps push's skip-if-unchanged comparison correct across an algorithm
+// upgrade: records written before ChecksumSHA256 existed still compare by
+// MD5, while fully upgraded pairs never rely on it. Returns the algorithm
+// used alongside the verdict.
+func CompareChecksums(existing, candidate *ImageMetadata) (string, bool) {
+	if existing.ChecksumSHA256 != "" && candidate.ChecksumSHA256 != "" {
+		return "sha256", existing.ChecksumSHA256 == candidate.ChecksumSHA256
+	}
+	return "md5", existing.Checksum == candidate.Checksum
+}
+
+// HashDockerfile fingerprints a Dockerfile's content for the DockerfileHash
+// metadata field.
+func HashDockerfile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Dockerfile %s: %w", path, err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
 func GenerateMetadataKey(imageS3Key string) string {
 	// Convert images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz
 	// to images/myapp/202507/myapp-20250721-2118-f7a5a27.json
@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -28,6 +30,62 @@ func TestCalculateMetadata(t *testing.T) {
 	assert.True(t, metadata.CreatedAt.Before(time.Now().Add(time.Second)))
 }
 
+func TestCalculateMetadata_SHA256(t *testing.T) {
+	data := strings.NewReader("test image data")
+
+	metadata, _, err := CalculateMetadata(data, "abc1234", "20250721-1430", "myapp:latest", "myapp")
+
+	assert.NoError(t, err)
+	// SHA256 of "test image data"
+	assert.Equal(t, "fc50f1a3c9cbf0154d7dc87998446624c8b78f84c5cbef4f8139a0c8be1e4976", metadata.ChecksumSHA256)
+}
+
+func TestCompareChecksums(t *testing.T) {
+	cases := []struct {
+		name          string
+		existing, new *ImageMetadata
+		algorithm     string
+		match         bool
+	}{
+		{
+			name:      "both sha256, match",
+			existing:  &ImageMetadata{Checksum: "md5-a", ChecksumSHA256: "sha-x"},
+			new:       &ImageMetadata{Checksum: "md5-b", ChecksumSHA256: "sha-x"},
+			algorithm: "sha256",
+			match:     true,
+		},
+		{
+			name:      "both sha256, mismatch wins over matching md5",
+			existing:  &ImageMetadata{Checksum: "md5-a", ChecksumSHA256: "sha-x"},
+			new:       &ImageMetadata{Checksum: "md5-a", ChecksumSHA256: "sha-y"},
+			algorithm: "sha256",
+			match:     false,
+		},
+		{
+			name:      "existing predates sha256, falls back to md5",
+			existing:  &ImageMetadata{Checksum: "md5-a"},
+			new:       &ImageMetadata{Checksum: "md5-a", ChecksumSHA256: "sha-x"},
+			algorithm: "md5",
+			match:     true,
+		},
+		{
+			name:      "new lacks sha256, falls back to md5",
+			existing:  &ImageMetadata{Checksum: "md5-a", ChecksumSHA256: "sha-x"},
+			new:       &ImageMetadata{Checksum: "md5-b"},
+			algorithm: "md5",
+			match:     false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			algorithm, match := CompareChecksums(tc.existing, tc.new)
+			assert.Equal(t, tc.algorithm, algorithm)
+			assert.Equal(t, tc.match, match)
+		})
+	}
+}
+
 func TestImageMetadata_ToJSON(t *testing.T) {
 	metadata := &ImageMetadata{
 		Checksum:  "abc123",
@@ -102,3 +160,114 @@ func TestGenerateArchiveKeys(t *testing.T) {
 	assert.Equal(t, expectedImageKey, archiveImageKey)
 	assert.Equal(t, expectedMetaKey, archiveMetaKey)
 }
+
+func TestImageMetadataFromJSON_V0Migration(t *testing.T) {
+	// A v0 object written before schema_version existed
+	data := []byte(`{
+		"checksum": "abc123",
+		"size": 1024,
+		"git_hash": "f7a5a27",
+		"git_time": "20250721-2118",
+		"image_tag": "myapp:20250721-2118-f7a5a27",
+		"app_name": "myapp"
+	}`)
+
+	metadata, err := ImageMetadataFromJSON(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ImageMetadataSchemaVersion, metadata.SchemaVersion)
+	assert.Equal(t, "abc123", metadata.Checksum)
+	assert.Equal(t, "myapp", metadata.AppName)
+}
+
+func TestImageMetadataFromJSON_NewerVersionPreserved(t *testing.T) {
+	// A future client wrote this; the version must not be downgraded and
+	// the known fields still parse
+	data := []byte(`{
+		"schema_version": 99,
+		"checksum": "abc123",
+		"size": 1024,
+		"git_hash": "f7a5a27",
+		"git_time": "20250721-2118",
+		"image_tag": "myapp:20250721-2118-f7a5a27",
+		"app_name": "myapp",
+		"field_from_the_future": true
+	}`)
+
+	metadata, err := ImageMetadataFromJSON(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 99, metadata.SchemaVersion)
+	assert.Equal(t, "abc123", metadata.Checksum)
+}
+
+func TestImageMetadata_ExtraRoundTrip(t *testing.T) {
+	metadata, _, err := CalculateMetadata(strings.NewReader("test image data"), "f7a5a27", "20250721-2118", "myapp:20250721-2118-f7a5a27", "myapp")
+	assert.NoError(t, err)
+
+	metadata.Extra = map[string]string{"ticket": "OPS-1234", "change": "CR-42"}
+
+	data, err := metadata.ToJSON()
+	assert.NoError(t, err)
+
+	parsed, err := ImageMetadataFromJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "OPS-1234", parsed.Extra["ticket"])
+	assert.Equal(t, "CR-42", parsed.Extra["change"])
+}
+
+func TestImageMetadata_NoExtraOmitted(t *testing.T) {
+	metadata, _, err := CalculateMetadata(strings.NewReader("test image data"), "f7a5a27", "20250721-2118", "myapp:20250721-2118-f7a5a27", "myapp")
+	assert.NoError(t, err)
+
+	data, err := metadata.ToJSON()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), `"extra"`)
+
+	parsed, err := ImageMetadataFromJSON(data)
+	assert.NoError(t, err)
+	assert.Nil(t, parsed.Extra)
+}
+
+func TestHashDockerfile(t *testing.T) {
+	dir := t.TempDir()
+	dockerfile := filepath.Join(dir, "Dockerfile")
+	err := os.WriteFile(dockerfile, []byte("FROM alpine:3.20\nRUN true\n"), 0644)
+	assert.NoError(t, err)
+
+	hash1, err := HashDockerfile(dockerfile)
+	assert.NoError(t, err)
+	assert.Len(t, hash1, 64) // hex sha256
+
+	// Stable for identical content
+	hash2, err := HashDockerfile(dockerfile)
+	assert.NoError(t, err)
+	assert.Equal(t, hash1, hash2)
+
+	// Different content yields a different fingerprint
+	err = os.WriteFile(dockerfile, []byte("FROM alpine:3.21\nRUN true\n"), 0644)
+	assert.NoError(t, err)
+	hash3, err := HashDockerfile(dockerfile)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash1, hash3)
+}
+
+func TestHashDockerfile_Missing(t *testing.T) {
+	_, err := HashDockerfile(filepath.Join(t.TempDir(), "Dockerfile"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read Dockerfile")
+}
+
+func TestImageMetadata_DockerfileHashRoundTrip(t *testing.T) {
+	metadata, _, err := CalculateMetadata(strings.NewReader("test image data"), "f7a5a27", "20250721-2118", "myapp:20250721-2118-f7a5a27", "myapp")
+	assert.NoError(t, err)
+
+	metadata.DockerfileHash = "deadbeef"
+
+	data, err := metadata.ToJSON()
+	assert.NoError(t, err)
+
+	parsed, err := ImageMetadataFromJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "deadbeef", parsed.DockerfileHash)
+}
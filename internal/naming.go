@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultPathTemplate is the image key layout used when naming.path_template
+// is not configured.
+const DefaultPathTemplate = "images/{app}/{year_month}/{filename}"
+
+// ExpandPathTemplate expands an image key template into a concrete S3 key.
+// Supported variables: {app}, {year_month}, {filename}, {git_hash},
+// {git_time}. {filename} is the canonical <app>-<git_time>-<git_hash>.tar.gz
+// name, which listing relies on to recover image info under custom layouts.
+func ExpandPathTemplate(template, appName, yearMonth, gitHash, gitTime string) string {
+	if template == "" {
+		template = DefaultPathTemplate
+	}
+
+	filename := fmt.Sprintf("%s-%s-%s.tar.gz", appName, gitTime, gitHash)
+
+	replacer := strings.NewReplacer(
+		"{app}", appName,
+		"{year_month}", yearMonth,
+		"{filename}", filename,
+		"{git_hash}", gitHash,
+		"{git_time}", gitTime,
+	)
+	return replacer.Replace(template)
+}
+
+// resolveImageKey constructs the canonical S3 key for an image. The
+// year-month directory is derived from the git commit time rather than the
+// wall clock, so the pusher, tagger, and promoter all agree on the key even
+// when a push and a later lookup straddle a month boundary.
+func resolveImageKey(template, appName, gitTime, gitHash string) string {
+	yearMonth := ""
+	if len(gitTime) >= 6 {
+		yearMonth = gitTime[:6]
+	}
+	return ExpandPathTemplate(template, appName, yearMonth, gitHash, gitTime)
+}
+
+// ExpandPathPrefix returns the static listing prefix of a template: {app}
+// (and {year_month} when given) are expanded, and the result is truncated at
+// the first remaining variable so List enumerates everything beneath it.
+func ExpandPathPrefix(template, appName, yearMonth string) string {
+	if template == "" {
+		template = DefaultPathTemplate
+	}
+
+	expanded := strings.ReplaceAll(template, "{app}", appName)
+	if yearMonth != "" {
+		expanded = strings.ReplaceAll(expanded, "{year_month}", yearMonth)
+	}
+
+	if idx := strings.Index(expanded, "{"); idx != -1 {
+		expanded = expanded[:idx]
+	}
+	return expanded
+}
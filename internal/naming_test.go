@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPathTemplate_Default(t *testing.T) {
+	key := ExpandPathTemplate("", "myapp", "202507", "f7a5a27", "20250721-2118")
+
+	assert.Equal(t, "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz", key)
+}
+
+func TestExpandPathTemplate_CustomVariables(t *testing.T) {
+	key := ExpandPathTemplate("docker/{app}/{git_time}-{git_hash}.tar.gz", "myapp", "202507", "f7a5a27", "20250721-2118")
+
+	assert.Equal(t, "docker/myapp/20250721-2118-f7a5a27.tar.gz", key)
+}
+
+func TestResolveImageKey(t *testing.T) {
+	key := resolveImageKey("", "myapp", "20250721-2118", "f7a5a27")
+
+	assert.Equal(t, "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz", key)
+}
+
+func TestResolveImageKey_MonthBoundary(t *testing.T) {
+	// The year-month directory follows the commit time, not the wall clock
+	key := resolveImageKey("", "myapp", "20250131-2359", "abc1234")
+
+	assert.Equal(t, "images/myapp/202501/myapp-20250131-2359-abc1234.tar.gz", key)
+}
+
+func TestResolveImageKey_CustomTemplate(t *testing.T) {
+	key := resolveImageKey("docker/{app}/{year_month}/{filename}", "myapp", "20250721-2118", "f7a5a27")
+
+	assert.Equal(t, "docker/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz", key)
+}
+
+func TestExpandPathPrefix(t *testing.T) {
+	assert.Equal(t, "images/myapp/", ExpandPathPrefix("", "myapp", ""))
+	assert.Equal(t, "images/myapp/202507/", ExpandPathPrefix("", "myapp", "202507"))
+	assert.Equal(t, "docker/myapp/", ExpandPathPrefix("docker/{app}/{git_time}-{git_hash}.tar.gz", "myapp", ""))
+}
+
+func TestPathTemplate_PushListRoundTrip(t *testing.T) {
+	template := "docker/{app}/{filename}"
+	mock := newMockS3ClientForList()
+
+	// Store an image at the key push would generate for this template
+	key := ExpandPathTemplate(template, "myapp", "202507", "f7a5a27", "20250721-2118")
+	mock.files[key] = []byte("image-data")
+
+	list := NewListService(mock, "test-bucket")
+	list.SetPathTemplate(template)
+
+	images, err := list.ListImages(context.Background(), "myapp", "")
+
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, "myapp", images[0].AppName)
+	assert.Equal(t, "20250721-2118-f7a5a27", images[0].Tag)
+	assert.Equal(t, key, images[0].S3Path)
+	assert.Equal(t, "202507", images[0].YearMonth)
+}
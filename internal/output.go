@@ -13,6 +13,9 @@ const (
 	OutputFormatText OutputFormat = iota
 	// OutputFormatJSON is JSON output for programmatic consumption
 	OutputFormatJSON
+	// OutputFormatNDJSON streams each result element as one JSON line as it
+	// is produced, instead of buffering the full result
+	OutputFormatNDJSON
 )
 
 // OutputConfig holds the global output configuration
@@ -38,6 +41,18 @@ func IsJSONOutput() bool {
 	return globalOutputConfig.Format == OutputFormatJSON
 }
 
+// IsNDJSONOutput returns true if newline-delimited JSON streaming is enabled
+func IsNDJSONOutput() bool {
+	return globalOutputConfig.Format == OutputFormatNDJSON
+}
+
+// OutputStreamItem writes one result element as a single JSON line to stdout.
+// Commands wire this into streaming-capable services when --output ndjson is
+// set, so consumers can process elements without waiting for the full scan.
+func OutputStreamItem(v interface{}) error {
+	return json.NewEncoder(os.Stdout).Encode(v)
+}
+
 // OutputJSON writes a value as JSON to stdout
 func OutputJSON(v interface{}) error {
 	encoder := json.NewEncoder(os.Stdout)
@@ -95,6 +95,46 @@ func TestCommandResult(t *testing.T) {
 	}
 }
 
+func TestOutputStreamItem_EachLineValidJSON(t *testing.T) {
+	// Capture stdout
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	items := []ImageInfoJSON{
+		{AppName: "myapp", Tag: "20250721-2118-f7a5a27", S3Path: "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz", YearMonth: "202507"},
+		{AppName: "myapp", Tag: "20250722-0910-abc1234", S3Path: "images/myapp/202507/myapp-20250722-0910-abc1234.tar.gz", YearMonth: "202507"},
+	}
+	for _, item := range items {
+		if err := OutputStreamItem(item); err != nil {
+			t.Errorf("OutputStreamItem returned error: %v", err)
+		}
+	}
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	lines := bytes.Split(bytes.TrimRight(buf.Bytes(), "\n"), []byte("\n"))
+
+	if len(lines) != len(items) {
+		t.Fatalf("Expected %d lines, got %d", len(items), len(lines))
+	}
+
+	// Each line must be independently valid JSON
+	for i, line := range lines {
+		var decoded ImageInfoJSON
+		if err := json.Unmarshal(line, &decoded); err != nil {
+			t.Errorf("Line %d is not valid JSON: %v", i, err)
+			continue
+		}
+		if decoded.Tag != items[i].Tag {
+			t.Errorf("Line %d: expected tag %s, got %s", i, items[i].Tag, decoded.Tag)
+		}
+	}
+}
+
 func TestOutputResult_JSONFormat(t *testing.T) {
 	// Reset to default after test
 	defer SetOutputFormat(OutputFormatText)
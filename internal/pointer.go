@@ -16,15 +16,24 @@ const (
 	TargetTypeTag   TargetType = "tag"
 )
 
+// PointerMetadataSchemaVersion is the schema stamped on newly written
+// pointers. PointerMetadataFromJSON upgrades older objects in-memory.
+const PointerMetadataSchemaVersion = 1
+
 type PointerMetadata struct {
-	TargetType  TargetType `json:"target_type"`
-	TargetPath  string     `json:"target_path"`
-	PromotedAt  time.Time  `json:"promoted_at"`
-	PromotedBy  string     `json:"promoted_by"`
-	GitHash     string     `json:"git_hash"`
-	GitTime     string     `json:"git_time"`
-	SourceImage string     `json:"source_image,omitempty"` // Original image reference if tagged
-	SourceTag   string     `json:"source_tag,omitempty"`   // Source tag if promoted from tag
+	SchemaVersion int        `json:"schema_version,omitempty"`
+	TargetType    TargetType `json:"target_type"`
+	TargetPath    string     `json:"target_path"`
+	PromotedAt    time.Time  `json:"promoted_at"`
+	PromotedBy    string     `json:"promoted_by"`
+	GitHash       string     `json:"git_hash"`
+	GitTime       string     `json:"git_time"`
+	SourceImage   string     `json:"source_image,omitempty"` // Original image reference if tagged
+	SourceTag     string     `json:"source_tag,omitempty"`   // Source tag if promoted from tag
+	EffectiveAt   time.Time  `json:"effective_at,omitzero"`  // Set on pending pointers scheduled via promote --schedule
+
+	RequiresApproval bool   `json:"requires_approval,omitempty"` // Set on pending pointers awaiting a second approver
+	ApprovedBy       string `json:"approved_by,omitempty"`       // Who approved an approval-protected promotion
 }
 
 func (p *PointerMetadata) ToJSON() ([]byte, error) {
@@ -36,9 +45,29 @@ func PointerMetadataFromJSON(data []byte) (*PointerMetadata, error) {
 	if err := json.Unmarshal(data, &pointer); err != nil {
 		return nil, err
 	}
+	migratePointerMetadata(&pointer)
 	return &pointer, nil
 }
 
+// migratePointerMetadata upgrades pointers written by older clients
+// in-memory. v0 pointers predate the schema_version field; the earliest ones
+// also lack target_type and always targeted images directly.
+func migratePointerMetadata(p *PointerMetadata) {
+	if p.SchemaVersion > PointerMetadataSchemaVersion {
+		// Written by a newer client; unknown fields were silently dropped
+		// during parsing, so flag it rather than failing outright
+		LogInfo("Pointer has schema version %d, newer than the supported %d - fields added by newer clients are ignored", p.SchemaVersion, PointerMetadataSchemaVersion)
+		return
+	}
+	if p.SchemaVersion >= PointerMetadataSchemaVersion {
+		return
+	}
+	if p.TargetType == "" {
+		p.TargetType = TargetTypeImage
+	}
+	p.SchemaVersion = PointerMetadataSchemaVersion
+}
+
 func CreateImagePointer(imageS3Path, gitHash, gitTime, sourceImage string) (*PointerMetadata, error) {
 	promotedBy, err := getCurrentUser()
 	if err != nil {
@@ -46,13 +75,14 @@ func CreateImagePointer(imageS3Path, gitHash, gitTime, sourceImage string) (*Poi
 	}
 
 	return &PointerMetadata{
-		TargetType:  TargetTypeImage,
-		TargetPath:  imageS3Path,
-		PromotedAt:  time.Now(),
-		PromotedBy:  promotedBy,
-		GitHash:     gitHash,
-		GitTime:     gitTime,
-		SourceImage: sourceImage,
+		SchemaVersion: PointerMetadataSchemaVersion,
+		TargetType:    TargetTypeImage,
+		TargetPath:    imageS3Path,
+		PromotedAt:    time.Now(),
+		PromotedBy:    promotedBy,
+		GitHash:       gitHash,
+		GitTime:       gitTime,
+		SourceImage:   sourceImage,
 	}, nil
 }
 
@@ -63,17 +93,49 @@ func CreateTagPointer(tagS3Path, gitHash, gitTime, sourceImage, sourceTag string
 	}
 
 	return &PointerMetadata{
-		TargetType:  TargetTypeTag,
-		TargetPath:  tagS3Path,
-		PromotedAt:  time.Now(),
-		PromotedBy:  promotedBy,
-		GitHash:     gitHash,
-		GitTime:     gitTime,
-		SourceImage: sourceImage,
-		SourceTag:   sourceTag,
+		SchemaVersion: PointerMetadataSchemaVersion,
+		TargetType:    TargetTypeTag,
+		TargetPath:    tagS3Path,
+		PromotedAt:    time.Now(),
+		PromotedBy:    promotedBy,
+		GitHash:       gitHash,
+		GitTime:       gitTime,
+		SourceImage:   sourceImage,
+		SourceTag:     sourceTag,
 	}, nil
 }
 
+// validateKeyComponent rejects names that would produce unexpected S3 key
+// structures: empty names, path separators, leading dots, and control characters.
+func validateKeyComponent(kind, name string) error {
+	if name == "" {
+		return fmt.Errorf("%s name cannot be empty", kind)
+	}
+	if strings.HasPrefix(name, ".") {
+		return fmt.Errorf("%s name cannot start with a dot: %s", kind, name)
+	}
+	for _, c := range name {
+		if c == '/' || c == '\\' {
+			return fmt.Errorf("%s name cannot contain path separators: %s", kind, name)
+		}
+		if c < 0x20 || c == 0x7f {
+			return fmt.Errorf("%s name cannot contain control characters", kind)
+		}
+	}
+	return nil
+}
+
+// ValidateEnvironmentName checks that an environment name is safe to use as
+// an S3 key component.
+func ValidateEnvironmentName(name string) error {
+	return validateKeyComponent("environment", name)
+}
+
+// ValidateAppName checks that an app name is safe to use as an S3 key component.
+func ValidateAppName(name string) error {
+	return validateKeyComponent("app", name)
+}
+
 func GenerateTagKey(appName, version string) string {
 	return fmt.Sprintf("tags/%s/%s.json", appName, version)
 }
@@ -82,6 +144,12 @@ func GeneratePointerKey(appName, environment string) string {
 	return fmt.Sprintf("pointers/%s/%s.json", appName, environment)
 }
 
+// GeneratePendingPointerKey returns the key for a scheduled promotion that has
+// not been activated yet (see promote --schedule and apply-pending).
+func GeneratePendingPointerKey(appName, environment string) string {
+	return fmt.Sprintf("pointers/%s/%s.pending.json", appName, environment)
+}
+
 func ResolveImagePath(ctx context.Context, s3Client S3Client, bucket string, pointer *PointerMetadata) (string, error) {
 	switch pointer.TargetType {
 	case TargetTypeImage:
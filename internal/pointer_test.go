@@ -99,6 +99,30 @@ func TestGeneratePointerKey(t *testing.T) {
 	}
 }
 
+func TestValidateEnvironmentName(t *testing.T) {
+	valid := []string{"production", "staging", "staging-2", "qa_eu", "prod.blue"}
+	for _, name := range valid {
+		assert.NoError(t, ValidateEnvironmentName(name), "Expected %q to be valid", name)
+	}
+
+	invalid := []string{"", "pro/duction", "pro\\duction", ".hidden", "env\x00name", "env\nname"}
+	for _, name := range invalid {
+		assert.Error(t, ValidateEnvironmentName(name), "Expected %q to be rejected", name)
+	}
+}
+
+func TestValidateAppName(t *testing.T) {
+	valid := []string{"myapp", "my-app", "api2"}
+	for _, name := range valid {
+		assert.NoError(t, ValidateAppName(name), "Expected %q to be valid", name)
+	}
+
+	invalid := []string{"", "my/app", ".app", "app\tname"}
+	for _, name := range invalid {
+		assert.Error(t, ValidateAppName(name), "Expected %q to be rejected", name)
+	}
+}
+
 func TestParseImageReference(t *testing.T) {
 	tests := []struct {
 		imageRef        string
@@ -140,3 +164,54 @@ func TestParseImageReference(t *testing.T) {
 		})
 	}
 }
+
+func TestPointerMetadataFromJSON_V0Migration(t *testing.T) {
+	// The earliest pointers had neither schema_version nor target_type
+	data := []byte(`{
+		"target_path": "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		"promoted_by": "alice",
+		"git_hash": "f7a5a27",
+		"git_time": "20250721-2118"
+	}`)
+
+	pointer, err := PointerMetadataFromJSON(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, PointerMetadataSchemaVersion, pointer.SchemaVersion)
+	assert.Equal(t, TargetTypeImage, pointer.TargetType)
+	assert.Equal(t, "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz", pointer.TargetPath)
+}
+
+func TestPointerMetadataFromJSON_CurrentVersionUntouched(t *testing.T) {
+	original, err := CreateTagPointer("tags/myapp/v1.2.0.json", "f7a5a27", "20250721-2118", "myapp:20250721-2118-f7a5a27", "v1.2.0")
+	assert.NoError(t, err)
+
+	data, err := original.ToJSON()
+	assert.NoError(t, err)
+
+	pointer, err := PointerMetadataFromJSON(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, PointerMetadataSchemaVersion, pointer.SchemaVersion)
+	assert.Equal(t, TargetTypeTag, pointer.TargetType)
+}
+
+func TestPointerMetadataFromJSON_NewerVersionPreserved(t *testing.T) {
+	// A future client wrote this; the version must not be downgraded and
+	// the known fields still parse
+	data := []byte(`{
+		"schema_version": 99,
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		"promoted_by": "alice",
+		"git_hash": "f7a5a27",
+		"git_time": "20250721-2118",
+		"field_from_the_future": true
+	}`)
+
+	pointer, err := PointerMetadataFromJSON(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 99, pointer.SchemaVersion)
+	assert.Equal(t, TargetTypeImage, pointer.TargetType)
+}
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// LocalPruner removes imported s3dock images from the local Docker daemon
+// once no environment references them anymore
+type LocalPruner struct {
+	docker DockerClient
+	list   *ListService
+}
+
+func NewLocalPruner(docker DockerClient, s3 S3Client, bucket string) *LocalPruner {
+	return &LocalPruner{
+		docker: docker,
+		list:   NewListService(s3, bucket),
+	}
+}
+
+// PruneLocal removes local images that match the s3dock tag pattern
+// (<app>:<timestamp>-<hash>) and are not referenced by any environment
+// pointer for their app. The newest keepLast images per app are kept
+// regardless of references. With dryRun set, images are only reported.
+func (p *LocalPruner) PruneLocal(ctx context.Context, keepLast int, dryRun bool) (*PruneLocalResult, error) {
+	refs, err := p.docker.ListImages(ctx)
+	if err != nil {
+		LogError("Failed to list local images: %v", err)
+		return nil, fmt.Errorf("failed to list local images: %w", err)
+	}
+
+	// Group s3dock-tagged images by app; anything else is not ours to touch
+	byApp := make(map[string][]string)
+	for _, ref := range refs {
+		appName, _, _, err := ParseImageReference(ref)
+		if err != nil {
+			continue
+		}
+		byApp[appName] = append(byApp[appName], ref)
+	}
+
+	apps := make([]string, 0, len(byApp))
+	for app := range byApp {
+		apps = append(apps, app)
+	}
+	sort.Strings(apps)
+
+	result := &PruneLocalResult{DryRun: dryRun}
+	for _, app := range apps {
+		images := byApp[app]
+		// Tags embed the commit timestamp, so string order is age order
+		sort.Sort(sort.Reverse(sort.StringSlice(images)))
+
+		referenced, err := p.referencedImages(ctx, app)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, ref := range images {
+			switch {
+			case i < keepLast:
+				LogDebug("Keeping %s (within keep-last %d)", ref, keepLast)
+				result.Kept = append(result.Kept, ref)
+			case referenced[ref]:
+				LogDebug("Keeping %s (referenced by an environment)", ref)
+				result.Kept = append(result.Kept, ref)
+			default:
+				if dryRun {
+					LogInfo("Would remove local image %s", ref)
+				} else {
+					LogInfo("Removing local image %s", ref)
+					if err := p.docker.RemoveImage(ctx, ref); err != nil {
+						LogError("Failed to remove image %s: %v", ref, err)
+						return nil, fmt.Errorf("failed to remove image %s: %w", ref, err)
+					}
+				}
+				result.Removed = append(result.Removed, ref)
+			}
+		}
+	}
+
+	LogInfo("Pruned %d local images, kept %d", len(result.Removed), len(result.Kept))
+	return result, nil
+}
+
+// referencedImages collects the image references an app's environments
+// currently point at, both the promote-time snapshot and the currently-
+// resolved image for tag-promoted environments.
+func (p *LocalPruner) referencedImages(ctx context.Context, appName string) (map[string]bool, error) {
+	envs, err := p.list.ListEnvironments(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments for %s: %w", appName, err)
+	}
+	p.list.ResolveEnvironments(ctx, envs)
+
+	referenced := make(map[string]bool)
+	for _, env := range envs {
+		if env.SourceImage != "" {
+			referenced[env.SourceImage] = true
+		}
+		if env.ResolvedImage != "" {
+			referenced[env.ResolvedImage] = true
+		}
+	}
+	return referenced, nil
+}
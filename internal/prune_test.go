@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruneLocal_RemovesUnreferenced(t *testing.T) {
+	ctx := context.Background()
+	mockDocker := new(MockDockerClient)
+	mockS3 := newMockS3ClientForList()
+
+	mockS3.files["images/myapp/202507/myapp-20250720-1045-abc1234.tar.gz"] = []byte("old")
+	mockS3.files["images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"] = []byte("live")
+	mockS3.files["images/myapp/202507/myapp-20250722-0900-cafe123.tar.gz"] = []byte("new")
+	mockS3.files["pointers/myapp/production.json"] = []byte(`{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		"source_image": "myapp:20250721-2118-f7a5a27"
+	}`)
+
+	mockDocker.On("ListImages", ctx).Return([]string{
+		"myapp:20250720-1045-abc1234",
+		"myapp:20250721-2118-f7a5a27",
+		"myapp:20250722-0900-cafe123",
+		"nginx:latest",
+	}, nil)
+	mockDocker.On("RemoveImage", ctx, "myapp:20250720-1045-abc1234").Return(nil)
+
+	pruner := NewLocalPruner(mockDocker, mockS3, "test-bucket")
+
+	result, err := pruner.PruneLocal(ctx, 1, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"myapp:20250720-1045-abc1234"}, result.Removed)
+	// Newest image kept by keep-last, promoted image kept by reference;
+	// nginx:latest doesn't match the s3dock pattern and is never touched
+	assert.Equal(t, []string{"myapp:20250722-0900-cafe123", "myapp:20250721-2118-f7a5a27"}, result.Kept)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestPruneLocal_DryRun(t *testing.T) {
+	ctx := context.Background()
+	mockDocker := new(MockDockerClient)
+	mockS3 := newMockS3ClientForList()
+
+	mockDocker.On("ListImages", ctx).Return([]string{
+		"myapp:20250720-1045-abc1234",
+	}, nil)
+
+	pruner := NewLocalPruner(mockDocker, mockS3, "test-bucket")
+
+	result, err := pruner.PruneLocal(ctx, 0, true)
+
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []string{"myapp:20250720-1045-abc1234"}, result.Removed)
+	mockDocker.AssertNotCalled(t, "RemoveImage", ctx, "myapp:20250720-1045-abc1234")
+	mockDocker.AssertExpectations(t)
+}
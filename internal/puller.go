@@ -7,15 +7,25 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/schollz/progressbar/v3"
 )
 
+// pullDiskHeadroomFactor estimates total disk usage of a pull relative to the
+// compressed image size: the download itself plus the decompressed tar that
+// Docker reads during import.
+const pullDiskHeadroomFactor = 3
+
 type ImagePuller struct {
-	docker DockerClient
-	s3     S3Client
-	bucket string
-	audit  AuditLogger
+	docker         DockerClient
+	s3             S3Client
+	bucket         string
+	audit          AuditLogger
+	stripTags      bool
+	keepDownload   bool
+	localRefPrefix string
+	platform       string
 }
 
 func NewImagePuller(docker DockerClient, s3 S3Client, bucket string) *ImagePuller {
@@ -28,6 +38,36 @@ func NewImagePuller(docker DockerClient, s3 S3Client, bucket string) *ImagePulle
 	}
 }
 
+// SetStripTags controls whether tags embedded in the saved tar are removed
+// after import, keeping only the s3dock-canonical tag (--strip-tags-on-import).
+// Preservation is the default.
+func (p *ImagePuller) SetStripTags(strip bool) {
+	p.stripTags = strip
+}
+
+// SetLocalRefPrefix sets a registry-style prefix applied when tagging the
+// imported image locally (--local-ref-prefix), e.g. "registry.example.com"
+// turns myapp:tag into registry.example.com/myapp:tag. This eases migration
+// for teams keeping registry-style local references.
+func (p *ImagePuller) SetLocalRefPrefix(prefix string) {
+	p.localRefPrefix = prefix
+}
+
+// SetPlatform makes pull verify the imported image's os/arch against the
+// requested platform (pull --platform). The stored artifact is single-arch,
+// so a mismatched pull imports fine but won't run; checking here guards
+// mixed amd64/arm64 fleets before the image reaches a deploy.
+func (p *ImagePuller) SetPlatform(platform string) {
+	p.platform = platform
+}
+
+// SetKeepDownload controls whether the downloaded .tar.gz survives the pull
+// (--keep-download) so the exact stored artifact can be inspected with
+// tar or docker load. By default the temp file is deleted after import.
+func (p *ImagePuller) SetKeepDownload(keep bool) {
+	p.keepDownload = keep
+}
+
 // Pull image from environment (e.g., "myapp production")
 func (p *ImagePuller) Pull(ctx context.Context, appName, environment string) (*PullResult, error) {
 	LogInfo("Pulling %s from %s environment", appName, environment)
@@ -193,18 +233,41 @@ func (p *ImagePuller) downloadAndImportImage(ctx context.Context, appName, sourc
 
 	if exists {
 		LogInfo("Image %s already exists in Docker, skipping download and import", expectedImageTag)
-		return expectedImageTag, true, nil
+		if err := p.verifyPlatform(ctx, expectedImageTag); err != nil {
+			return "", false, err
+		}
+		localRef, err := p.applyLocalRefPrefix(ctx, expectedImageTag)
+		if err != nil {
+			return "", false, err
+		}
+		return localRef, true, nil
 	}
 
 	LogDebug("Image %s not found in Docker, proceeding with download", expectedImageTag)
 
+	// Preflight: the temp filesystem needs room for the compressed download
+	// plus headroom for the decompressed Docker import
+	required := metadata.Size * pullDiskHeadroomFactor
+	available, err := availableDiskSpace(os.TempDir())
+	if err != nil {
+		LogDebug("Could not determine available disk space: %v", err)
+	} else if available < required {
+		LogError("Insufficient disk space: need %d bytes, have %d bytes", required, available)
+		return "", false, fmt.Errorf("insufficient disk space: need %d bytes, have %d bytes", required, available)
+	}
+
 	// Create temporary file for download
 	tempFile, err := os.CreateTemp("", "s3dock-pull-*.tar.gz")
 	if err != nil {
 		LogError("Failed to create temp file: %v", err)
 		return "", false, fmt.Errorf("failed to create temp file: %w", err)
 	}
-	defer os.Remove(tempFile.Name()) // Always cleanup temp file
+	defer func() {
+		// With --keep-download the artifact survives for inspection
+		if !p.keepDownload {
+			os.Remove(tempFile.Name())
+		}
+	}()
 	defer tempFile.Close()
 
 	// Download with retries and checksum verification
@@ -270,8 +333,164 @@ func (p *ImagePuller) downloadAndImportImage(ctx context.Context, appName, sourc
 		return "", false, fmt.Errorf("failed to import image to Docker: %w", err)
 	}
 
+	// Verify the import actually produced the expected local reference - a
+	// docker load can succeed while the archive carries different tags
+	LogDebug("Verifying imported image is available as %s", expectedImageTag)
+	imported, err := p.docker.ImageExists(ctx, expectedImageTag)
+	if err != nil {
+		LogError("Failed to verify imported image: %v", err)
+		return "", false, fmt.Errorf("failed to verify imported image: %w", err)
+	}
+	if !imported {
+		LogError("Import did not produce expected image %s", expectedImageTag)
+		return "", false, fmt.Errorf("import verification failed: image %s not found after import (archive tags may not match)", expectedImageTag)
+	}
+
+	if p.stripTags {
+		if err := p.stripImportedTags(ctx, expectedImageTag); err != nil {
+			LogError("Failed to strip imported tags: %v", err)
+			return "", false, fmt.Errorf("failed to strip imported tags: %w", err)
+		}
+	}
+
+	if err := p.verifyPlatform(ctx, expectedImageTag); err != nil {
+		return "", false, err
+	}
+
+	localRef, err := p.applyLocalRefPrefix(ctx, expectedImageTag)
+	if err != nil {
+		return "", false, err
+	}
+
+	if p.keepDownload {
+		LogInfo("Keeping downloaded archive at %s", tempFile.Name())
+		if !IsJSONOutput() {
+			fmt.Printf("Downloaded archive kept at: %s\n", tempFile.Name())
+		}
+	}
+
 	LogInfo("Successfully pulled and imported %s from %s", appName, source)
-	return expectedImageTag, false, nil
+	return localRef, false, nil
+}
+
+// verifyPlatform checks the image's architecture against the --platform
+// request. No-op when no platform was requested.
+func (p *ImagePuller) verifyPlatform(ctx context.Context, imageRef string) error {
+	if p.platform == "" {
+		return nil
+	}
+
+	actual, err := p.docker.ImageArchitecture(ctx, imageRef)
+	if err != nil {
+		return fmt.Errorf("failed to inspect image architecture: %w", err)
+	}
+
+	if !platformMatches(p.platform, actual) {
+		LogError("Platform mismatch for %s: requested %s, image is %s", imageRef, p.platform, actual)
+		return fmt.Errorf("image %s is %s but --platform requested %s: the stored artifact is single-arch and will not run on this host", imageRef, actual, p.platform)
+	}
+
+	LogDebug("Platform verified for %s: %s", imageRef, actual)
+	return nil
+}
+
+// platformMatches compares a requested platform against the image's os/arch.
+// A bare architecture request (amd64) matches any OS with that architecture.
+func platformMatches(requested, actual string) bool {
+	if requested == actual {
+		return true
+	}
+	if !strings.Contains(requested, "/") {
+		if idx := strings.LastIndex(actual, "/"); idx >= 0 {
+			return actual[idx+1:] == requested
+		}
+	}
+	return false
+}
+
+// applyLocalRefPrefix tags the image with the registry-style local reference
+// when --local-ref-prefix is set and returns the reference callers should
+// report. The canonical tag is left in place.
+func (p *ImagePuller) applyLocalRefPrefix(ctx context.Context, canonicalRef string) (string, error) {
+	if p.localRefPrefix == "" {
+		return canonicalRef, nil
+	}
+
+	localRef := strings.TrimSuffix(p.localRefPrefix, "/") + "/" + canonicalRef
+	if err := validateLocalReference(localRef); err != nil {
+		return "", err
+	}
+
+	LogDebug("Tagging %s as %s", canonicalRef, localRef)
+	if err := p.docker.TagImage(ctx, canonicalRef, localRef); err != nil {
+		return "", fmt.Errorf("failed to tag image as %s: %w", localRef, err)
+	}
+
+	return localRef, nil
+}
+
+// validateLocalReference rejects references Docker would refuse: empty path
+// components, whitespace or control characters, an uppercase repository, or
+// an empty tag.
+func validateLocalReference(ref string) error {
+	if strings.Contains(ref, "//") {
+		return fmt.Errorf("invalid local reference %s: empty path component", ref)
+	}
+	for _, c := range ref {
+		if c <= 0x20 || c == 0x7f {
+			return fmt.Errorf("invalid local reference %s: contains whitespace or control characters", ref)
+		}
+	}
+
+	// Only the repository part (before the tag) must be lowercase
+	name := ref
+	if idx := strings.LastIndex(ref, ":"); idx > strings.LastIndex(ref, "/") {
+		if idx == len(ref)-1 {
+			return fmt.Errorf("invalid local reference %s: empty tag", ref)
+		}
+		name = ref[:idx]
+	}
+	if strings.ToLower(name) != name {
+		return fmt.Errorf("invalid local reference %s: repository must be lowercase", ref)
+	}
+
+	return nil
+}
+
+// stripImportedTags removes the tags embedded in the saved tar, keeping only
+// the canonical reference. The canonical tag is re-applied first if the tar
+// did not carry it, so the image is never left untagged.
+func (p *ImagePuller) stripImportedTags(ctx context.Context, canonicalRef string) error {
+	tags, err := p.docker.ImageTags(ctx, canonicalRef)
+	if err != nil {
+		return fmt.Errorf("failed to list imported image tags: %w", err)
+	}
+
+	hasCanonical := false
+	for _, tag := range tags {
+		if tag == canonicalRef {
+			hasCanonical = true
+			break
+		}
+	}
+	if !hasCanonical && len(tags) > 0 {
+		LogDebug("Re-tagging %s as canonical %s", tags[0], canonicalRef)
+		if err := p.docker.TagImage(ctx, tags[0], canonicalRef); err != nil {
+			return fmt.Errorf("failed to apply canonical tag: %w", err)
+		}
+	}
+
+	for _, tag := range tags {
+		if tag == canonicalRef {
+			continue
+		}
+		LogDebug("Removing embedded tag %s", tag)
+		if err := p.docker.RemoveTag(ctx, tag); err != nil {
+			return fmt.Errorf("failed to remove tag %s: %w", tag, err)
+		}
+	}
+
+	return nil
 }
 
 // downloadImageWithProgress downloads image from S3 with progress bar
@@ -7,6 +7,8 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,8 +41,10 @@ func TestImagePuller_Pull_Success_DirectImage(t *testing.T) {
 	mockS3.On("DownloadStream", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz").Return(io.NopCloser(bytes.NewReader(imageData)), nil)
 
 	// Set up Docker mock
-	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil)
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil).Once()
 	mockDocker.On("ImportImage", mock.Anything, mock.AnythingOfType("*gzip.Reader")).Return(nil)
+	// Post-import verification finds the image under the expected tag
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(true, nil)
 
 	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
 
@@ -51,6 +55,130 @@ func TestImagePuller_Pull_Success_DirectImage(t *testing.T) {
 	mockDocker.AssertExpectations(t)
 }
 
+func TestImagePuller_Pull_StripTagsOnImport(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+
+	// Create test data
+	testContent := "mock image data strip"
+	metadataJSON, imageData, _ := createTestMetadata(testContent)
+
+	// Mock environment pointer
+	envPointerJSON := `{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz",
+		"promoted_at": "2025-07-22T13:34:24Z",
+		"promoted_by": "testuser",
+		"git_hash": "abc1234",
+		"git_time": "20250722-0039",
+		"source_image": "myapp:20250722-0039-abc1234"
+	}`
+
+	// Set up S3 mocks
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return([]byte(envPointerJSON), nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.json").Return([]byte(metadataJSON), nil)
+	mockS3.On("DownloadStream", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz").Return(io.NopCloser(bytes.NewReader(imageData)), nil)
+
+	// Set up Docker mock: the tar carried an extra tag besides the canonical one
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil).Once()
+	mockDocker.On("ImportImage", mock.Anything, mock.AnythingOfType("*gzip.Reader")).Return(nil)
+	// Post-import verification finds the image under the expected tag
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(true, nil)
+	mockDocker.On("ImageTags", mock.Anything, "myapp:20250722-0039-abc1234").Return([]string{"myapp:20250722-0039-abc1234", "myapp:latest"}, nil)
+	mockDocker.On("RemoveTag", mock.Anything, "myapp:latest").Return(nil)
+
+	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
+	puller.SetStripTags(true)
+
+	_, err := puller.Pull(context.Background(), "myapp", "production")
+
+	assert.NoError(t, err)
+	// The canonical tag must not be removed
+	mockDocker.AssertNotCalled(t, "RemoveTag", mock.Anything, "myapp:20250722-0039-abc1234")
+	mockS3.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestImagePuller_Pull_KeepDownload(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+
+	testContent := "mock image data keep"
+	metadataJSON, imageData, _ := createTestMetadata(testContent)
+
+	envPointerJSON := `{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz",
+		"promoted_at": "2025-07-22T13:34:24Z",
+		"promoted_by": "testuser",
+		"git_hash": "abc1234",
+		"git_time": "20250722-0039",
+		"source_image": "myapp:20250722-0039-abc1234"
+	}`
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return([]byte(envPointerJSON), nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.json").Return([]byte(metadataJSON), nil)
+	mockS3.On("DownloadStream", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz").Return(io.NopCloser(bytes.NewReader(imageData)), nil)
+
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil).Once()
+	mockDocker.On("ImportImage", mock.Anything, mock.AnythingOfType("*gzip.Reader")).Return(nil)
+	// Post-import verification finds the image under the expected tag
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(true, nil)
+
+	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
+	puller.SetKeepDownload(true)
+
+	// Capture stdout to recover the kept archive path
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	_, err := puller.Pull(context.Background(), "myapp", "production")
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	output := buf.String()
+
+	// The import spinner shares stdout, so locate the path by its marker
+	marker := "Downloaded archive kept at: "
+	idx := strings.LastIndex(output, marker)
+	if idx < 0 {
+		t.Fatalf("Expected kept-archive path in output, got: %q", output)
+	}
+	path := strings.TrimSpace(output[idx+len(marker):])
+	defer os.Remove(path)
+
+	// The artifact must still exist and match what was stored in S3
+	kept, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, imageData, kept)
+}
+
+func TestImagePuller_StripImportedTags_ReappliesCanonical(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+
+	// The saved tar only carried a foreign tag; the canonical one is re-applied
+	mockDocker.On("ImageTags", mock.Anything, "myapp:20250722-0039-abc1234").Return([]string{"registry.local/myapp:latest"}, nil)
+	mockDocker.On("TagImage", mock.Anything, "registry.local/myapp:latest", "myapp:20250722-0039-abc1234").Return(nil)
+	mockDocker.On("RemoveTag", mock.Anything, "registry.local/myapp:latest").Return(nil)
+
+	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
+	puller.SetStripTags(true)
+
+	err := puller.stripImportedTags(context.Background(), "myapp:20250722-0039-abc1234")
+
+	assert.NoError(t, err)
+	mockDocker.AssertExpectations(t)
+}
+
 func TestImagePuller_Pull_Success_TagReference(t *testing.T) {
 	mockDocker := new(MockDockerClient)
 	mockS3 := new(MockS3Client)
@@ -89,8 +217,10 @@ func TestImagePuller_Pull_Success_TagReference(t *testing.T) {
 	mockS3.On("DownloadStream", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz").Return(io.NopCloser(bytes.NewReader(imageData)), nil)
 
 	// Set up Docker mock
-	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil)
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil).Once()
 	mockDocker.On("ImportImage", mock.Anything, mock.AnythingOfType("*gzip.Reader")).Return(nil)
+	// Post-import verification finds the image under the expected tag
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(true, nil)
 
 	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
 
@@ -143,8 +273,10 @@ func TestImagePuller_PullFromTag_Success(t *testing.T) {
 	mockS3.On("DownloadStream", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz").Return(io.NopCloser(bytes.NewReader(imageData)), nil)
 
 	// Set up Docker mock
-	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil)
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil).Once()
 	mockDocker.On("ImportImage", mock.Anything, mock.AnythingOfType("*gzip.Reader")).Return(nil)
+	// Post-import verification finds the image under the expected tag
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(true, nil)
 
 	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
 
@@ -203,8 +335,10 @@ func TestImagePuller_Pull_ChecksumMismatch_RetrySuccess(t *testing.T) {
 	mockS3.On("DownloadStream", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz").Return(io.NopCloser(bytes.NewReader(goodImageData)), nil).Once()
 
 	// Set up Docker mock
-	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil)
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil).Once()
 	mockDocker.On("ImportImage", mock.Anything, mock.AnythingOfType("*gzip.Reader")).Return(nil)
+	// Post-import verification finds the image under the expected tag
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(true, nil)
 
 	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
 
@@ -254,6 +388,46 @@ func TestImagePuller_Pull_DockerImportFailure(t *testing.T) {
 	mockDocker.AssertExpectations(t)
 }
 
+func TestImagePuller_Pull_ImportTagMismatch(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+
+	// Create test data
+	testContent := "tag mismatch data"
+	metadataJSON, imageData, _ := createTestMetadata(testContent)
+
+	// Mock environment pointer
+	envPointerJSON := `{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz",
+		"promoted_at": "2025-07-22T13:34:24Z",
+		"promoted_by": "testuser",
+		"git_hash": "abc1234",
+		"git_time": "20250722-0039",
+		"source_image": "myapp:20250722-0039-abc1234"
+	}`
+
+	// Set up S3 mocks
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return([]byte(envPointerJSON), nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.json").Return([]byte(metadataJSON), nil)
+	mockS3.On("DownloadStream", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz").Return(io.NopCloser(bytes.NewReader(imageData)), nil)
+
+	// Import succeeds but the expected tag is still missing afterwards,
+	// e.g. the archive carried tags for a different reference
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil)
+	mockDocker.On("ImportImage", mock.Anything, mock.AnythingOfType("*gzip.Reader")).Return(nil)
+
+	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
+
+	_, err := puller.Pull(context.Background(), "myapp", "production")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found after import")
+	mockS3.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+}
+
 func TestImagePuller_Pull_Skip_ImageAlreadyExists(t *testing.T) {
 	mockDocker := new(MockDockerClient)
 	mockS3 := new(MockS3Client)
@@ -292,6 +466,94 @@ func TestImagePuller_Pull_Skip_ImageAlreadyExists(t *testing.T) {
 	mockDocker.AssertExpectations(t)
 }
 
+func TestImagePuller_Pull_PlatformMismatch(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+
+	testContent := "existing image data"
+	metadataJSON, _, _ := createTestMetadata(testContent)
+
+	envPointerJSON := `{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz",
+		"promoted_at": "2025-07-22T13:34:24Z",
+		"promoted_by": "testuser",
+		"git_hash": "abc1234",
+		"git_time": "20250722-0039",
+		"source_image": "myapp:20250722-0039-abc1234"
+	}`
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return([]byte(envPointerJSON), nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.json").Return([]byte(metadataJSON), nil)
+
+	// The image is already local but was built for the other architecture
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(true, nil)
+	mockDocker.On("ImageArchitecture", mock.Anything, "myapp:20250722-0039-abc1234").Return("linux/arm64", nil)
+
+	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
+	puller.SetPlatform("linux/amd64")
+
+	_, err := puller.Pull(context.Background(), "myapp", "production")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "linux/arm64")
+	assert.Contains(t, err.Error(), "linux/amd64")
+	mockDocker.AssertExpectations(t)
+}
+
+func TestImagePuller_Pull_PlatformMatch(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+
+	testContent := "existing image data"
+	metadataJSON, _, _ := createTestMetadata(testContent)
+
+	envPointerJSON := `{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz",
+		"promoted_at": "2025-07-22T13:34:24Z",
+		"promoted_by": "testuser",
+		"git_hash": "abc1234",
+		"git_time": "20250722-0039",
+		"source_image": "myapp:20250722-0039-abc1234"
+	}`
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return([]byte(envPointerJSON), nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.json").Return([]byte(metadataJSON), nil)
+
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(true, nil)
+	mockDocker.On("ImageArchitecture", mock.Anything, "myapp:20250722-0039-abc1234").Return("linux/amd64", nil)
+
+	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
+	puller.SetPlatform("linux/amd64")
+
+	result, err := puller.Pull(context.Background(), "myapp", "production")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Skipped)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestPlatformMatches(t *testing.T) {
+	cases := []struct {
+		requested string
+		actual    string
+		expected  bool
+	}{
+		{"linux/amd64", "linux/amd64", true},
+		{"linux/amd64", "linux/arm64", false},
+		{"amd64", "linux/amd64", true},
+		{"arm64", "linux/amd64", false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, platformMatches(tc.requested, tc.actual),
+			"requested %s against %s", tc.requested, tc.actual)
+	}
+}
+
 // Helper functions
 func createMockGzippedData(content string) []byte {
 	var buf bytes.Buffer
@@ -324,3 +586,52 @@ func createTestMetadata(content string) (string, []byte, string) {
 
 	return metadataJSON, imageData, checksum
 }
+
+func TestImagePuller_Pull_LocalRefPrefix(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+
+	testContent := "mock image data prefix"
+	metadataJSON, imageData, _ := createTestMetadata(testContent)
+
+	envPointerJSON := `{
+		"target_type": "image",
+		"target_path": "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz",
+		"promoted_at": "2025-07-22T13:34:24Z",
+		"promoted_by": "testuser",
+		"git_hash": "abc1234",
+		"git_time": "20250722-0039",
+		"source_image": "myapp:20250722-0039-abc1234"
+	}`
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return([]byte(envPointerJSON), nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.json").Return([]byte(metadataJSON), nil)
+	mockS3.On("DownloadStream", mock.Anything, "test-bucket", "images/myapp/202507/myapp-20250722-0039-abc1234.tar.gz").Return(io.NopCloser(bytes.NewReader(imageData)), nil)
+
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(false, nil).Once()
+	mockDocker.On("ImportImage", mock.Anything, mock.AnythingOfType("*gzip.Reader")).Return(nil)
+	// Post-import verification finds the image under the expected tag
+	mockDocker.On("ImageExists", mock.Anything, "myapp:20250722-0039-abc1234").Return(true, nil)
+	mockDocker.On("TagImage", mock.Anything, "myapp:20250722-0039-abc1234", "registry.example.com/myapp:20250722-0039-abc1234").Return(nil)
+
+	puller := NewImagePuller(mockDocker, mockS3, "test-bucket")
+	puller.SetLocalRefPrefix("registry.example.com")
+
+	result, err := puller.Pull(context.Background(), "myapp", "production")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "registry.example.com/myapp:20250722-0039-abc1234", result.ImageRef)
+	mockS3.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestValidateLocalReference(t *testing.T) {
+	assert.NoError(t, validateLocalReference("registry.example.com/myapp:20250722-0039-abc1234"))
+	assert.NoError(t, validateLocalReference("localhost:5000/myapp:latest"))
+
+	assert.Error(t, validateLocalReference("registry.example.com//myapp:latest"))
+	assert.Error(t, validateLocalReference("Registry.Example.com/myapp:latest"))
+	assert.Error(t, validateLocalReference("registry.example.com/myapp:"))
+	assert.Error(t, validateLocalReference("registry example.com/myapp:latest"))
+}
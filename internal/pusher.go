@@ -1,23 +1,35 @@
 package internal
 
 import (
-	"bytes"
+	"archive/tar"
 	"compress/gzip"
 	"context"
+	"crypto/md5"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/klauspost/pgzip"
 	"github.com/schollz/progressbar/v3"
 )
 
 type ImagePusher struct {
-	docker DockerClient
-	s3     S3Client
-	git    GitClient
-	bucket string
-	audit  AuditLogger
+	docker             DockerClient
+	s3                 S3Client
+	git                GitClient
+	bucket             string
+	audit              AuditLogger
+	pathTemplate       string
+	gitPath            string
+	appName            string
+	extraMeta          map[string]string
+	dockerfileHash     string
+	compressionWorkers int
+
+	verifyAfterPush       bool
+	deleteOnVerifyFailure bool
 }
 
 func NewImagePusher(docker DockerClient, s3 S3Client, git GitClient, bucket string) *ImagePusher {
@@ -31,26 +43,128 @@ func NewImagePusher(docker DockerClient, s3 S3Client, git GitClient, bucket stri
 	}
 }
 
-func (p *ImagePusher) Push(ctx context.Context, imageRef string) (*PushResult, error) {
-	LogInfo("Pushing image %s to S3", imageRef)
+// DisableAudit suppresses audit logging for this invocation (--no-audit)
+func (p *ImagePusher) DisableAudit() {
+	p.audit = NopAuditLogger{}
+}
+
+// SetPathTemplate overrides the image key layout (naming.path_template in the
+// config). An empty template keeps the default.
+func (p *ImagePusher) SetPathTemplate(template string) {
+	p.pathTemplate = template
+}
+
+// SetGitPath overrides the repository queried for git metadata (push --path).
+// When unset, push uses the current directory, falling back to the enclosing
+// repository root if the current directory is not itself a repo.
+func (p *ImagePusher) SetGitPath(path string) {
+	p.gitPath = path
+}
+
+// SetAppName overrides the app name derived from the image reference
+// (push --app-name). The override still has to produce legal S3 keys.
+func (p *ImagePusher) SetAppName(name string) {
+	p.appName = name
+}
+
+// SetExtraMetadata attaches free-form annotations (push --meta key=value) to
+// the image metadata written alongside the upload, e.g. ticket IDs or change
+// requests.
+func (p *ImagePusher) SetExtraMetadata(extra map[string]string) {
+	p.extraMeta = extra
+}
+
+// SetDockerfileHash records the Dockerfile fingerprint computed for push
+// --dockerfile-hash-label in the image metadata.
+func (p *ImagePusher) SetDockerfileHash(hash string) {
+	p.dockerfileHash = hash
+}
+
+// SetCompressionWorkers enables parallel gzip compression with the given
+// worker count (--compression-workers). Values below 2 keep the standard
+// single-threaded compressor.
+func (p *ImagePusher) SetCompressionWorkers(workers int) {
+	p.compressionWorkers = workers
+}
 
-	gitHash, err := p.git.GetCurrentHash(".")
+// SetVerifyAfterPush re-downloads the uploaded image and re-checks its
+// checksum before declaring the push successful (--verify-after-push),
+// catching silent corruption or wrong-bucket writes. With deleteOnFailure
+// set, a corrupt object is removed instead of being left in place for
+// investigation (--delete-on-verify-failure).
+func (p *ImagePusher) SetVerifyAfterPush(deleteOnFailure bool) {
+	p.verifyAfterPush = true
+	p.deleteOnVerifyFailure = deleteOnFailure
+}
+
+// resolveAppName applies the configured override, defaulting to the name
+// derived from the image reference
+func (p *ImagePusher) resolveAppName(imageRef string) string {
+	if p.appName != "" {
+		return p.appName
+	}
+	return ExtractAppName(imageRef)
+}
+
+// gitCoordinates returns the commit hash and timestamp for the configured
+// git path.
+func (p *ImagePusher) gitCoordinates() (string, string, error) {
+	gitPath := p.gitPath
+	if gitPath == "" {
+		gitPath = "."
+	}
+
+	gitHash, err := p.git.GetCurrentHash(gitPath)
+	if err != nil && p.gitPath == "" {
+		root, rootErr := p.git.FindRepositoryRoot(".")
+		if rootErr != nil {
+			LogError("Failed to get git hash: %v", err)
+			return "", "", fmt.Errorf("failed to get git hash: %w", err)
+		}
+		LogDebug("Current directory is not a git repository, using root %s", root)
+		gitPath = root
+		gitHash, err = p.git.GetCurrentHash(gitPath)
+	}
 	if err != nil {
 		LogError("Failed to get git hash: %v", err)
-		return nil, fmt.Errorf("failed to get git hash: %w", err)
+		return "", "", fmt.Errorf("failed to get git hash: %w", err)
 	}
 
-	gitTime, err := p.git.GetCommitTimestamp(".")
+	gitTime, err := p.git.GetCommitTimestamp(gitPath)
 	if err != nil {
 		LogError("Failed to get git timestamp: %v", err)
-		return nil, fmt.Errorf("failed to get git timestamp: %w", err)
+		return "", "", fmt.Errorf("failed to get git timestamp: %w", err)
 	}
 
-	appName := ExtractAppName(imageRef)
-	yearMonth := time.Now().Format("200601")
+	return gitHash, gitTime, nil
+}
+
+// PushOptions controls how Push handles an existing image with a different checksum
+type PushOptions struct {
+	ImmutableTags bool       // Error on checksum mismatch instead of archiving and overwriting
+	Force         bool       // Archive and overwrite even when ImmutableTags is set
+	SortTar       bool       // Sort tar entries by name for stronger reproducibility (buffers in memory)
+	TarFormat     tar.Format // Force a tar header format during normalization (tar.FormatUnknown keeps each entry's original format)
+}
+
+func (p *ImagePusher) Push(ctx context.Context, imageRef string) (*PushResult, error) {
+	return p.PushWithOptions(ctx, imageRef, PushOptions{})
+}
+
+func (p *ImagePusher) PushWithOptions(ctx context.Context, imageRef string, opts PushOptions) (*PushResult, error) {
+	LogInfo("Pushing image %s to S3", imageRef)
+
+	gitHash, gitTime, err := p.gitCoordinates()
+	if err != nil {
+		return nil, err
+	}
 
-	filename := fmt.Sprintf("%s-%s-%s.tar.gz", appName, gitTime, gitHash)
-	s3Key := fmt.Sprintf("images/%s/%s/%s", appName, yearMonth, filename)
+	appName := p.resolveAppName(imageRef)
+	if err := ValidateAppName(appName); err != nil {
+		LogError("Invalid app name: %v", err)
+		return nil, err
+	}
+	s3Key := resolveImageKey(p.pathTemplate, appName, gitTime, gitHash)
 	metadataKey := GenerateMetadataKey(s3Key)
 
 	LogDebug("App name: %s, Git hash: %s, Git time: %s", appName, gitHash, gitTime)
@@ -64,26 +178,51 @@ func (p *ImagePusher) Push(ctx context.Context, imageRef string) (*PushResult, e
 		return nil, fmt.Errorf("failed to check metadata existence: %w", err)
 	}
 
-	LogDebug("Exporting Docker image %s", imageRef)
-	var spinner *progressbar.ProgressBar
-	if !IsJSONOutput() {
-		spinner = progressbar.NewOptions(-1,
-			progressbar.OptionSetDescription("Exporting Docker image..."),
-			progressbar.OptionSpinnerType(14),
-			progressbar.OptionSetWidth(50),
-		)
-		spinner.RenderBlank()
-	}
+	// If metadata exists, download it up front so the recorded image ID can
+	// short-circuit the export pipeline
+	var previousMetadata *ImageMetadata
+	if exists {
+		LogDebug("Metadata exists, downloading for comparison")
+		existingMetadataBytes, err := p.s3.Download(ctx, p.bucket, metadataKey)
+		if err != nil {
+			LogError("Failed to download existing metadata: %v", err)
+			return nil, fmt.Errorf("failed to download existing metadata: %w", err)
+		}
 
-	imageData, err := p.docker.ExportImage(ctx, imageRef)
-	if spinner != nil {
-		spinner.Finish()
+		previousMetadata, err = ImageMetadataFromJSON(existingMetadataBytes)
+		if err != nil {
+			LogError("Failed to parse existing metadata: %v", err)
+			return nil, fmt.Errorf("failed to parse existing metadata: %w", err)
+		}
 	}
+
+	// Inspecting the image ID is cheap; an unchanged ID means unchanged
+	// content, so the whole export/normalize/compress/checksum pipeline can
+	// be skipped without even exporting the image
+	imageID, err := p.docker.ImageID(ctx, imageRef)
 	if err != nil {
-		LogError("Failed to export image: %v", err)
-		return nil, fmt.Errorf("failed to export image: %w", err)
+		LogDebug("Failed to inspect image ID for %s: %v", imageRef, err)
+		imageID = ""
+	}
+
+	if previousMetadata != nil && imageID != "" && previousMetadata.ImageID == imageID {
+		LogInfo("Image %s has unchanged image ID %s, skipping export and upload", imageRef, imageID)
+
+		// Log audit event for skipped upload
+		auditEvent, err := CreatePushEvent(appName, gitHash, gitTime, imageRef, s3Key, previousMetadata.Checksum, previousMetadata.Size, true, false)
+		if err == nil {
+			p.audit.LogEvent(ctx, auditEvent)
+		}
+
+		return &PushResult{
+			ImageRef: imageRef,
+			S3Key:    s3Key,
+			Checksum: previousMetadata.Checksum,
+			Size:     previousMetadata.Size,
+			Skipped:  true,
+			Archived: false,
+		}, nil
 	}
-	defer imageData.Close()
 
 	// Parse git time for tar normalization
 	fixedTime, err := ParseGitTime(gitTime)
@@ -92,41 +231,24 @@ func (p *ImagePusher) Push(ctx context.Context, imageRef string) (*PushResult, e
 		return nil, fmt.Errorf("failed to parse git time: %w", err)
 	}
 
-	// Normalize tar timestamps and add gzip compression
-	LogDebug("Normalizing tar timestamps to %s and compressing with gzip", fixedTime.Format("2006-01-02 15:04:05"))
-	pr, pw := io.Pipe()
-	go func() {
-		defer pw.Close()
-		
-		// Create a pipe for tar normalization
-		normPr, normPw := io.Pipe()
-		
-		// Goroutine 1: Normalize tar
-		go func() {
-			defer normPw.Close()
-			if err := NormalizeTar(imageData, normPw, fixedTime); err != nil {
-				LogError("Failed to normalize tar: %v", err)
-				normPw.CloseWithError(err)
-				return
-			}
-		}()
-		
-		// Goroutine 2: Compress normalized tar
-		gzipWriter := gzip.NewWriter(pw)
-		gzipWriter.ModTime = time.Time{} // Set to zero time for deterministic output
-		defer gzipWriter.Close()
-
-		if _, err := io.Copy(gzipWriter, normPr); err != nil {
-			LogError("Failed to compress image data: %v", err)
-			pw.CloseWithError(err)
-			return
-		}
-	}()
+	pr, err := p.exportCompressed(ctx, imageRef, fixedTime, opts.SortTar, opts.TarFormat)
+	if err != nil {
+		return nil, err
+	}
+	defer pr.Close()
 
-	// Calculate metadata while buffering compressed data
+	// Calculate metadata while spooling compressed data to a temp file, so
+	// large images never have to fit in memory
 	LogDebug("Calculating metadata for compressed image")
-	var buf bytes.Buffer
-	teeReader := io.TeeReader(pr, &buf)
+	tmpFile, err := os.CreateTemp("", "s3dock-push-*.tar.gz")
+	if err != nil {
+		LogError("Failed to create temp file: %v", err)
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	teeReader := io.TeeReader(pr, tmpFile)
 
 	metadata, _, err := CalculateMetadata(teeReader, gitHash, gitTime, imageRef, appName)
 	if err != nil {
@@ -134,25 +256,19 @@ func (p *ImagePusher) Push(ctx context.Context, imageRef string) (*PushResult, e
 		return nil, fmt.Errorf("failed to calculate metadata: %w", err)
 	}
 
+	metadata.ImageID = imageID
+	metadata.DockerfileHash = p.dockerfileHash
+	if len(p.extraMeta) > 0 {
+		metadata.Extra = p.extraMeta
+	}
 	LogDebug("Image checksum: %s, size: %d bytes", metadata.Checksum, metadata.Size)
 
 	// If metadata exists, compare checksums
-	if exists {
-		LogDebug("Metadata exists, downloading for checksum comparison")
-		existingMetadataBytes, err := p.s3.Download(ctx, p.bucket, metadataKey)
-		if err != nil {
-			LogError("Failed to download existing metadata: %v", err)
-			return nil, fmt.Errorf("failed to download existing metadata: %w", err)
-		}
-
-		existingMetadata, err := ImageMetadataFromJSON(existingMetadataBytes)
-		if err != nil {
-			LogError("Failed to parse existing metadata: %v", err)
-			return nil, fmt.Errorf("failed to parse existing metadata: %w", err)
-		}
-
-		LogDebug("Comparing checksums - existing: %s, new: %s", existingMetadata.Checksum, metadata.Checksum)
-		if existingMetadata.Checksum == metadata.Checksum {
+	if previousMetadata != nil {
+		existingMetadata := previousMetadata
+		algorithm, match := CompareChecksums(existingMetadata, metadata)
+		LogDebug("Comparing %s checksums - existing: %s, new: %s", algorithm, existingMetadata.Checksum, metadata.Checksum)
+		if match {
 			LogInfo("Image %s already exists with same checksum, skipping upload", imageRef)
 
 			// Log audit event for skipped upload
@@ -171,7 +287,14 @@ func (p *ImagePusher) Push(ctx context.Context, imageRef string) (*PushResult, e
 			}, nil
 		}
 
-		// Checksums don't match - archive existing files
+		// Checksums don't match - the same git state produced different content
+		if opts.ImmutableTags && !opts.Force {
+			LogError("Checksum mismatch for %s (existing: %s, new: %s). Refusing to overwrite immutable image tag.",
+				imageRef, existingMetadata.Checksum, metadata.Checksum)
+			return nil, fmt.Errorf("image %s already exists with different checksum (existing: %s, new: %s): this usually indicates a non-reproducible build; use --force to archive and overwrite", imageRef, existingMetadata.Checksum, metadata.Checksum)
+		}
+
+		// Archive existing files before overwriting
 		LogError("Checksum mismatch for %s (existing: %s, new: %s). Archiving existing files.",
 			imageRef, existingMetadata.Checksum, metadata.Checksum)
 
@@ -181,13 +304,27 @@ func (p *ImagePusher) Push(ctx context.Context, imageRef string) (*PushResult, e
 		}
 	}
 
-	// Upload new image
+	// Upload new image from the temp file
 	LogDebug("Uploading image to S3: %s", s3Key)
-	if err := p.s3.UploadWithProgress(ctx, p.bucket, s3Key, &buf, metadata.Size, "Uploading image"); err != nil {
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		LogError("Failed to rewind temp file: %v", err)
+		return nil, fmt.Errorf("failed to rewind temp file: %w", err)
+	}
+	if err := p.s3.UploadWithProgress(ctx, p.bucket, s3Key, tmpFile, metadata.Size, "Uploading image"); err != nil {
 		LogError("Failed to upload image to S3: %v", err)
 		return nil, fmt.Errorf("failed to upload image to S3: %w", err)
 	}
 
+	// Record the uploaded object's ETag so verify can compare ETags with a
+	// cheap HEAD before falling back to a full re-checksum
+	if etagger, ok := p.s3.(ETagClient); ok {
+		if etag, err := etagger.HeadETag(ctx, p.bucket, s3Key); err == nil {
+			metadata.ETag = etag
+		} else {
+			LogDebug("Failed to read ETag for %s: %v", s3Key, err)
+		}
+	}
+
 	// Upload metadata
 	LogDebug("Uploading metadata to S3: %s", metadataKey)
 	metadataJSON, err := metadata.ToJSON()
@@ -201,6 +338,14 @@ func (p *ImagePusher) Push(ctx context.Context, imageRef string) (*PushResult, e
 		return nil, fmt.Errorf("failed to upload metadata to S3: %w", err)
 	}
 
+	// End-to-end integrity check (--verify-after-push): re-download the
+	// object and confirm the bytes that landed match what was computed locally
+	if p.verifyAfterPush {
+		if err := p.verifyUploadedImage(ctx, s3Key, metadata); err != nil {
+			return nil, err
+		}
+	}
+
 	LogInfo("Successfully pushed %s to s3://%s/%s (checksum: %s)", imageRef, p.bucket, s3Key, metadata.Checksum)
 
 	// Log audit event for successful upload
@@ -210,13 +355,194 @@ func (p *ImagePusher) Push(ctx context.Context, imageRef string) (*PushResult, e
 		p.audit.LogEvent(ctx, auditEvent)
 	}
 
-	return &PushResult{
+	result := &PushResult{
 		ImageRef: imageRef,
 		S3Key:    s3Key,
 		Checksum: metadata.Checksum,
 		Size:     metadata.Size,
 		Skipped:  false,
 		Archived: wasArchived,
+	}
+
+	if wasArchived && previousMetadata != nil {
+		result.PreviousChecksum = previousMetadata.Checksum
+		result.PreviousSize = previousMetadata.Size
+		result.SizeDelta = metadata.Size - previousMetadata.Size
+	}
+
+	return result, nil
+}
+
+// verifyUploadedImage re-downloads the uploaded object and compares size and
+// checksum against the locally computed metadata
+func (p *ImagePusher) verifyUploadedImage(ctx context.Context, s3Key string, metadata *ImageMetadata) error {
+	LogInfo("Verifying uploaded image s3://%s/%s", p.bucket, s3Key)
+
+	body, err := p.s3.DownloadStream(ctx, p.bucket, s3Key)
+	if err != nil {
+		LogError("Failed to re-download image for verification: %v", err)
+		return fmt.Errorf("failed to re-download image for verification: %w", err)
+	}
+	defer body.Close()
+
+	hasher := md5.New()
+	size, err := io.Copy(hasher, body)
+	if err != nil {
+		LogError("Failed to read image during verification: %v", err)
+		return fmt.Errorf("failed to read image during verification: %w", err)
+	}
+
+	actual := fmt.Sprintf("%x", hasher.Sum(nil))
+	if actual != metadata.Checksum || size != metadata.Size {
+		LogError("Post-upload verification failed for s3://%s/%s: expected %s (%d bytes), got %s (%d bytes)",
+			p.bucket, s3Key, metadata.Checksum, metadata.Size, actual, size)
+		if p.deleteOnVerifyFailure {
+			if delErr := p.s3.Delete(ctx, p.bucket, s3Key); delErr != nil {
+				LogError("Failed to delete corrupt object s3://%s/%s: %v", p.bucket, s3Key, delErr)
+			} else {
+				LogInfo("Deleted corrupt object s3://%s/%s", p.bucket, s3Key)
+			}
+		}
+		return fmt.Errorf("post-upload verification failed for s3://%s/%s: checksum mismatch (expected %s, got %s)", p.bucket, s3Key, metadata.Checksum, actual)
+	}
+
+	LogDebug("Post-upload verification passed for s3://%s/%s", p.bucket, s3Key)
+	return nil
+}
+
+// exportCompressed exports the Docker image and returns a reader of the
+// normalized, gzip-compressed tar stream. With sortTar set, entries are
+// additionally sorted by name (buffered, not streamed).
+func (p *ImagePusher) exportCompressed(ctx context.Context, imageRef string, fixedTime time.Time, sortTar bool, tarFormat tar.Format) (io.ReadCloser, error) {
+	LogDebug("Exporting Docker image %s", imageRef)
+	var spinner *progressbar.ProgressBar
+	if !IsJSONOutput() {
+		spinner = progressbar.NewOptions(-1,
+			progressbar.OptionSetDescription("Exporting Docker image..."),
+			progressbar.OptionSpinnerType(14),
+			progressbar.OptionSetWidth(50),
+		)
+		spinner.RenderBlank()
+	}
+
+	imageData, err := p.docker.ExportImage(ctx, imageRef)
+	if spinner != nil {
+		spinner.Finish()
+	}
+	if err != nil {
+		LogError("Failed to export image: %v", err)
+		return nil, fmt.Errorf("failed to export image: %w", err)
+	}
+
+	// Normalize tar timestamps and add gzip compression
+	LogDebug("Normalizing tar timestamps to %s and compressing with gzip", fixedTime.Format("2006-01-02 15:04:05"))
+	pr, pw := io.Pipe()
+	go func() {
+		defer imageData.Close()
+		defer pw.Close()
+
+		// Create a pipe for tar normalization
+		normPr, normPw := io.Pipe()
+
+		// Goroutine 1: Normalize tar
+		go func() {
+			defer normPw.Close()
+			normalize := NormalizeTarWithFormat
+			if sortTar {
+				normalize = NormalizeTarSortedWithFormat
+			}
+			if err := normalize(imageData, normPw, fixedTime, tarFormat); err != nil {
+				LogError("Failed to normalize tar: %v", err)
+				normPw.CloseWithError(err)
+				return
+			}
+		}()
+
+		// Goroutine 2: Compress normalized tar
+		gzipWriter := p.newGzipWriter(pw)
+		defer gzipWriter.Close()
+
+		if _, err := io.Copy(gzipWriter, normPr); err != nil {
+			LogError("Failed to compress image data: %v", err)
+			pw.CloseWithError(err)
+			return
+		}
+	}()
+
+	return pr, nil
+}
+
+// compressionBlockSize is the pgzip block size. Fixed so the parallel
+// output depends only on the input, not on the worker count.
+const compressionBlockSize = 1 << 20
+
+// newGzipWriter returns the compressor for the push pipeline: a parallel
+// pgzip writer when --compression-workers asks for more than one worker,
+// the standard gzip writer otherwise. Both zero ModTime for deterministic
+// output. The two produce different (each internally deterministic) streams,
+// so enabling workers changes the artifact checksum on the next push; the
+// parallel stream is still plain gzip and decompresses with the standard
+// reader on pull.
+func (p *ImagePusher) newGzipWriter(w io.Writer) io.WriteCloser {
+	if p.compressionWorkers > 1 {
+		pgzipWriter := pgzip.NewWriter(w)
+		if err := pgzipWriter.SetConcurrency(compressionBlockSize, p.compressionWorkers); err != nil {
+			LogError("Invalid compression worker settings, falling back to single-threaded gzip: %v", err)
+		} else {
+			LogDebug("Compressing with %d parallel gzip workers", p.compressionWorkers)
+			pgzipWriter.ModTime = time.Time{} // Set to zero time for deterministic output
+			return pgzipWriter
+		}
+	}
+	gzipWriter := gzip.NewWriter(w)
+	gzipWriter.ModTime = time.Time{} // Set to zero time for deterministic output
+	return gzipWriter
+}
+
+// ChecksumOnly runs the push pipeline (export, normalize, compress) just far
+// enough to compute the artifact checksum and the would-be S3 key, without
+// uploading anything. CI can compare this against the remote metadata checksum
+// to decide whether a push is needed at all.
+func (p *ImagePusher) ChecksumOnly(ctx context.Context, imageRef string, opts PushOptions) (*ChecksumResult, error) {
+	LogInfo("Computing checksum for image %s", imageRef)
+
+	gitHash, gitTime, err := p.gitCoordinates()
+	if err != nil {
+		return nil, err
+	}
+
+	appName := p.resolveAppName(imageRef)
+	if err := ValidateAppName(appName); err != nil {
+		LogError("Invalid app name: %v", err)
+		return nil, err
+	}
+	s3Key := resolveImageKey(p.pathTemplate, appName, gitTime, gitHash)
+
+	fixedTime, err := ParseGitTime(gitTime)
+	if err != nil {
+		LogError("Failed to parse git time: %v", err)
+		return nil, fmt.Errorf("failed to parse git time: %w", err)
+	}
+
+	pr, err := p.exportCompressed(ctx, imageRef, fixedTime, opts.SortTar, opts.TarFormat)
+	if err != nil {
+		return nil, err
+	}
+	defer pr.Close()
+
+	metadata, _, err := CalculateMetadata(pr, gitHash, gitTime, imageRef, appName)
+	if err != nil {
+		LogError("Failed to calculate metadata: %v", err)
+		return nil, fmt.Errorf("failed to calculate metadata: %w", err)
+	}
+
+	LogInfo("Checksum for %s: %s (%d bytes), would upload to s3://%s/%s", imageRef, metadata.Checksum, metadata.Size, p.bucket, s3Key)
+
+	return &ChecksumResult{
+		ImageRef: imageRef,
+		S3Key:    s3Key,
+		Checksum: metadata.Checksum,
+		Size:     metadata.Size,
 	}, nil
 }
 
@@ -227,33 +553,80 @@ func (p *ImagePusher) archiveExistingFiles(ctx context.Context, imageS3Key, meta
 	LogDebug("Archiving existing files - image: %s, metadata: %s", imageS3Key, metadataKey)
 	LogDebug("Archive paths - image: %s, metadata: %s", archiveImageKey, archiveMetaKey)
 
-	// Copy image to archive
-	if err := p.s3.Copy(ctx, p.bucket, imageS3Key, archiveImageKey); err != nil {
-		LogError("Failed to copy image to archive: %v", err)
-		return fmt.Errorf("failed to copy image to archive: %w", err)
+	if err := p.archiveObject(ctx, imageS3Key, archiveImageKey, "image"); err != nil {
+		return err
 	}
 
-	// Copy metadata to archive
-	if err := p.s3.Copy(ctx, p.bucket, metadataKey, archiveMetaKey); err != nil {
-		LogError("Failed to copy metadata to archive: %v", err)
-		return fmt.Errorf("failed to copy metadata to archive: %w", err)
+	if err := p.archiveObject(ctx, metadataKey, archiveMetaKey, "metadata"); err != nil {
+		return err
 	}
 
-	// Delete original files (they will be replaced)
-	if err := p.s3.Delete(ctx, p.bucket, imageS3Key); err != nil {
-		LogError("Failed to delete original image: %v", err)
-		return fmt.Errorf("failed to delete original image: %w", err)
+	LogInfo("Archived existing files to %s and %s", archiveImageKey, archiveMetaKey)
+	return nil
+}
+
+// archiveObject moves a single object to its archive key. It is safe to re-run
+// after a crash between copy and delete: an existing archive copy is not
+// copied again, and an already-deleted original is tolerated.
+func (p *ImagePusher) archiveObject(ctx context.Context, srcKey, dstKey, kind string) error {
+	srcExists, err := p.s3.Exists(ctx, p.bucket, srcKey)
+	if err != nil {
+		LogError("Failed to check original %s: %v", kind, err)
+		return fmt.Errorf("failed to check original %s: %w", kind, err)
 	}
 
-	if err := p.s3.Delete(ctx, p.bucket, metadataKey); err != nil {
-		LogError("Failed to delete original metadata: %v", err)
-		return fmt.Errorf("failed to delete original metadata: %w", err)
+	archived, err := p.s3.Exists(ctx, p.bucket, dstKey)
+	if err != nil {
+		LogError("Failed to check archive copy of %s: %v", kind, err)
+		return fmt.Errorf("failed to check archive copy of %s: %w", kind, err)
+	}
+
+	if archived {
+		LogDebug("Archive copy %s already exists - skipping copy", dstKey)
+	} else {
+		if !srcExists {
+			// A previous run archived and deleted the original under an
+			// earlier timestamp - nothing left to move
+			LogDebug("Original %s %s already archived by a previous run", kind, srcKey)
+			return nil
+		}
+		if err := p.s3.Copy(ctx, p.bucket, srcKey, dstKey); err != nil {
+			LogError("Failed to copy %s to archive: %v", kind, err)
+			return fmt.Errorf("failed to copy %s to archive: %w", kind, err)
+		}
+	}
+
+	if !srcExists {
+		LogDebug("Original %s %s already deleted", kind, srcKey)
+		return nil
+	}
+
+	if err := p.s3.Delete(ctx, p.bucket, srcKey); err != nil {
+		LogError("Failed to delete original %s: %v", kind, err)
+		return fmt.Errorf("failed to delete original %s: %w", kind, err)
 	}
 
-	LogInfo("Archived existing files to %s and %s", archiveImageKey, archiveMetaKey)
 	return nil
 }
 
+// ExtractAppNameWithNamespace derives an app name that keeps the repository
+// namespace: registry.com/team/myapp:tag becomes team-myapp. The registry
+// host is dropped and slashes become dashes so the result forms a single
+// legal S3 key segment.
+func ExtractAppNameWithNamespace(imageRef string) string {
+	ref := imageRef
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+
+	parts := strings.Split(ref, "/")
+	if len(parts) > 1 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		parts = parts[1:]
+	}
+
+	return strings.Join(parts, "-")
+}
+
 func ExtractAppName(imageRef string) string {
 	lastSlash := -1
 
@@ -33,8 +33,43 @@ func (m *MockDockerClient) ImageExists(ctx context.Context, imageRef string) (bo
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockDockerClient) BuildImage(ctx context.Context, contextPath string, dockerfile string, tags []string, platform string) error {
-	args := m.Called(ctx, contextPath, dockerfile, tags, platform)
+func (m *MockDockerClient) ImageID(ctx context.Context, imageRef string) (string, error) {
+	args := m.Called(ctx, imageRef)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDockerClient) ImageTags(ctx context.Context, imageRef string) ([]string, error) {
+	args := m.Called(ctx, imageRef)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDockerClient) ImageArchitecture(ctx context.Context, imageRef string) (string, error) {
+	args := m.Called(ctx, imageRef)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDockerClient) TagImage(ctx context.Context, sourceRef, targetRef string) error {
+	args := m.Called(ctx, sourceRef, targetRef)
+	return args.Error(0)
+}
+
+func (m *MockDockerClient) RemoveTag(ctx context.Context, imageRef string) error {
+	args := m.Called(ctx, imageRef)
+	return args.Error(0)
+}
+
+func (m *MockDockerClient) BuildImage(ctx context.Context, contextPath string, dockerfile string, tags []string, platform string, cacheFrom []string, squash, pull bool) error {
+	args := m.Called(ctx, contextPath, dockerfile, tags, platform, cacheFrom, squash, pull)
+	return args.Error(0)
+}
+
+func (m *MockDockerClient) ListImages(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDockerClient) RemoveImage(ctx context.Context, imageRef string) error {
+	args := m.Called(ctx, imageRef)
 	return args.Error(0)
 }
 
@@ -111,6 +146,16 @@ func (m *MockGitClient) GetCommitTimestamp(path string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockGitClient) GetTreeHash(path, subdir string) (string, error) {
+	args := m.Called(path, subdir)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockGitClient) GetTreeHashAtCommit(path, subdir, commitHash string) (string, error) {
+	args := m.Called(path, subdir, commitHash)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockGitClient) IsRepositoryDirty(path string) (bool, error) {
 	args := m.Called(path)
 	return args.Bool(0), args.Error(1)
@@ -146,6 +191,7 @@ func TestImagePusher_Push_Success_NewImage(t *testing.T) {
 
 	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
 	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
 	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("image data"), nil)
 
 	// Metadata doesn't exist (new image)
@@ -176,6 +222,124 @@ func TestImagePusher_Push_Success_NewImage(t *testing.T) {
 	mockS3.AssertExpectations(t)
 }
 
+func TestImagePusher_Push_VerifyAfterPush_Success(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("image data"), nil)
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	})).Return(false, nil)
+
+	// Capture the uploaded bytes so verification re-downloads exactly what
+	// was uploaded
+	var uploaded bytes.Buffer
+	mockS3.On("UploadWithProgress", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	}), mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		uploaded.Reset()
+		io.Copy(&uploaded, args.Get(3).(io.Reader))
+	}).Return(nil)
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	}), mock.Anything).Return(nil)
+	mockS3.On("DownloadStream", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	})).Return(io.NopCloser(&uploaded), nil)
+
+	// Mock audit log upload
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "audit/") && strings.Contains(key, "push")
+	}), mock.Anything).Return(nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+	pusher.SetVerifyAfterPush(false)
+
+	_, err := pusher.Push(context.Background(), "myapp:latest")
+
+	assert.NoError(t, err)
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePusher_Push_VerifyAfterPush_Mismatch(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("image data"), nil)
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	})).Return(false, nil)
+	mockS3.On("UploadWithProgress", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	}), mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("string")).Return(nil)
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	}), mock.Anything).Return(nil)
+
+	// The re-download returns different bytes; no Delete is mocked, so an
+	// attempted deletion would fail the test as an unexpected call
+	mockS3.On("DownloadStream", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	})).Return(io.NopCloser(bytes.NewReader([]byte("corrupted"))), nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+	pusher.SetVerifyAfterPush(false)
+
+	_, err := pusher.Push(context.Background(), "myapp:latest")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "post-upload verification failed")
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePusher_Push_VerifyAfterPush_MismatchDeletes(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("image data"), nil)
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	})).Return(false, nil)
+	mockS3.On("UploadWithProgress", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	}), mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("string")).Return(nil)
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	}), mock.Anything).Return(nil)
+	mockS3.On("DownloadStream", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	})).Return(io.NopCloser(bytes.NewReader([]byte("corrupted"))), nil)
+
+	// --delete-on-verify-failure removes the corrupt object
+	mockS3.On("Delete", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	})).Return(nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+	pusher.SetVerifyAfterPush(true)
+
+	_, err := pusher.Push(context.Background(), "myapp:latest")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "post-upload verification failed")
+	mockS3.AssertExpectations(t)
+}
+
 func TestImagePusher_Push_Success_ExistingSameChecksum(t *testing.T) {
 	mockDocker := new(MockDockerClient)
 	mockS3 := new(MockS3Client)
@@ -183,6 +347,7 @@ func TestImagePusher_Push_Success_ExistingSameChecksum(t *testing.T) {
 
 	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
 	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
 	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("image data"), nil)
 
 	// Metadata exists
@@ -222,6 +387,7 @@ func TestImagePusher_Push_GitError(t *testing.T) {
 	mockGit := new(MockGitClient)
 
 	mockGit.On("GetCurrentHash", mock.Anything).Return("", errors.New("git error"))
+	mockGit.On("FindRepositoryRoot", ".").Return("", errors.New("not a repository"))
 
 	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
 
@@ -239,6 +405,7 @@ func TestImagePusher_Push_Success_ChecksumMismatch(t *testing.T) {
 
 	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
 	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
 	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("new image data"), nil)
 
 	// Metadata exists
@@ -257,6 +424,12 @@ func TestImagePusher_Push_Success_ChecksumMismatch(t *testing.T) {
 	})).Return(metadataJSON, nil)
 
 	// Archive operations
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	})).Return(true, nil)
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "archive/")
+	})).Return(false, nil)
 	mockS3.On("Copy", mock.Anything, "test-bucket", mock.AnythingOfType("string"), mock.MatchedBy(func(key string) bool {
 		return strings.HasPrefix(key, "archive/")
 	})).Return(nil)
@@ -277,9 +450,109 @@ func TestImagePusher_Push_Success_ChecksumMismatch(t *testing.T) {
 
 	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
 
-	_, err := pusher.Push(context.Background(), "myapp:latest")
+	result, err := pusher.Push(context.Background(), "myapp:latest")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Archived)
+	assert.Equal(t, "old-checksum-value", result.PreviousChecksum)
+	assert.Equal(t, int64(10), result.PreviousSize)
+	assert.Equal(t, result.Size-10, result.SizeDelta)
+	mockGit.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePusher_Push_ImmutableTagsRejectsMismatch(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("new image data"), nil)
+
+	// Metadata exists with a different checksum
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	})).Return(true, nil)
+
+	existingMetadata := &ImageMetadata{
+		Checksum: "old-checksum-value",
+		Size:     10,
+	}
+	metadataJSON, _ := existingMetadata.ToJSON()
+	mockS3.On("Download", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	})).Return(metadataJSON, nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+
+	_, err := pusher.PushWithOptions(context.Background(), "myapp:latest", PushOptions{ImmutableTags: true})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "non-reproducible")
+	mockS3.AssertNotCalled(t, "Copy", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockS3.AssertNotCalled(t, "UploadWithProgress", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockGit.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePusher_Push_ImmutableTagsForceOverwrites(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("new image data"), nil)
+
+	// Metadata exists with a different checksum
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	})).Return(true, nil)
+
+	existingMetadata := &ImageMetadata{
+		Checksum: "old-checksum-value",
+		Size:     10,
+	}
+	metadataJSON, _ := existingMetadata.ToJSON()
+	mockS3.On("Download", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	})).Return(metadataJSON, nil)
+
+	// Archive operations still happen with --force
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	})).Return(true, nil)
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "archive/")
+	})).Return(false, nil)
+	mockS3.On("Copy", mock.Anything, "test-bucket", mock.AnythingOfType("string"), mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "archive/")
+	})).Return(nil)
+	mockS3.On("Delete", mock.Anything, "test-bucket", mock.AnythingOfType("string")).Return(nil)
+
+	mockS3.On("UploadWithProgress", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	}), mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("string")).Return(nil)
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	}), mock.Anything).Return(nil)
+
+	// Mock audit log upload
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "audit/") && strings.Contains(key, "push")
+	}), mock.Anything).Return(nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+
+	result, err := pusher.PushWithOptions(context.Background(), "myapp:latest", PushOptions{ImmutableTags: true, Force: true})
 
 	assert.NoError(t, err)
+	assert.True(t, result.Archived)
 	mockGit.AssertExpectations(t)
 	mockDocker.AssertExpectations(t)
 	mockS3.AssertExpectations(t)
@@ -292,6 +565,7 @@ func TestImagePusher_Push_DockerError(t *testing.T) {
 
 	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
 	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
 	mockS3.On("Exists", mock.Anything, "test-bucket", mock.AnythingOfType("string")).Return(false, nil)
 	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(io.NopCloser(strings.NewReader("")), errors.New("docker error"))
 
@@ -327,6 +601,7 @@ func TestImagePusher_Push_VerifyGzipCompression(t *testing.T) {
 
 	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
 	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
 	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(io.NopCloser(bytes.NewReader(tarBuf.Bytes())), nil)
 
 	// Metadata doesn't exist (new image)
@@ -386,3 +661,320 @@ func TestImagePusher_Push_VerifyGzipCompression(t *testing.T) {
 	mockDocker.AssertExpectations(t)
 	mockS3.AssertExpectations(t)
 }
+
+func TestImagePusher_Push_ParallelCompressionDecompressible(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	originalData := strings.Repeat("parallel compression payload ", 1000)
+
+	tarBuf := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(tarBuf)
+	header := &tar.Header{
+		Name: "test-file.txt",
+		Size: int64(len(originalData)),
+		Mode: 0644,
+	}
+	tarWriter.WriteHeader(header)
+	tarWriter.Write([]byte(originalData))
+	tarWriter.Close()
+
+	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(io.NopCloser(bytes.NewReader(tarBuf.Bytes())), nil)
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	})).Return(false, nil)
+
+	var uploadedData *bytes.Buffer
+	mockS3.On("UploadWithProgress", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	}), mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		reader := args.Get(3).(io.Reader)
+		uploadedData = &bytes.Buffer{}
+		io.Copy(uploadedData, reader)
+	}).Return(nil)
+
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	}), mock.Anything).Return(nil)
+
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "audit/") && strings.Contains(key, "push")
+	}), mock.Anything).Return(nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+	pusher.SetCompressionWorkers(4)
+
+	_, err := pusher.Push(context.Background(), "myapp:latest")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, uploadedData, "Should have captured uploaded data")
+
+	// The parallel stream must decompress with the standard gzip reader,
+	// which is what the puller uses
+	gzipReader, gzErr := gzip.NewReader(uploadedData)
+	assert.NoError(t, gzErr, "Parallel output should be valid gzip")
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	header, tarErr := tarReader.Next()
+	assert.NoError(t, tarErr, "Should contain valid tar data")
+	assert.Equal(t, "test-file.txt", header.Name)
+
+	content, readErr := io.ReadAll(tarReader)
+	assert.NoError(t, readErr)
+	assert.Equal(t, originalData, string(content), "File content should survive parallel compression")
+}
+
+func TestImagePusher_Push_ParallelCompressionDeterministic(t *testing.T) {
+	// The same input must compress to the same bytes regardless of worker
+	// count, since the block size is fixed
+	compress := func(workers int) string {
+		mockDocker := new(MockDockerClient)
+		mockS3 := new(MockS3Client)
+		mockGit := new(MockGitClient)
+
+		mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
+		mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+		mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+		mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar(strings.Repeat("deterministic payload ", 1000)), nil)
+		mockS3.On("Exists", mock.Anything, "test-bucket", mock.Anything).Return(false, nil)
+		mockS3.On("UploadWithProgress", mock.Anything, "test-bucket", mock.Anything, mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("string")).Return(nil)
+		mockS3.On("Upload", mock.Anything, "test-bucket", mock.Anything, mock.Anything).Return(nil)
+
+		pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+		pusher.SetCompressionWorkers(workers)
+
+		result, err := pusher.Push(context.Background(), "myapp:latest")
+		assert.NoError(t, err)
+		return result.Checksum
+	}
+
+	assert.Equal(t, compress(2), compress(8))
+}
+
+func TestImagePusher_ChecksumOnly(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("image data"), nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+
+	result, err := pusher.ChecksumOnly(context.Background(), "myapp:latest", PushOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:latest", result.ImageRef)
+	assert.NotEmpty(t, result.Checksum)
+	assert.Greater(t, result.Size, int64(0))
+	assert.True(t, strings.HasPrefix(result.S3Key, "images/myapp/"))
+	assert.True(t, strings.HasSuffix(result.S3Key, "myapp-20250721-1430-abc1234.tar.gz"))
+
+	// No S3 calls at all - nothing uploaded, nothing checked
+	mockS3.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockS3.AssertNotCalled(t, "UploadWithProgress", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockGit.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestImagePusher_Push_ExplicitGitPath(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("GetCurrentHash", "/path/to/repo").Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", "/path/to/repo").Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("image data"), nil)
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.AnythingOfType("string")).Return(false, nil)
+	mockS3.On("UploadWithProgress", mock.Anything, "test-bucket", mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("string")).Return(nil)
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+	pusher.SetGitPath("/path/to/repo")
+
+	_, err := pusher.Push(context.Background(), "myapp:latest")
+
+	assert.NoError(t, err)
+	mockGit.AssertExpectations(t)
+}
+
+func TestImagePusher_Push_FallsBackToRepositoryRoot(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	// CWD is not a repo; push should resolve the enclosing repository root
+	mockGit.On("GetCurrentHash", ".").Return("", errors.New("not a git repository"))
+	mockGit.On("FindRepositoryRoot", ".").Return("/repo/root", nil)
+	mockGit.On("GetCurrentHash", "/repo/root").Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", "/repo/root").Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("image data"), nil)
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.AnythingOfType("string")).Return(false, nil)
+	mockS3.On("UploadWithProgress", mock.Anything, "test-bucket", mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("string")).Return(nil)
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+
+	_, err := pusher.Push(context.Background(), "myapp:latest")
+
+	assert.NoError(t, err)
+	mockGit.AssertExpectations(t)
+}
+
+func TestImagePusher_Push_SkipsExportOnUnchangedImageID(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+
+	// Metadata exists and records the same image ID
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	})).Return(true, nil)
+
+	existingMetadata := &ImageMetadata{
+		Checksum: "stored-checksum",
+		Size:     119,
+		ImageID:  "sha256:f00dfeed",
+	}
+	metadataJSON, _ := existingMetadata.ToJSON()
+	mockS3.On("Download", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".json") && strings.HasPrefix(key, "images/")
+	})).Return(metadataJSON, nil)
+
+	// Mock audit log upload for skipped push
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "audit/") && strings.Contains(key, "push")
+	}), mock.Anything).Return(nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+
+	result, err := pusher.Push(context.Background(), "myapp:latest")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Skipped)
+	assert.Equal(t, "stored-checksum", result.Checksum)
+	// ExportImage must never run: that is the whole point of the fast path
+	mockDocker.AssertNotCalled(t, "ExportImage", mock.Anything, mock.Anything)
+	mockS3.AssertExpectations(t)
+}
+
+func TestExtractAppNameWithNamespace(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"myapp:latest", "myapp"},
+		{"team/myapp:latest", "team-myapp"},
+		{"registry.com/team/myapp:v1.0", "team-myapp"},
+		{"localhost:5000/team/myapp:latest", "team-myapp"},
+		{"registry.io:443/namespace/team/myapp:v1.0", "namespace-team-myapp"},
+	}
+
+	for _, test := range tests {
+		result := ExtractAppNameWithNamespace(test.input)
+		assert.Equal(t, test.expected, result, "Failed for input: %s", test.input)
+	}
+}
+
+func TestImagePusher_Push_AppNameOverride(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	mockGit.On("GetCurrentHash", mock.Anything).Return("abc1234", nil)
+	mockGit.On("GetCommitTimestamp", mock.Anything).Return("20250721-1430", nil)
+	mockDocker.On("ImageID", mock.Anything, "myapp:latest").Return("sha256:f00dfeed", nil)
+	mockDocker.On("ExportImage", mock.Anything, "myapp:latest").Return(createTestTar("image data"), nil)
+
+	// All keys must live under the overridden app name
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "images/team-myapp/")
+	})).Return(false, nil)
+	mockS3.On("UploadWithProgress", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "images/team-myapp/")
+	}), mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("string")).Return(nil)
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "images/team-myapp/")
+	}), mock.Anything).Return(nil)
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "audit/team-myapp/")
+	}), mock.Anything).Return(nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+	pusher.SetAppName("team-myapp")
+
+	result, err := pusher.Push(context.Background(), "myapp:latest")
+
+	assert.NoError(t, err)
+	assert.Contains(t, result.S3Key, "images/team-myapp/")
+	mockS3.AssertExpectations(t)
+}
+
+func TestArchiveExistingFiles_RerunAfterCrash(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	imageKey := "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz"
+	metadataKey := "images/myapp/202507/myapp-20250721-1430-abc1234.json"
+
+	// A previous run crashed between copy and delete: the archive copies
+	// already exist and the originals are still in place
+	mockS3.On("Exists", mock.Anything, "test-bucket", imageKey).Return(true, nil)
+	mockS3.On("Exists", mock.Anything, "test-bucket", metadataKey).Return(true, nil)
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "archive/")
+	})).Return(true, nil)
+	mockS3.On("Delete", mock.Anything, "test-bucket", imageKey).Return(nil)
+	mockS3.On("Delete", mock.Anything, "test-bucket", metadataKey).Return(nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+
+	err := pusher.archiveExistingFiles(context.Background(), imageKey, metadataKey)
+
+	assert.NoError(t, err)
+	// Archive copies must not be written a second time
+	mockS3.AssertNotCalled(t, "Copy", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockS3.AssertExpectations(t)
+}
+
+func TestArchiveExistingFiles_OriginalsAlreadyDeleted(t *testing.T) {
+	mockDocker := new(MockDockerClient)
+	mockS3 := new(MockS3Client)
+	mockGit := new(MockGitClient)
+
+	imageKey := "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz"
+	metadataKey := "images/myapp/202507/myapp-20250721-1430-abc1234.json"
+
+	// A previous run archived everything and crashed before finishing the
+	// push: originals are gone, archive copies exist
+	mockS3.On("Exists", mock.Anything, "test-bucket", imageKey).Return(false, nil)
+	mockS3.On("Exists", mock.Anything, "test-bucket", metadataKey).Return(false, nil)
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "archive/")
+	})).Return(true, nil)
+
+	pusher := NewImagePusher(mockDocker, mockS3, mockGit, "test-bucket")
+
+	err := pusher.archiveExistingFiles(context.Background(), imageKey, metadataKey)
+
+	assert.NoError(t, err)
+	mockS3.AssertNotCalled(t, "Copy", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockS3.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	mockS3.AssertExpectations(t)
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+// regionCachePath returns the location of the persisted bucket-region cache.
+// S3DOCK_REGION_CACHE overrides the default ~/.s3dock/regions.json (mainly
+// for tests).
+func regionCachePath() string {
+	if path := os.Getenv("S3DOCK_REGION_CACHE"); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".s3dock", "regions.json")
+}
+
+// LoadRegionCache reads the bucket-region cache. A missing or unreadable
+// cache is treated as empty - it is only an optimization.
+func LoadRegionCache() map[string]string {
+	path := regionCachePath()
+	if path == "" {
+		return map[string]string{}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return map[string]string{}
+	}
+
+	var cache map[string]string
+	if err := json.Unmarshal(data, &cache); err != nil {
+		LogDebug("Ignoring unparseable region cache %s: %v", path, err)
+		return map[string]string{}
+	}
+	return cache
+}
+
+// saveRegionCache writes the cache back to disk, creating the directory if
+// needed. Failures are logged, not fatal.
+func saveRegionCache(cache map[string]string) {
+	path := regionCachePath()
+	if path == "" {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		LogDebug("Could not create region cache directory: %v", err)
+		return
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		LogDebug("Could not write region cache: %v", err)
+	}
+}
+
+// CachedBucketRegion returns the cached region for a bucket, if any.
+func CachedBucketRegion(bucket string) (string, bool) {
+	region, ok := LoadRegionCache()[bucket]
+	return region, ok
+}
+
+// SaveBucketRegion records a discovered bucket region in the cache.
+func SaveBucketRegion(bucket, region string) {
+	cache := LoadRegionCache()
+	cache[bucket] = region
+	saveRegionCache(cache)
+}
+
+// InvalidateBucketRegion drops a bucket's cached region, e.g. after an auth
+// error that suggests the cached value is stale or wrong.
+func InvalidateBucketRegion(bucket string) {
+	cache := LoadRegionCache()
+	if _, ok := cache[bucket]; !ok {
+		return
+	}
+	delete(cache, bucket)
+	saveRegionCache(cache)
+}
+
+// ResolveBucketRegion returns the bucket's region, consulting the on-disk
+// cache before probing S3 (--region-per-bucket). Probe results are persisted
+// so later invocations skip the round trip. Auth errors during the probe
+// invalidate any stale cache entry.
+func ResolveBucketRegion(ctx context.Context, bucket string) (string, error) {
+	if region, ok := CachedBucketRegion(bucket); ok {
+		LogDebug("Using cached region %s for bucket %s", region, bucket)
+		return region, nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS config for region probe: %w", err)
+	}
+
+	region, err := manager.GetBucketRegion(ctx, s3.NewFromConfig(cfg), bucket)
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "AccessDenied" || apiErr.ErrorCode() == "InvalidAccessKeyId" || apiErr.ErrorCode() == "SignatureDoesNotMatch") {
+			InvalidateBucketRegion(bucket)
+		}
+		return "", fmt.Errorf("failed to probe region for bucket %s: %w", bucket, err)
+	}
+
+	LogDebug("Probed region %s for bucket %s", region, bucket)
+	SaveBucketRegion(bucket, region)
+	return region, nil
+}
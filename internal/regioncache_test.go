@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegionCache_ReadWrite(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "regions.json")
+	t.Setenv("S3DOCK_REGION_CACHE", cachePath)
+
+	// Missing cache reads as empty
+	assert.Empty(t, LoadRegionCache())
+	_, ok := CachedBucketRegion("my-bucket")
+	assert.False(t, ok)
+
+	SaveBucketRegion("my-bucket", "eu-west-1")
+	SaveBucketRegion("other-bucket", "us-east-2")
+
+	region, ok := CachedBucketRegion("my-bucket")
+	assert.True(t, ok)
+	assert.Equal(t, "eu-west-1", region)
+
+	cache := LoadRegionCache()
+	assert.Len(t, cache, 2)
+	assert.Equal(t, "us-east-2", cache["other-bucket"])
+}
+
+func TestRegionCache_Invalidate(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "regions.json")
+	t.Setenv("S3DOCK_REGION_CACHE", cachePath)
+
+	SaveBucketRegion("my-bucket", "eu-west-1")
+	SaveBucketRegion("other-bucket", "us-east-2")
+
+	InvalidateBucketRegion("my-bucket")
+
+	_, ok := CachedBucketRegion("my-bucket")
+	assert.False(t, ok)
+
+	// Other entries survive invalidation
+	region, ok := CachedBucketRegion("other-bucket")
+	assert.True(t, ok)
+	assert.Equal(t, "us-east-2", region)
+
+	// Invalidating an absent bucket is a no-op
+	InvalidateBucketRegion("never-seen")
+	assert.Len(t, LoadRegionCache(), 1)
+}
+
+func TestRegionCache_UnparseableFile(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "regions.json")
+	t.Setenv("S3DOCK_REGION_CACHE", cachePath)
+
+	err := os.WriteFile(cachePath, []byte("not json"), 0644)
+	assert.NoError(t, err)
+
+	assert.Empty(t, LoadRegionCache())
+}
+
+func TestResolveBucketRegion_CacheHit(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "regions.json")
+	t.Setenv("S3DOCK_REGION_CACHE", cachePath)
+
+	SaveBucketRegion("cached-bucket", "ap-southeast-1")
+
+	// Cache hit must not probe S3
+	region, err := ResolveBucketRegion(context.Background(), "cached-bucket")
+	assert.NoError(t, err)
+	assert.Equal(t, "ap-southeast-1", region)
+}
@@ -1,5 +1,7 @@
 package internal
 
+import "time"
+
 // CommandResult is the generic wrapper for all command results in JSON mode
 type CommandResult struct {
 	Success bool        `json:"success"`
@@ -14,6 +16,8 @@ type BuildResult struct {
 	AppName  string `json:"app_name"`
 	GitHash  string `json:"git_hash"`
 	GitTime  string `json:"git_time"`
+	Skipped  bool   `json:"skipped,omitempty"`   // True when --since-tag found no subtree changes
+	BuildLog string `json:"build_log,omitempty"` // Captured build output, when the Docker client records it
 }
 
 // PushResult contains the result of a push command
@@ -24,6 +28,20 @@ type PushResult struct {
 	Size     int64  `json:"size"`
 	Skipped  bool   `json:"skipped"`
 	Archived bool   `json:"archived"`
+
+	// Delta against the replaced image, populated only when a previous
+	// image was archived and overwritten
+	PreviousChecksum string `json:"previous_checksum,omitempty"`
+	PreviousSize     int64  `json:"previous_size,omitempty"`
+	SizeDelta        int64  `json:"size_delta,omitempty"`
+}
+
+// ChecksumResult contains the result of a push --checksum-only command
+type ChecksumResult struct {
+	ImageRef string `json:"image_ref"`
+	S3Key    string `json:"s3_key"`
+	Checksum string `json:"checksum"`
+	Size     int64  `json:"size"`
 }
 
 // TagResult contains the result of a tag command
@@ -37,9 +55,46 @@ type TagResult struct {
 type PromoteResult struct {
 	Source      string `json:"source"`
 	Environment string `json:"environment"`
-	SourceType  string `json:"source_type"` // "image" or "tag"
+	SourceType  string `json:"source_type"` // "image", "tag", or "environment"
 	ImageRef    string `json:"image_ref"`
 	Skipped     bool   `json:"skipped"`
+	Pending     bool   `json:"pending,omitempty"`      // True if written as a pending pointer via --schedule
+	EffectiveAt string `json:"effective_at,omitempty"` // When a pending promotion takes effect
+
+	AwaitingApproval bool `json:"awaiting_approval,omitempty"` // True if a second actor must run approve
+}
+
+// BatchPromoteEntryResult contains the outcome of one entry in a batch promote
+type BatchPromoteEntryResult struct {
+	App         string `json:"app,omitempty"`
+	Source      string `json:"source"`
+	Environment string `json:"environment"`
+	Status      string `json:"status"` // "promoted", "skipped", "failed", or "dry-run"
+	Error       string `json:"error,omitempty"`
+}
+
+// BatchPromoteResult contains the result of a promote --from-file command
+type BatchPromoteResult struct {
+	Total    int                       `json:"total"`
+	Promoted int                       `json:"promoted"`
+	Skipped  int                       `json:"skipped"`
+	Failed   int                       `json:"failed"`
+	Entries  []BatchPromoteEntryResult `json:"entries"`
+}
+
+// PruneLocalResult contains the result of a prune-local command
+type PruneLocalResult struct {
+	Removed []string `json:"removed"`
+	Kept    []string `json:"kept"`
+	DryRun  bool     `json:"dry_run,omitempty"`
+}
+
+// CleanupResult contains the result of a cleanup command
+type CleanupResult struct {
+	AppName   string   `json:"app_name"`
+	Deleted   []string `json:"deleted"`
+	Protected int      `json:"protected"`
+	DryRun    bool     `json:"dry_run,omitempty"`
 }
 
 // PullResult contains the result of a pull command
@@ -54,7 +109,78 @@ type PullResult struct {
 type CurrentResult struct {
 	AppName     string `json:"app_name"`
 	Environment string `json:"environment"`
-	ImageRef    string `json:"image_ref"`
+	ImageRef    string `json:"image_ref,omitempty"`
+	S3Path      string `json:"s3_path,omitempty"`  // Resolved object key (current --format s3path)
+	Source      string `json:"source,omitempty"`   // Which fallback hop resolved (current --fallback)
+	Expected    string `json:"expected,omitempty"` // The --expect reference (current --exit-code)
+	Match       *bool  `json:"match,omitempty"`    // Whether the resolved image matched --expect
+}
+
+// VerifyFailure describes one object that failed verification
+type VerifyFailure struct {
+	S3Path string `json:"s3_path"`
+	Detail string `json:"detail"`
+}
+
+// VerifyItem is one streamed verify-all element (--output ndjson)
+type VerifyItem struct {
+	S3Path string `json:"s3_path"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// VerifyAllResult contains the result of a verify-all scan
+type VerifyAllResult struct {
+	AppName         string          `json:"app_name,omitempty"`
+	Checked         int             `json:"checked"`
+	OK              int             `json:"ok"`
+	Corrupt         []VerifyFailure `json:"corrupt,omitempty"`
+	MissingMetadata []string        `json:"missing_metadata,omitempty"`
+}
+
+// UserActivity counts the audit events recorded for one user
+type UserActivity struct {
+	User   string `json:"user"`
+	Events int    `json:"events"`
+}
+
+// AuditSummaryResult contains the result of an audit summary command
+type AuditSummaryResult struct {
+	AppName         string         `json:"app_name"`
+	Since           string         `json:"since"`
+	Events          int            `json:"events"`
+	EventCounts     map[string]int `json:"event_counts"`
+	TopUsers        []UserActivity `json:"top_users,omitempty"`
+	Pushes          int            `json:"pushes"`
+	PushesSkipped   int            `json:"pushes_skipped"`
+	PushSkipRate    float64        `json:"push_skip_rate"`
+	PromotionsByEnv map[string]int `json:"promotions_by_env,omitempty"`
+}
+
+// AuditChainResult contains the result of an audit verify chain check
+type AuditChainResult struct {
+	AppName   string `json:"app_name"`
+	Events    int    `json:"events"`
+	Chained   int    `json:"chained"`
+	Unchained int    `json:"unchained"` // Events written before audit.chain was enabled
+	Intact    bool   `json:"intact"`
+	BrokenAt  string `json:"broken_at,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// AuditReplayResult contains the result of an audit replay command
+type AuditReplayResult struct {
+	AppName        string `json:"app_name"`
+	Environment    string `json:"environment"`
+	ImageRef       string `json:"image_ref"`
+	EventTimestamp string `json:"event_timestamp"`   // When the replayed promotion originally happened
+	Skipped        bool   `json:"skipped,omitempty"` // Pointer already matched the audit history
+}
+
+// AppItemJSON is one streamed list apps element (--format jsonl)
+type AppItemJSON struct {
+	App    string `json:"app"`
+	Latest string `json:"latest,omitempty"` // Newest image tag (list apps --with-latest)
 }
 
 // ListAppsResult contains the result of a list apps command
@@ -62,6 +188,11 @@ type ListAppsResult struct {
 	Apps []string `json:"apps"`
 }
 
+// ListAppsWithLatestResult is the list apps --with-latest JSON shape
+type ListAppsWithLatestResult struct {
+	Apps []AppItemJSON `json:"apps"`
+}
+
 // ListImagesResult contains the result of a list images command
 type ListImagesResult struct {
 	AppName   string          `json:"app_name"`
@@ -69,12 +200,22 @@ type ListImagesResult struct {
 	Images    []ImageInfoJSON `json:"images"`
 }
 
+// ListImagesByMonthResult is the list images --group-by-month JSON shape:
+// images nested under their year-month keys
+type ListImagesByMonthResult struct {
+	AppName string                     `json:"app_name"`
+	Months  map[string][]ImageInfoJSON `json:"months"`
+}
+
 // ImageInfoJSON is the JSON-serializable version of ImageInfo
 type ImageInfoJSON struct {
 	AppName   string `json:"app_name"`
 	Tag       string `json:"tag"`
 	S3Path    string `json:"s3_path"`
 	YearMonth string `json:"year_month"`
+	Checksum  string `json:"checksum,omitempty"`
+
+	DockerfileHash string `json:"dockerfile_hash,omitempty"`
 }
 
 // ListTagsResult contains the result of a list tags command
@@ -89,6 +230,16 @@ type TagInfoJSON struct {
 	Version     string `json:"version"`
 	TargetImage string `json:"target_image"`
 	S3Path      string `json:"s3_path"`
+	Dangling    bool   `json:"dangling,omitempty"`
+}
+
+// ListAllResult combines an app's images, tags, and environments in one
+// document (list all)
+type ListAllResult struct {
+	AppName      string          `json:"app_name"`
+	Images       []ImageInfoJSON `json:"images"`
+	Tags         []TagInfoJSON   `json:"tags"`
+	Environments []EnvInfoJSON   `json:"environments"`
 }
 
 // ListEnvsResult contains the result of a list envs command
@@ -99,12 +250,23 @@ type ListEnvsResult struct {
 
 // EnvInfoJSON is the JSON-serializable version of EnvInfo
 type EnvInfoJSON struct {
-	AppName     string `json:"app_name"`
-	Environment string `json:"environment"`
-	TargetType  string `json:"target_type"` // "image" or "tag"
-	TargetPath  string `json:"target_path"`
-	SourceTag   string `json:"source_tag,omitempty"`
-	SourceImage string `json:"source_image"`
+	AppName       string `json:"app_name"`
+	Environment   string `json:"environment"`
+	TargetType    string `json:"target_type"` // "image" or "tag"
+	TargetPath    string `json:"target_path"`
+	SourceTag     string `json:"source_tag,omitempty"`
+	SourceImage   string `json:"source_image"`
+	PromotedBy    string `json:"promoted_by,omitempty"`
+	PromotedAt    string `json:"promoted_at,omitempty"`
+	ResolvedImage string `json:"resolved_image,omitempty"`
+	Drifted       bool   `json:"drifted,omitempty"`
+}
+
+// FindResult contains the result of a find command
+type FindResult struct {
+	AppName string          `json:"app_name"`
+	ImageID string          `json:"image_id"`
+	Matches []ImageInfoJSON `json:"matches"`
 }
 
 // ListTagForResult contains the result of a list tag-for command
@@ -115,6 +277,14 @@ type ListTagForResult struct {
 	Direct      bool   `json:"direct"` // true if promoted directly from image (no tag)
 }
 
+// WhoamiResult contains the result of a whoami command
+type WhoamiResult struct {
+	User        string `json:"user"`
+	Source      string `json:"source"` // "actor", "os", or "fallback"
+	Bucket      string `json:"bucket,omitempty"`
+	WriteAccess *bool  `json:"write_access,omitempty"`
+}
+
 // VersionResult contains the result of a version command
 type VersionResult struct {
 	Version string `json:"version"`
@@ -131,6 +301,21 @@ type ConfigShowResult struct {
 	AccessKey string `json:"access_key,omitempty"`
 }
 
+// ResolvedFieldJSON pairs a resolved config value with its provenance
+type ResolvedFieldJSON struct {
+	Value  string `json:"value"`
+	Source string `json:"source"` // "flag", "env", "config", or "default"
+}
+
+// ConfigShowResolvedResult contains the result of a config show --resolved command
+type ConfigShowResolvedResult struct {
+	Profile   string            `json:"profile,omitempty"`
+	Bucket    ResolvedFieldJSON `json:"bucket"`
+	Region    ResolvedFieldJSON `json:"region"`
+	Endpoint  ResolvedFieldJSON `json:"endpoint"`
+	AccessKey ResolvedFieldJSON `json:"access_key"`
+}
+
 // ConfigListResult contains the result of a config list command
 type ConfigListResult struct {
 	Profiles       []string `json:"profiles"`
@@ -140,10 +325,12 @@ type ConfigListResult struct {
 // ToImageInfoJSON converts ImageInfo to ImageInfoJSON
 func (i ImageInfo) ToJSON() ImageInfoJSON {
 	return ImageInfoJSON{
-		AppName:   i.AppName,
-		Tag:       i.Tag,
-		S3Path:    i.S3Path,
-		YearMonth: i.YearMonth,
+		AppName:        i.AppName,
+		Tag:            i.Tag,
+		S3Path:         i.S3Path,
+		YearMonth:      i.YearMonth,
+		Checksum:       i.Checksum,
+		DockerfileHash: i.DockerfileHash,
 	}
 }
 
@@ -154,17 +341,26 @@ func (t TagInfo) ToJSON() TagInfoJSON {
 		Version:     t.Version,
 		TargetImage: t.TargetImage,
 		S3Path:      t.S3Path,
+		Dangling:    t.Dangling,
 	}
 }
 
 // ToEnvInfoJSON converts EnvInfo to EnvInfoJSON
 func (e EnvInfo) ToJSON() EnvInfoJSON {
+	promotedAt := ""
+	if !e.PromotedAt.IsZero() {
+		promotedAt = e.PromotedAt.Format(time.RFC3339)
+	}
 	return EnvInfoJSON{
-		AppName:     e.AppName,
-		Environment: e.Environment,
-		TargetType:  string(e.TargetType),
-		TargetPath:  e.TargetPath,
-		SourceTag:   e.SourceTag,
-		SourceImage: e.SourceImage,
+		AppName:       e.AppName,
+		Environment:   e.Environment,
+		TargetType:    string(e.TargetType),
+		TargetPath:    e.TargetPath,
+		SourceTag:     e.SourceTag,
+		SourceImage:   e.SourceImage,
+		PromotedBy:    e.PromotedBy,
+		PromotedAt:    promotedAt,
+		ResolvedImage: e.ResolvedImage,
+		Drifted:       e.Drifted,
 	}
 }
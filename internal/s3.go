@@ -3,31 +3,56 @@ package internal
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/logging"
 	"github.com/schollz/progressbar/v3"
+	"golang.org/x/time/rate"
 )
 
 type S3ClientImpl struct {
-	client      *s3.Client
-	listClient  *s3.Client // Separate client for list operations (handles bucket-subdomain endpoints)
-	uploader    *manager.Uploader
-	keyPrefix   string // Prefix to add to keys for list operations
+	client          *s3.Client
+	listClient      *s3.Client // Separate client for list operations (handles bucket-subdomain endpoints)
+	uploader        *manager.Uploader
+	keyPrefix       string // Prefix to add to keys for list operations
+	copyUnsupported bool   // Provider rejects CopyObject; use download-and-reupload
 }
 
 func NewS3Client(ctx context.Context) (*S3ClientImpl, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	var loadOpts []func(*config.LoadOptions) error
+
+	// Request/response logging is opt-in via --verbose-s3: it is noisy and
+	// can leak headers, but invaluable for endpoint/auth debugging
+	if os.Getenv("S3DOCK_VERBOSE_S3") != "" {
+		LogDebug("Enabling AWS SDK request/response logging")
+		loadOpts = append(loadOpts,
+			config.WithClientLogMode(verboseS3LogMode(true)),
+			config.WithLogger(awsLogger{}),
+		)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
 	if err != nil {
 		return nil, err
 	}
 
+	// Enterprise setups deploy via STS assume-role on top of the base credentials
+	if roleArn := os.Getenv("S3DOCK_ROLE_ARN"); roleArn != "" {
+		LogDebug("Assuming role %s for S3 access", roleArn)
+		cfg.Credentials = newAssumeRoleProvider(sts.NewFromConfig(cfg), roleArn, os.Getenv("S3DOCK_EXTERNAL_ID"))
+	}
+
 	var client *s3.Client
 	var listClient *s3.Client
 	var keyPrefix string
@@ -40,8 +65,13 @@ func NewS3Client(ctx context.Context) (*S3ClientImpl, error) {
 		})
 
 		// Check if endpoint contains bucket name (e.g., https://bucket.s3.region.wasabisys.com)
-		// If so, create a separate list client with the base endpoint
-		baseEndpoint, bucket := extractBaseEndpoint(endpointURL)
+		// If so, create a separate list client with the base endpoint. The
+		// heuristic can be switched off entirely for endpoints it misreads
+		// (disable_endpoint_detection in the config).
+		baseEndpoint, bucket := "", ""
+		if os.Getenv("S3DOCK_DISABLE_ENDPOINT_DETECTION") == "" {
+			baseEndpoint, bucket = extractBaseEndpoint(endpointURL)
+		}
 		if baseEndpoint != "" && bucket != "" {
 			LogDebug("Detected bucket-subdomain endpoint, creating separate list client")
 			LogDebug("Base endpoint: %s, bucket prefix: %s", baseEndpoint, bucket)
@@ -60,14 +90,77 @@ func NewS3Client(ctx context.Context) (*S3ClientImpl, error) {
 
 	uploader := manager.NewUploader(client)
 
+	// Requests-per-second cap from the config (rate_limit); the limiter is
+	// process-global so it also covers parallel bulk operations
+	if v := os.Getenv("S3DOCK_RATE_LIMIT"); v != "" {
+		var rps float64
+		if _, err := fmt.Sscanf(v, "%f", &rps); err == nil && rps > 0 {
+			SetRateLimit(rps)
+		} else {
+			LogError("Invalid S3DOCK_RATE_LIMIT value: %s (expected requests per second)", v)
+		}
+	}
+
 	return &S3ClientImpl{
 		client:     client,
 		listClient: listClient,
 		uploader:   uploader,
 		keyPrefix:  keyPrefix,
+		// Some providers reject CopyObject outright; the config can force the
+		// download-and-reupload fallback instead of discovering it per call
+		copyUnsupported: os.Getenv("S3DOCK_DISABLE_COPY") != "",
 	}, nil
 }
 
+// verboseS3LogMode returns the SDK client log mode for --verbose-s3
+func verboseS3LogMode(verbose bool) aws.ClientLogMode {
+	if !verbose {
+		return 0
+	}
+	return aws.LogRequest | aws.LogResponse
+}
+
+// awsLogger routes AWS SDK client logs through the s3dock logger
+type awsLogger struct{}
+
+func (awsLogger) Logf(classification logging.Classification, format string, v ...interface{}) {
+	if classification == logging.Warn {
+		LogError(format, v...)
+		return
+	}
+	LogDebug(format, v...)
+}
+
+// newAssumeRoleProvider wraps base credentials with an STS assume-role
+// provider, passing the external ID through when one is configured.
+func newAssumeRoleProvider(client stscreds.AssumeRoleAPIClient, roleArn, externalID string) aws.CredentialsProvider {
+	provider := stscreds.NewAssumeRoleProvider(client, roleArn, func(o *stscreds.AssumeRoleOptions) {
+		if externalID != "" {
+			o.ExternalID = aws.String(externalID)
+		}
+	})
+	return aws.NewCredentialsCache(provider)
+}
+
+// endpointServiceWords are hostname labels that name the storage service
+// itself rather than a bucket. An endpoint whose first label is one of these
+// (e.g. minio.s3.internal) is a plain hostname, never bucket-subdomain style.
+var endpointServiceWords = map[string]bool{
+	"s3":      true,
+	"minio":   true,
+	"storage": true,
+}
+
+// bucketSubdomainSuffixes are provider domains known to serve
+// bucket-subdomain endpoints. Detection only fires for these, so self-hosted
+// endpoints (MinIO and friends) are left untouched.
+var bucketSubdomainSuffixes = []string{
+	".amazonaws.com",
+	".wasabisys.com",
+	".digitaloceanspaces.com",
+	".backblazeb2.com",
+}
+
 // extractBaseEndpoint checks if an endpoint is a bucket-subdomain style endpoint
 // (e.g., https://bucket.s3.region.wasabisys.com) and returns the base endpoint and bucket name
 func extractBaseEndpoint(endpoint string) (baseEndpoint, bucket string) {
@@ -77,23 +170,84 @@ func extractBaseEndpoint(endpoint string) (baseEndpoint, bucket string) {
 
 	// Check for patterns like: bucket.s3.region.provider.com
 	// The bucket is the first part before .s3.
-	if idx := strings.Index(e, ".s3."); idx > 0 {
-		bucket = e[:idx]
-		rest := e[idx+1:] // s3.region.provider.com
-		baseEndpoint = strings.TrimPrefix(endpoint, "https://"+bucket+".")
-		baseEndpoint = strings.TrimPrefix(baseEndpoint, "http://"+bucket+".")
-		if strings.HasPrefix(endpoint, "https://") {
-			baseEndpoint = "https://" + rest
-		} else {
-			baseEndpoint = "http://" + rest
+	idx := strings.Index(e, ".s3.")
+	if idx <= 0 {
+		return "", ""
+	}
+
+	bucket = e[:idx]
+	rest := e[idx+1:] // s3.region.provider.com
+
+	if endpointServiceWords[strings.ToLower(bucket)] {
+		return "", ""
+	}
+
+	// Strip any port before matching the provider suffix
+	host := rest
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	matched := false
+	for _, suffix := range bucketSubdomainSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			matched = true
+			break
 		}
-		return baseEndpoint, bucket
 	}
+	if !matched {
+		return "", ""
+	}
+
+	if strings.HasPrefix(endpoint, "https://") {
+		return "https://" + rest, bucket
+	}
+	return "http://" + rest, bucket
+}
+
+// s3RateLimiter paces every S3 call when rate_limit is configured, keeping
+// bulk list/download operations under provider throttling thresholds.
+var s3RateLimiter *rate.Limiter
+
+// SetRateLimit installs a global token-bucket limiter applied to all S3
+// calls (rate_limit in the config, requests per second). Zero or negative
+// disables limiting.
+func SetRateLimit(rps float64) {
+	if rps <= 0 {
+		s3RateLimiter = nil
+		return
+	}
+	s3RateLimiter = rate.NewLimiter(rate.Limit(rps), 1)
+}
+
+// waitForRateLimit blocks until the limiter allows another S3 call, or the
+// context is cancelled. A nil limiter passes through immediately.
+func waitForRateLimit(ctx context.Context) error {
+	if s3RateLimiter == nil {
+		return nil
+	}
+	return s3RateLimiter.Wait(ctx)
+}
 
-	return "", ""
+// dryRunMode short-circuits every S3 mutation (--dry-run). Guarded here in
+// the client rather than per command so new write paths are covered
+// automatically. Reads pass through, so keys and checksums upstream are
+// still computed and the logged plan stays accurate.
+var dryRunMode bool
+
+// SetDryRun toggles dry-run mode: mutation methods log the intended
+// operation and return success without performing it.
+func SetDryRun(enabled bool) {
+	dryRunMode = enabled
 }
 
 func (s *S3ClientImpl) Upload(ctx context.Context, bucket, key string, data io.Reader) error {
+	if dryRunMode {
+		LogInfo("[dry-run] Would upload s3://%s/%s", bucket, key)
+		return nil
+	}
+	if err := waitForRateLimit(ctx); err != nil {
+		return err
+	}
 	_, err := s.uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
@@ -103,6 +257,9 @@ func (s *S3ClientImpl) Upload(ctx context.Context, bucket, key string, data io.R
 }
 
 func (s *S3ClientImpl) Exists(ctx context.Context, bucket, key string) (bool, error) {
+	if err := waitForRateLimit(ctx); err != nil {
+		return false, err
+	}
 	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
@@ -117,7 +274,26 @@ func (s *S3ClientImpl) Exists(ctx context.Context, bucket, key string) (bool, er
 	return true, nil
 }
 
+// HeadETag returns the object's ETag via a HEAD request, with the quotes S3
+// wraps around the value stripped.
+func (s *S3ClientImpl) HeadETag(ctx context.Context, bucket, key string) (string, error) {
+	if err := waitForRateLimit(ctx); err != nil {
+		return "", err
+	}
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(aws.ToString(output.ETag), `"`), nil
+}
+
 func (s *S3ClientImpl) Download(ctx context.Context, bucket, key string) ([]byte, error) {
+	if err := waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
 	downloader := manager.NewDownloader(s.client)
 	buf := manager.NewWriteAtBuffer([]byte{})
 
@@ -142,15 +318,63 @@ func (s *S3ClientImpl) Copy(ctx context.Context, bucket, srcKey, dstKey string)
 	} else {
 		copySource = bucket + "/" + srcKey
 	}
+	if dryRunMode {
+		LogInfo("[dry-run] Would copy s3://%s/%s to s3://%s/%s", bucket, srcKey, bucket, dstKey)
+		return nil
+	}
+	if s.copyUnsupported {
+		return s.copyByDownload(ctx, bucket, srcKey, dstKey)
+	}
+	if err := waitForRateLimit(ctx); err != nil {
+		return err
+	}
+
 	_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
 		Bucket:     aws.String(bucket),
 		Key:        aws.String(dstKey),
 		CopySource: aws.String(copySource),
 	})
+	if err != nil && isCopyUnsupported(err) {
+		// Remember the capability so later copies skip the failing call
+		LogDebug("Provider does not support CopyObject, falling back to download-and-reupload: %v", err)
+		s.copyUnsupported = true
+		return s.copyByDownload(ctx, bucket, srcKey, dstKey)
+	}
 	return err
 }
 
+// copyByDownload emulates CopyObject for providers that reject it by
+// streaming the object down and re-uploading it
+func (s *S3ClientImpl) copyByDownload(ctx context.Context, bucket, srcKey, dstKey string) error {
+	body, err := s.DownloadStream(ctx, bucket, srcKey)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return s.Upload(ctx, bucket, dstKey, body)
+}
+
+// isCopyUnsupported reports whether an error indicates the provider does not
+// implement CopyObject at all (as opposed to a transient or auth failure)
+func isCopyUnsupported(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.ErrorCode() == "NotImplemented" {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "not implemented")
+}
+
 func (s *S3ClientImpl) UploadWithProgress(ctx context.Context, bucket, key string, data io.Reader, size int64, description string) error {
+	if dryRunMode {
+		LogInfo("[dry-run] Would upload s3://%s/%s (%d bytes)", bucket, key, size)
+		return nil
+	}
+	if err := waitForRateLimit(ctx); err != nil {
+		return err
+	}
 	bar := progressbar.DefaultBytes(size, description)
 	defer bar.Finish()
 
@@ -165,6 +389,13 @@ func (s *S3ClientImpl) UploadWithProgress(ctx context.Context, bucket, key strin
 }
 
 func (s *S3ClientImpl) Delete(ctx context.Context, bucket, key string) error {
+	if dryRunMode {
+		LogInfo("[dry-run] Would delete s3://%s/%s", bucket, key)
+		return nil
+	}
+	if err := waitForRateLimit(ctx); err != nil {
+		return err
+	}
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
@@ -174,6 +405,9 @@ func (s *S3ClientImpl) Delete(ctx context.Context, bucket, key string) error {
 
 // DownloadStream streams an object from S3 as an io.ReadCloser.
 func (s *S3ClientImpl) DownloadStream(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	if err := waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := s.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
@@ -198,6 +432,9 @@ func (s *S3ClientImpl) List(ctx context.Context, bucket, prefix string) ([]strin
 	})
 
 	for paginator.HasMorePages() {
+		if err := waitForRateLimit(ctx); err != nil {
+			return nil, err
+		}
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
@@ -2,12 +2,58 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
 )
 
+// stubSTSClient records the assume-role request and returns canned credentials
+type stubSTSClient struct {
+	input *sts.AssumeRoleInput
+}
+
+func (s *stubSTSClient) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	s.input = params
+	expiry := time.Now().Add(time.Hour)
+	return &sts.AssumeRoleOutput{
+		Credentials: &ststypes.Credentials{
+			AccessKeyId:     aws.String("ASSUMED-ACCESS-KEY"),
+			SecretAccessKey: aws.String("assumed-secret"),
+			SessionToken:    aws.String("assumed-token"),
+			Expiration:      &expiry,
+		},
+	}, nil
+}
+
+func TestNewAssumeRoleProvider_AssumesRole(t *testing.T) {
+	stub := &stubSTSClient{}
+
+	provider := newAssumeRoleProvider(stub, "arn:aws:iam::123456789012:role/deploy", "")
+	creds, err := provider.Retrieve(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ASSUMED-ACCESS-KEY", creds.AccessKeyID)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/deploy", *stub.input.RoleArn)
+	assert.Nil(t, stub.input.ExternalId)
+}
+
+func TestNewAssumeRoleProvider_ExternalID(t *testing.T) {
+	stub := &stubSTSClient{}
+
+	provider := newAssumeRoleProvider(stub, "arn:aws:iam::123456789012:role/deploy", "partner-id")
+	_, err := provider.Retrieve(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "partner-id", *stub.input.ExternalId)
+}
+
 func TestS3Client_NewS3Client(t *testing.T) {
 	client, err := NewS3Client(context.Background())
 
@@ -30,3 +76,137 @@ func TestS3Client_Upload(t *testing.T) {
 	err = client.Upload(context.Background(), "nonexistent-bucket", "test-key", strings.NewReader("test data"))
 	assert.Error(t, err)
 }
+
+func TestS3Client_DryRunSkipsMutations(t *testing.T) {
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	// Dry-run returns before the AWS client is touched, so a zero client
+	// proves no request was attempted
+	client := &S3ClientImpl{}
+	ctx := context.Background()
+
+	assert.NoError(t, client.Upload(ctx, "test-bucket", "test-key", strings.NewReader("test data")))
+	assert.NoError(t, client.UploadWithProgress(ctx, "test-bucket", "test-key", strings.NewReader("test data"), 9, "Uploading"))
+	assert.NoError(t, client.Copy(ctx, "test-bucket", "src-key", "dst-key"))
+	assert.NoError(t, client.Delete(ctx, "test-bucket", "test-key"))
+}
+
+func TestNewS3Client_RateLimitFromEnv(t *testing.T) {
+	t.Setenv("S3DOCK_RATE_LIMIT", "2.5")
+	defer SetRateLimit(0)
+
+	_, err := NewS3Client(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, s3RateLimiter)
+	assert.Equal(t, 2.5, float64(s3RateLimiter.Limit()))
+}
+
+func TestRateLimiter_PacesCalls(t *testing.T) {
+	// 50 rps with burst 1 means three calls need at least ~40ms
+	SetRateLimit(50)
+	defer SetRateLimit(0)
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, waitForRateLimit(ctx))
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+}
+
+func TestRateLimiter_DisabledPassesThrough(t *testing.T) {
+	SetRateLimit(0)
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, waitForRateLimit(ctx))
+	}
+
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestVerboseS3LogMode(t *testing.T) {
+	assert.Equal(t, aws.ClientLogMode(0), verboseS3LogMode(false))
+
+	mode := verboseS3LogMode(true)
+	assert.True(t, mode.IsRequest())
+	assert.True(t, mode.IsResponse())
+}
+
+func TestIsCopyUnsupported(t *testing.T) {
+	assert.True(t, isCopyUnsupported(&smithy.GenericAPIError{Code: "NotImplemented", Message: "copy not available"}))
+	assert.True(t, isCopyUnsupported(errors.New("operation not implemented by this provider")))
+	assert.False(t, isCopyUnsupported(&smithy.GenericAPIError{Code: "AccessDenied", Message: "no"}))
+	assert.False(t, isCopyUnsupported(errors.New("connection refused")))
+}
+
+func TestNewS3Client_DisableServerSideCopy(t *testing.T) {
+	t.Setenv("S3DOCK_DISABLE_COPY", "1")
+
+	client, err := NewS3Client(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, client.copyUnsupported)
+}
+
+func TestExtractBaseEndpoint(t *testing.T) {
+	cases := []struct {
+		name         string
+		endpoint     string
+		baseEndpoint string
+		bucket       string
+	}{
+		{
+			name:         "wasabi bucket-subdomain endpoint",
+			endpoint:     "https://mybucket.s3.eu-central-1.wasabisys.com",
+			baseEndpoint: "https://s3.eu-central-1.wasabisys.com",
+			bucket:       "mybucket",
+		},
+		{
+			name:         "aws bucket-subdomain endpoint",
+			endpoint:     "https://mybucket.s3.us-east-1.amazonaws.com",
+			baseEndpoint: "https://s3.us-east-1.amazonaws.com",
+			bucket:       "mybucket",
+		},
+		{
+			name:     "minio hostname without s3 segment",
+			endpoint: "http://minio.internal:9000",
+		},
+		{
+			name:     "minio hostname with s3 segment is not a bucket subdomain",
+			endpoint: "http://minio.s3.internal:9000",
+		},
+		{
+			name:     "service-word first label",
+			endpoint: "https://s3.s3.example.com",
+		},
+		{
+			name:     "self-hosted domain with s3 segment is left untouched",
+			endpoint: "http://mybucket.s3.internal:9000",
+		},
+		{
+			name:     "plain aws endpoint",
+			endpoint: "https://s3.us-east-1.amazonaws.com",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			baseEndpoint, bucket := extractBaseEndpoint(tc.endpoint)
+			assert.Equal(t, tc.baseEndpoint, baseEndpoint)
+			assert.Equal(t, tc.bucket, bucket)
+		})
+	}
+}
+
+func TestNewS3Client_DisableEndpointDetection(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL", "https://mybucket.s3.eu-central-1.wasabisys.com")
+	t.Setenv("S3DOCK_DISABLE_ENDPOINT_DETECTION", "1")
+
+	client, err := NewS3Client(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, client.keyPrefix)
+	assert.Same(t, client.client, client.listClient)
+}
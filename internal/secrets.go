@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// resolveSecretValue expands indirect credential values so secrets never have
+// to live in the committed config file. Recognized prefixes:
+//
+//	env:NAME    - read from the named environment variable
+//	file:/path  - read from a file (trailing whitespace trimmed)
+//	kms:BASE64  - decrypt a base64-encoded KMS ciphertext
+//
+// Values without a recognized prefix are returned unchanged.
+func resolveSecretValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+		resolved := os.Getenv(name)
+		if resolved == "" {
+			return "", fmt.Errorf("environment variable %s referenced by config is not set", name)
+		}
+		return resolved, nil
+	case strings.HasPrefix(value, "file:"):
+		path := strings.TrimPrefix(value, "file:")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file %s: %w", path, err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case strings.HasPrefix(value, "kms:"):
+		return decryptKMSValue(context.Background(), strings.TrimPrefix(value, "kms:"))
+	}
+	return value, nil
+}
+
+// expandConfigIndirections applies file: and env: indirection to every string
+// value in the config, so bucket names, endpoints, and credentials can come
+// from mounted secrets or the environment without templating the config file.
+// Indirection is expanded before the usual env-var overrides (AWS_REGION,
+// S3DOCK_BUCKET, ...) are considered, so those still take precedence over an
+// indirect config value. kms: indirection is intentionally limited to
+// credential fields (see ResolveConfigWithSources).
+func expandConfigIndirections(config *Config) error {
+	return expandIndirectStrings(reflect.ValueOf(config).Elem())
+}
+
+func expandIndirectStrings(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.String:
+		value := v.String()
+		if strings.HasPrefix(value, "env:") || strings.HasPrefix(value, "file:") {
+			expanded, err := resolveSecretValue(value)
+			if err != nil {
+				return err
+			}
+			v.SetString(expanded)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if err := expandIndirectStrings(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			// Map values are not addressable; expand a copy and store it back
+			elem := reflect.New(v.MapIndex(key).Type()).Elem()
+			elem.Set(v.MapIndex(key))
+			if err := expandIndirectStrings(elem); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return expandIndirectStrings(v.Elem())
+		}
+	}
+	return nil
+}
+
+// decryptKMSValue decrypts a base64-encoded KMS ciphertext using the default
+// AWS credential chain. The key ID is embedded in the ciphertext blob.
+func decryptKMSValue(ctx context.Context, encrypted string) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode kms ciphertext: %w", err)
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS config for KMS decryption: %w", err)
+	}
+
+	client := kms.NewFromConfig(cfg)
+	output, err := client.Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: ciphertext})
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt kms value: %w", err)
+	}
+
+	return string(output.Plaintext), nil
+}
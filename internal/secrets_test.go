@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSecretValue_Plain(t *testing.T) {
+	resolved, err := resolveSecretValue("plain-secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "plain-secret", resolved)
+}
+
+func TestResolveSecretValue_Env(t *testing.T) {
+	os.Setenv("S3DOCK_TEST_SECRET", "from-env")
+	defer os.Unsetenv("S3DOCK_TEST_SECRET")
+
+	resolved, err := resolveSecretValue("env:S3DOCK_TEST_SECRET")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", resolved)
+}
+
+func TestResolveSecretValue_EnvMissing(t *testing.T) {
+	_, err := resolveSecretValue("env:S3DOCK_TEST_SECRET_UNSET")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "S3DOCK_TEST_SECRET_UNSET")
+}
+
+func TestResolveSecretValue_File(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(secretFile, []byte("from-file\n"), 0600)
+	assert.NoError(t, err)
+
+	resolved, err := resolveSecretValue("file:" + secretFile)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "from-file", resolved)
+}
+
+func TestResolveSecretValue_FileMissing(t *testing.T) {
+	_, err := resolveSecretValue("file:/nonexistent/secret")
+
+	assert.Error(t, err)
+}
+
+func TestResolveSecretValue_KMSInvalidBase64(t *testing.T) {
+	_, err := resolveSecretValue("kms:not-base64!!!")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "kms ciphertext")
+}
+
+func TestLoadConfig_StringIndirection(t *testing.T) {
+	endpointFile := filepath.Join(t.TempDir(), "endpoint")
+	err := os.WriteFile(endpointFile, []byte("http://minio.internal:9000\n"), 0644)
+	assert.NoError(t, err)
+
+	testConfigContent := `{
+		"profiles": {
+			"default": {
+				"bucket": "env:S3DOCK_TEST_BUCKET",
+				"endpoint": "file:` + endpointFile + `"
+			}
+		}
+	}`
+
+	tmpFile := filepath.Join(t.TempDir(), "indirect-config.json5")
+	err = os.WriteFile(tmpFile, []byte(testConfigContent), 0644)
+	assert.NoError(t, err)
+
+	os.Setenv("S3DOCK_TEST_BUCKET", "bucket-from-env")
+	defer os.Unsetenv("S3DOCK_TEST_BUCKET")
+
+	config, err := LoadConfig(tmpFile)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bucket-from-env", config.Profiles["default"].Bucket)
+	assert.Equal(t, "http://minio.internal:9000", config.Profiles["default"].Endpoint)
+}
+
+func TestLoadConfig_StringIndirectionError(t *testing.T) {
+	testConfigContent := `{
+		"profiles": {
+			"default": {
+				"bucket": "env:S3DOCK_TEST_BUCKET_UNSET"
+			}
+		}
+	}`
+
+	tmpFile := filepath.Join(t.TempDir(), "indirect-bad.json5")
+	err := os.WriteFile(tmpFile, []byte(testConfigContent), 0644)
+	assert.NoError(t, err)
+
+	_, err = LoadConfig(tmpFile)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "S3DOCK_TEST_BUCKET_UNSET")
+}
+
+func TestResolveConfig_EnvIndirectSecret(t *testing.T) {
+	testConfigContent := `{
+		"profiles": {
+			"default": {
+				"bucket": "config-bucket",
+				"secret_key": "env:S3DOCK_TEST_INDIRECT_SECRET"
+			}
+		}
+	}`
+
+	tmpFile := filepath.Join(t.TempDir(), "indirect.json5")
+	err := os.WriteFile(tmpFile, []byte(testConfigContent), 0644)
+	assert.NoError(t, err)
+
+	os.Setenv("S3DOCK_TEST_INDIRECT_SECRET", "resolved-secret")
+	defer os.Unsetenv("S3DOCK_TEST_INDIRECT_SECRET")
+
+	resolved, err := ResolveConfig(tmpFile, "default", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "resolved-secret", resolved.SecretKey)
+}
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"strings"
@@ -8,9 +9,10 @@ import (
 )
 
 type ImageTagger struct {
-	s3     S3Client
-	bucket string
-	audit  AuditLogger
+	s3           S3Client
+	bucket       string
+	audit        AuditLogger
+	pathTemplate string
 }
 
 func NewImageTagger(s3Client S3Client, bucket string) *ImageTagger {
@@ -22,6 +24,17 @@ func NewImageTagger(s3Client S3Client, bucket string) *ImageTagger {
 	}
 }
 
+// DisableAudit suppresses audit logging for this invocation (--no-audit)
+func (t *ImageTagger) DisableAudit() {
+	t.audit = NopAuditLogger{}
+}
+
+// SetPathTemplate overrides the image key layout (naming.path_template in the
+// config) used to locate images from their references.
+func (t *ImageTagger) SetPathTemplate(template string) {
+	t.pathTemplate = template
+}
+
 func (t *ImageTagger) Tag(ctx context.Context, imageRef, version string) (*TagResult, error) {
 	LogInfo("Creating tag %s for image %s", version, imageRef)
 
@@ -34,10 +47,13 @@ func (t *ImageTagger) Tag(ctx context.Context, imageRef, version string) (*TagRe
 
 	LogDebug("Parsed image reference - app: %s, git time: %s, git hash: %s", appName, gitTime, gitHash)
 
+	if err := ValidateAppName(appName); err != nil {
+		LogError("Invalid app name: %v", err)
+		return nil, err
+	}
+
 	// Construct expected image S3 path
-	yearMonth := time.Now().Format("200601") // Use current year/month for lookup
-	imageFilename := fmt.Sprintf("%s-%s-%s.tar.gz", appName, gitTime, gitHash)
-	imageS3Path := fmt.Sprintf("images/%s/%s/%s", appName, yearMonth, imageFilename)
+	imageS3Path := resolveImageKey(t.pathTemplate, appName, gitTime, gitHash)
 
 	LogDebug("Looking for image at S3 path: %s", imageS3Path)
 
@@ -90,12 +106,69 @@ func (t *ImageTagger) Tag(ctx context.Context, imageRef, version string) (*TagRe
 	}, nil
 }
 
+// TagFromEnvironment resolves the environment's current image and tags that
+// concrete image as version (s3dock tag <app> <environment> <version>).
+// Release managers can cut a version from whatever an environment points to
+// without copying the SHA by hand.
+func (t *ImageTagger) TagFromEnvironment(ctx context.Context, appName, environment, version string) (*TagResult, error) {
+	current := NewCurrentService(t.s3, t.bucket)
+	imageRef, err := current.GetCurrentImage(ctx, appName, environment)
+	if err != nil {
+		LogError("Failed to resolve current image for %s/%s: %v", appName, environment, err)
+		return nil, fmt.Errorf("failed to resolve current image for %s/%s: %w", appName, environment, err)
+	}
+
+	LogInfo("Tagging %s's current image %s as %s", environment, imageRef, version)
+	return t.Tag(ctx, imageRef, version)
+}
+
+// PruneDanglingTags deletes the dangling tags in the given list and records
+// an audit event for each deletion. Non-dangling entries are left untouched,
+// so the output of MarkDanglingTags can be passed in unfiltered.
+func (t *ImageTagger) PruneDanglingTags(ctx context.Context, appName string, tags []TagInfo) ([]string, error) {
+	var deleted []string
+	for _, tag := range tags {
+		if !tag.Dangling {
+			continue
+		}
+
+		LogInfo("Deleting dangling tag %s (target %s is gone)", tag.Version, tag.TargetImage)
+		if err := t.s3.Delete(ctx, t.bucket, tag.S3Path); err != nil {
+			LogError("Failed to delete tag %s: %v", tag.S3Path, err)
+			return deleted, fmt.Errorf("failed to delete tag %s: %w", tag.S3Path, err)
+		}
+		deleted = append(deleted, tag.Version)
+
+		// The target image is gone, but its reference still carries the git coordinates
+		gitHash := ""
+		gitTime := ""
+		if app, parsedTime, parsedHash, err := ParseImageReference(tag.TargetImage); err == nil && app == appName {
+			gitTime = parsedTime
+			gitHash = parsedHash
+		}
+
+		auditEvent, err := CreateTagDeleteEvent(appName, gitHash, gitTime, tag.TargetImage, tag.Version, tag.S3Path)
+		if err == nil {
+			t.audit.LogEvent(ctx, auditEvent)
+		}
+	}
+	return deleted, nil
+}
+
 type ImagePromoter struct {
-	s3     S3Client
-	bucket string
-	audit  AuditLogger
+	s3              S3Client
+	bucket          string
+	audit           AuditLogger
+	pathTemplate    string
+	actor           string
+	consistencyWait time.Duration
+	auditSkips      bool
 }
 
+// consistencyPollInterval is how often waitForVisibility re-reads a freshly
+// written pointer. A variable so tests can shorten it.
+var consistencyPollInterval = 500 * time.Millisecond
+
 func NewImagePromoter(s3Client S3Client, bucket string) *ImagePromoter {
 	auditLogger := NewS3AuditLogger(s3Client, bucket)
 	return &ImagePromoter{
@@ -105,9 +178,111 @@ func NewImagePromoter(s3Client S3Client, bucket string) *ImagePromoter {
 	}
 }
 
+// DisableAudit suppresses audit logging for this invocation (--no-audit)
+func (p *ImagePromoter) DisableAudit() {
+	p.audit = NopAuditLogger{}
+}
+
+// AuditSkips records a no_op audit event when a promotion is skipped because
+// the target is unchanged (--no-skip-identical), so the audit trail reflects
+// every promotion attempt rather than only the ones that moved a pointer.
+func (p *ImagePromoter) AuditSkips() {
+	p.auditSkips = true
+}
+
+// logSkippedPromotion emits the no_op audit event for a skipped promotion
+// when skip auditing is enabled.
+func (p *ImagePromoter) logSkippedPromotion(ctx context.Context, appName, gitHash, gitTime, environment, source, sourceType, pointerPath, previousTarget string) error {
+	if !p.auditSkips {
+		return nil
+	}
+
+	auditEvent, err := CreateNoOpPromotionEvent(appName, gitHash, gitTime, environment, source, sourceType, pointerPath, previousTarget)
+	if err != nil {
+		LogError("Failed to create no-op promotion audit event: %v", err)
+		return fmt.Errorf("failed to create no-op promotion audit event: %w", err)
+	}
+
+	if err := p.audit.LogEvent(ctx, auditEvent); err != nil {
+		LogError("Failed to log no-op promotion audit event: %v", err)
+		return fmt.Errorf("failed to log no-op promotion audit event: %w", err)
+	}
+
+	return nil
+}
+
+// SetPathTemplate overrides the image key layout (naming.path_template in the
+// config) used to locate images from their references.
+func (p *ImagePromoter) SetPathTemplate(template string) {
+	p.pathTemplate = template
+}
+
+// SetActor overrides the OS user recorded on pointers and audit events
+// (--actor). Dual-control approvals compare actors, so CI systems acting on
+// behalf of a person should set this.
+func (p *ImagePromoter) SetActor(actor string) {
+	p.actor = actor
+}
+
+// SetConsistencyWait enables a bounded read-after-write check
+// (--wait-for-consistency): after writing a pointer the promoter polls until
+// the written bytes are readable back, for at most the given duration. Off by
+// default - AWS S3 is strongly consistent - but useful against eventually
+// consistent S3-compatible stores.
+func (p *ImagePromoter) SetConsistencyWait(wait time.Duration) {
+	p.consistencyWait = wait
+}
+
+// writePointer uploads a pointer object and, when a consistency wait is
+// configured, blocks until the write is visible to readers.
+func (p *ImagePromoter) writePointer(ctx context.Context, key string, pointerJSON []byte) error {
+	if err := p.s3.Upload(ctx, p.bucket, key, strings.NewReader(string(pointerJSON))); err != nil {
+		return err
+	}
+	return p.waitForVisibility(ctx, key, pointerJSON)
+}
+
+// waitForVisibility polls the key until a read returns the expected bytes or
+// the configured wait elapses.
+func (p *ImagePromoter) waitForVisibility(ctx context.Context, key string, expected []byte) error {
+	if p.consistencyWait <= 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(p.consistencyWait)
+	for {
+		data, err := p.s3.Download(ctx, p.bucket, key)
+		if err == nil && bytes.Equal(data, expected) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("pointer %s not visible after %s", key, p.consistencyWait)
+		}
+		LogDebug("Waiting for %s to become readable", key)
+		time.Sleep(consistencyPollInterval)
+	}
+}
+
+// currentActor returns the override set via SetActor, falling back to the OS user.
+func (p *ImagePromoter) currentActor() string {
+	if p.actor != "" {
+		return p.actor
+	}
+	user, err := getCurrentUser()
+	if err != nil {
+		return "unknown"
+	}
+	return user
+}
+
 func (p *ImagePromoter) Promote(ctx context.Context, source, environment string) (*PromoteResult, error) {
 	LogInfo("Promoting %s to %s environment", source, environment)
 
+	if err := ValidateEnvironmentName(environment); err != nil {
+		LogError("Invalid environment name: %v", err)
+		return nil, err
+	}
+
 	appName := ""
 	var pointer *PointerMetadata
 	var err error
@@ -126,9 +301,7 @@ func (p *ImagePromoter) Promote(ctx context.Context, source, environment string)
 		LogDebug("Parsed image reference - app: %s, git time: %s, git hash: %s", appName, gitTime, gitHash)
 
 		// Construct expected image S3 path
-		yearMonth := time.Now().Format("200601") // Use current year/month for lookup
-		imageFilename := fmt.Sprintf("%s-%s-%s.tar.gz", appName, gitTime, gitHash)
-		imageS3Path := fmt.Sprintf("images/%s/%s/%s", appName, yearMonth, imageFilename)
+		imageS3Path := resolveImageKey(p.pathTemplate, appName, gitTime, gitHash)
 
 		LogDebug("Looking for image at S3 path: %s", imageS3Path)
 
@@ -179,6 +352,9 @@ func (p *ImagePromoter) Promote(ctx context.Context, source, environment string)
 				newTargetPath := pointer.TargetPath
 				if existingPointer.TargetPath == newTargetPath {
 					LogInfo("Environment %s is already pointing to %s, skipping promotion", environment, newTargetPath)
+					if err := p.logSkippedPromotion(ctx, appName, pointer.GitHash, pointer.GitTime, environment, source, "image", envKey, previousTarget); err != nil {
+						return nil, err
+					}
 					return &PromoteResult{
 						Source:      source,
 						Environment: environment,
@@ -200,7 +376,7 @@ func (p *ImagePromoter) Promote(ctx context.Context, source, environment string)
 		return nil, fmt.Errorf("failed to serialize environment pointer: %w", err)
 	}
 
-	if err := p.s3.Upload(ctx, p.bucket, envKey, strings.NewReader(string(pointerJSON))); err != nil {
+	if err := p.writePointer(ctx, envKey, pointerJSON); err != nil {
 		LogError("Failed to upload environment pointer to S3: %v", err)
 		return nil, fmt.Errorf("failed to upload environment pointer to S3: %w", err)
 	}
@@ -228,9 +404,380 @@ func (p *ImagePromoter) Promote(ctx context.Context, source, environment string)
 	}, nil
 }
 
+// SchedulePromote writes a pending environment pointer with an effective_at
+// timestamp instead of the live pointer. The promotion only takes effect once
+// apply-pending runs after that time has passed.
+func (p *ImagePromoter) SchedulePromote(ctx context.Context, source, environment string, effectiveAt time.Time) (*PromoteResult, error) {
+	LogInfo("Scheduling promotion of %s to %s environment at %s", source, environment, effectiveAt.Format(time.RFC3339))
+
+	if err := ValidateEnvironmentName(environment); err != nil {
+		LogError("Invalid environment name: %v", err)
+		return nil, err
+	}
+
+	if !strings.Contains(source, ":") {
+		LogError("Scheduling from version tags requires specifying app name - use 'appname:version' format or direct image reference")
+		return nil, fmt.Errorf("scheduling from version tags requires specifying app name - use 'appname:version' format or direct image reference")
+	}
+
+	appName, gitTime, gitHash, err := ParseImageReference(source)
+	if err != nil {
+		LogError("Failed to parse image reference: %v", err)
+		return nil, fmt.Errorf("failed to parse image reference: %w", err)
+	}
+
+	// Construct expected image S3 path
+	imageS3Path := resolveImageKey(p.pathTemplate, appName, gitTime, gitHash)
+
+	// Verify the image exists in S3
+	exists, err := p.s3.Exists(ctx, p.bucket, imageS3Path)
+	if err != nil {
+		LogError("Failed to check if image exists: %v", err)
+		return nil, fmt.Errorf("failed to check if image exists: %w", err)
+	}
+	if !exists {
+		LogError("Image not found in S3: %s", imageS3Path)
+		return nil, fmt.Errorf("image not found in S3: %s", imageS3Path)
+	}
+
+	pointer, err := CreateImagePointer(imageS3Path, gitHash, gitTime, source)
+	if err != nil {
+		LogError("Failed to create image pointer: %v", err)
+		return nil, fmt.Errorf("failed to create image pointer: %w", err)
+	}
+	pointer.EffectiveAt = effectiveAt
+
+	pendingKey := GeneratePendingPointerKey(appName, environment)
+	LogDebug("Pending pointer key: %s", pendingKey)
+
+	pointerJSON, err := pointer.ToJSON()
+	if err != nil {
+		LogError("Failed to serialize pending pointer: %v", err)
+		return nil, fmt.Errorf("failed to serialize pending pointer: %w", err)
+	}
+
+	if err := p.writePointer(ctx, pendingKey, pointerJSON); err != nil {
+		LogError("Failed to upload pending pointer to S3: %v", err)
+		return nil, fmt.Errorf("failed to upload pending pointer to S3: %w", err)
+	}
+
+	LogInfo("Scheduled promotion of %s to %s environment, effective at %s", source, environment, effectiveAt.Format(time.RFC3339))
+
+	return &PromoteResult{
+		Source:      source,
+		Environment: environment,
+		SourceType:  "image",
+		ImageRef:    source,
+		Pending:     true,
+		EffectiveAt: effectiveAt.Format(time.RFC3339),
+	}, nil
+}
+
+// ApplyPending activates a previously scheduled promotion. It fails if no
+// pending pointer exists or if its effective_at time has not passed yet.
+func (p *ImagePromoter) ApplyPending(ctx context.Context, appName, environment string) (*PromoteResult, error) {
+	LogInfo("Applying pending promotion for %s in %s environment", appName, environment)
+
+	if err := ValidateEnvironmentName(environment); err != nil {
+		LogError("Invalid environment name: %v", err)
+		return nil, err
+	}
+	if err := ValidateAppName(appName); err != nil {
+		LogError("Invalid app name: %v", err)
+		return nil, err
+	}
+
+	pendingKey := GeneratePendingPointerKey(appName, environment)
+	LogDebug("Pending pointer key: %s", pendingKey)
+
+	exists, err := p.s3.Exists(ctx, p.bucket, pendingKey)
+	if err != nil {
+		LogError("Failed to check if pending pointer exists: %v", err)
+		return nil, fmt.Errorf("failed to check if pending pointer exists: %w", err)
+	}
+	if !exists {
+		LogError("No pending promotion found for %s/%s", appName, environment)
+		return nil, fmt.Errorf("no pending promotion found for %s/%s", appName, environment)
+	}
+
+	pendingData, err := p.s3.Download(ctx, p.bucket, pendingKey)
+	if err != nil {
+		LogError("Failed to download pending pointer: %v", err)
+		return nil, fmt.Errorf("failed to download pending pointer: %w", err)
+	}
+
+	pointer, err := PointerMetadataFromJSON(pendingData)
+	if err != nil {
+		LogError("Failed to parse pending pointer: %v", err)
+		return nil, fmt.Errorf("failed to parse pending pointer: %w", err)
+	}
+
+	if !pointer.EffectiveAt.IsZero() && time.Now().Before(pointer.EffectiveAt) {
+		LogError("Pending promotion for %s/%s does not take effect until %s", appName, environment, pointer.EffectiveAt.Format(time.RFC3339))
+		return nil, fmt.Errorf("pending promotion for %s/%s does not take effect until %s", appName, environment, pointer.EffectiveAt.Format(time.RFC3339))
+	}
+
+	// Check for existing live pointer to track previous state
+	envKey := GeneratePointerKey(appName, environment)
+	var previousTarget string
+
+	existingExists, err := p.s3.Exists(ctx, p.bucket, envKey)
+	if err == nil && existingExists {
+		existingData, err := p.s3.Download(ctx, p.bucket, envKey)
+		if err == nil {
+			existingPointer, err := PointerMetadataFromJSON(existingData)
+			if err == nil {
+				previousTarget = existingPointer.TargetPath
+				LogDebug("Previous target: %s", previousTarget)
+			}
+		}
+	}
+
+	// Activate: write the live pointer without the effective_at marker
+	pointer.EffectiveAt = time.Time{}
+	pointerJSON, err := pointer.ToJSON()
+	if err != nil {
+		LogError("Failed to serialize environment pointer: %v", err)
+		return nil, fmt.Errorf("failed to serialize environment pointer: %w", err)
+	}
+
+	if err := p.writePointer(ctx, envKey, pointerJSON); err != nil {
+		LogError("Failed to upload environment pointer to S3: %v", err)
+		return nil, fmt.Errorf("failed to upload environment pointer to S3: %w", err)
+	}
+
+	if err := p.s3.Delete(ctx, p.bucket, pendingKey); err != nil {
+		LogError("Failed to delete pending pointer: %v", err)
+		return nil, fmt.Errorf("failed to delete pending pointer: %w", err)
+	}
+
+	sourceType := "image"
+	source := pointer.SourceImage
+	if pointer.SourceTag != "" {
+		sourceType = "tag"
+		source = fmt.Sprintf("%s:%s", appName, pointer.SourceTag)
+	}
+
+	LogInfo("Successfully applied pending promotion of %s to %s environment", source, environment)
+
+	auditEvent, err := CreatePromotionEvent(appName, pointer.GitHash, pointer.GitTime, environment, source, sourceType, envKey, previousTarget)
+	if err != nil {
+		LogError("Failed to create promotion audit event: %v", err)
+		return nil, fmt.Errorf("failed to create promotion audit event: %w", err)
+	}
+
+	if err := p.audit.LogEvent(ctx, auditEvent); err != nil {
+		LogError("Failed to log promotion audit event: %v", err)
+		return nil, fmt.Errorf("failed to log promotion audit event: %w", err)
+	}
+
+	return &PromoteResult{
+		Source:      source,
+		Environment: environment,
+		SourceType:  sourceType,
+		ImageRef:    pointer.SourceImage,
+		Skipped:     false,
+	}, nil
+}
+
+// RequestApproval writes a pending approval record instead of the live
+// pointer, for environments listed in promotion.require_approval. The record
+// includes the requesting actor; a different actor must run approve to
+// activate it (separation of duties).
+func (p *ImagePromoter) RequestApproval(ctx context.Context, source, environment string) (*PromoteResult, error) {
+	LogInfo("Requesting approval to promote %s to %s environment", source, environment)
+
+	if err := ValidateEnvironmentName(environment); err != nil {
+		LogError("Invalid environment name: %v", err)
+		return nil, err
+	}
+
+	if !strings.Contains(source, ":") {
+		LogError("Approval requests from version tags require specifying app name - use 'appname:version' format or direct image reference")
+		return nil, fmt.Errorf("approval requests from version tags require specifying app name - use 'appname:version' format or direct image reference")
+	}
+
+	appName, gitTime, gitHash, err := ParseImageReference(source)
+	if err != nil {
+		LogError("Failed to parse image reference: %v", err)
+		return nil, fmt.Errorf("failed to parse image reference: %w", err)
+	}
+
+	// Construct expected image S3 path
+	imageS3Path := resolveImageKey(p.pathTemplate, appName, gitTime, gitHash)
+
+	// Verify the image exists in S3
+	exists, err := p.s3.Exists(ctx, p.bucket, imageS3Path)
+	if err != nil {
+		LogError("Failed to check if image exists: %v", err)
+		return nil, fmt.Errorf("failed to check if image exists: %w", err)
+	}
+	if !exists {
+		LogError("Image not found in S3: %s", imageS3Path)
+		return nil, fmt.Errorf("image not found in S3: %s", imageS3Path)
+	}
+
+	pointer, err := CreateImagePointer(imageS3Path, gitHash, gitTime, source)
+	if err != nil {
+		LogError("Failed to create image pointer: %v", err)
+		return nil, fmt.Errorf("failed to create image pointer: %w", err)
+	}
+	pointer.PromotedBy = p.currentActor()
+	pointer.RequiresApproval = true
+
+	pendingKey := GeneratePendingPointerKey(appName, environment)
+	LogDebug("Pending approval key: %s", pendingKey)
+
+	pointerJSON, err := pointer.ToJSON()
+	if err != nil {
+		LogError("Failed to serialize pending pointer: %v", err)
+		return nil, fmt.Errorf("failed to serialize pending pointer: %w", err)
+	}
+
+	if err := p.writePointer(ctx, pendingKey, pointerJSON); err != nil {
+		LogError("Failed to upload pending pointer to S3: %v", err)
+		return nil, fmt.Errorf("failed to upload pending pointer to S3: %w", err)
+	}
+
+	LogInfo("Promotion of %s to %s recorded, awaiting approval by a second actor (requested by %s)", source, environment, pointer.PromotedBy)
+
+	return &PromoteResult{
+		Source:           source,
+		Environment:      environment,
+		SourceType:       "image",
+		ImageRef:         source,
+		Pending:          true,
+		AwaitingApproval: true,
+	}, nil
+}
+
+// Approve activates a promotion awaiting approval. The approving actor must
+// differ from the requester recorded on the pending pointer; self-approval is
+// rejected.
+func (p *ImagePromoter) Approve(ctx context.Context, appName, environment string) (*PromoteResult, error) {
+	LogInfo("Approving pending promotion for %s in %s environment", appName, environment)
+
+	if err := ValidateEnvironmentName(environment); err != nil {
+		LogError("Invalid environment name: %v", err)
+		return nil, err
+	}
+	if err := ValidateAppName(appName); err != nil {
+		LogError("Invalid app name: %v", err)
+		return nil, err
+	}
+
+	pendingKey := GeneratePendingPointerKey(appName, environment)
+	LogDebug("Pending approval key: %s", pendingKey)
+
+	exists, err := p.s3.Exists(ctx, p.bucket, pendingKey)
+	if err != nil {
+		LogError("Failed to check if pending pointer exists: %v", err)
+		return nil, fmt.Errorf("failed to check if pending pointer exists: %w", err)
+	}
+	if !exists {
+		LogError("No promotion awaiting approval for %s/%s", appName, environment)
+		return nil, fmt.Errorf("no promotion awaiting approval for %s/%s", appName, environment)
+	}
+
+	pendingData, err := p.s3.Download(ctx, p.bucket, pendingKey)
+	if err != nil {
+		LogError("Failed to download pending pointer: %v", err)
+		return nil, fmt.Errorf("failed to download pending pointer: %w", err)
+	}
+
+	pointer, err := PointerMetadataFromJSON(pendingData)
+	if err != nil {
+		LogError("Failed to parse pending pointer: %v", err)
+		return nil, fmt.Errorf("failed to parse pending pointer: %w", err)
+	}
+
+	if !pointer.RequiresApproval {
+		LogError("Pending promotion for %s/%s does not require approval - use apply-pending", appName, environment)
+		return nil, fmt.Errorf("pending promotion for %s/%s does not require approval - use apply-pending", appName, environment)
+	}
+
+	approver := p.currentActor()
+	if approver == pointer.PromotedBy {
+		LogError("Self-approval rejected: promotion was requested by %s", pointer.PromotedBy)
+		return nil, fmt.Errorf("self-approval rejected: promotion was requested by %s", pointer.PromotedBy)
+	}
+
+	// Check for existing live pointer to track previous state
+	envKey := GeneratePointerKey(appName, environment)
+	var previousTarget string
+
+	existingExists, err := p.s3.Exists(ctx, p.bucket, envKey)
+	if err == nil && existingExists {
+		existingData, err := p.s3.Download(ctx, p.bucket, envKey)
+		if err == nil {
+			existingPointer, err := PointerMetadataFromJSON(existingData)
+			if err == nil {
+				previousTarget = existingPointer.TargetPath
+				LogDebug("Previous target: %s", previousTarget)
+			}
+		}
+	}
+
+	// Activate: write the live pointer with the approval marker cleared and
+	// the approver recorded
+	pointer.RequiresApproval = false
+	pointer.ApprovedBy = approver
+	pointerJSON, err := pointer.ToJSON()
+	if err != nil {
+		LogError("Failed to serialize environment pointer: %v", err)
+		return nil, fmt.Errorf("failed to serialize environment pointer: %w", err)
+	}
+
+	if err := p.writePointer(ctx, envKey, pointerJSON); err != nil {
+		LogError("Failed to upload environment pointer to S3: %v", err)
+		return nil, fmt.Errorf("failed to upload environment pointer to S3: %w", err)
+	}
+
+	if err := p.s3.Delete(ctx, p.bucket, pendingKey); err != nil {
+		LogError("Failed to delete pending pointer: %v", err)
+		return nil, fmt.Errorf("failed to delete pending pointer: %w", err)
+	}
+
+	LogInfo("Successfully approved promotion of %s to %s environment (requested by %s, approved by %s)", pointer.SourceImage, environment, pointer.PromotedBy, approver)
+
+	auditEvent, err := CreatePromotionEvent(appName, pointer.GitHash, pointer.GitTime, environment, pointer.SourceImage, "image", envKey, previousTarget)
+	if err != nil {
+		LogError("Failed to create promotion audit event: %v", err)
+		return nil, fmt.Errorf("failed to create promotion audit event: %w", err)
+	}
+	auditEvent.User = approver
+	if details, ok := auditEvent.Details.(PromotionEventDetails); ok {
+		details.RequestedBy = pointer.PromotedBy
+		details.ApprovedBy = approver
+		auditEvent.Details = details
+	}
+
+	if err := p.audit.LogEvent(ctx, auditEvent); err != nil {
+		LogError("Failed to log promotion audit event: %v", err)
+		return nil, fmt.Errorf("failed to log promotion audit event: %w", err)
+	}
+
+	return &PromoteResult{
+		Source:      pointer.SourceImage,
+		Environment: environment,
+		SourceType:  "image",
+		ImageRef:    pointer.SourceImage,
+		Skipped:     false,
+	}, nil
+}
+
 func (p *ImagePromoter) PromoteFromTag(ctx context.Context, appName, version, environment string) (*PromoteResult, error) {
 	LogInfo("Promoting %s %s to %s environment", appName, version, environment)
 
+	if err := ValidateEnvironmentName(environment); err != nil {
+		LogError("Invalid environment name: %v", err)
+		return nil, err
+	}
+	if err := ValidateAppName(appName); err != nil {
+		LogError("Invalid app name: %v", err)
+		return nil, err
+	}
+
 	// Download the tag to get image information
 	tagKey := GenerateTagKey(appName, version)
 	LogDebug("Looking for tag at S3 key: %s", tagKey)
@@ -288,6 +835,9 @@ func (p *ImagePromoter) PromoteFromTag(ctx context.Context, appName, version, en
 				newTargetPath := envPointer.TargetPath
 				if existingPointer.TargetPath == newTargetPath {
 					LogInfo("Environment %s is already pointing to %s, skipping tag promotion", environment, newTargetPath)
+					if err := p.logSkippedPromotion(ctx, appName, tagPointer.GitHash, tagPointer.GitTime, environment, fmt.Sprintf("%s:%s", appName, version), "tag", envKey, previousTarget); err != nil {
+						return nil, err
+					}
 					return &PromoteResult{
 						Source:      fmt.Sprintf("%s:%s", appName, version),
 						Environment: environment,
@@ -309,7 +859,7 @@ func (p *ImagePromoter) PromoteFromTag(ctx context.Context, appName, version, en
 		return nil, fmt.Errorf("failed to serialize environment pointer: %w", err)
 	}
 
-	if err := p.s3.Upload(ctx, p.bucket, envKey, strings.NewReader(string(pointerJSON))); err != nil {
+	if err := p.writePointer(ctx, envKey, pointerJSON); err != nil {
 		LogError("Failed to upload environment pointer to S3: %v", err)
 		return nil, fmt.Errorf("failed to upload environment pointer to S3: %w", err)
 	}
@@ -337,3 +887,139 @@ func (p *ImagePromoter) PromoteFromTag(ctx context.Context, appName, version, en
 		Skipped:     false,
 	}, nil
 }
+
+// PromoteFromEnvironment points dstEnv at the concrete image srcEnv currently
+// resolves to ("promote what's in staging to prod"). The destination always
+// receives an image pointer — even when the source environment points at a
+// tag — so later tag moves cannot silently change what dstEnv runs.
+func (p *ImagePromoter) PromoteFromEnvironment(ctx context.Context, appName, srcEnv, dstEnv string) (*PromoteResult, error) {
+	LogInfo("Promoting %s %s to %s environment", appName, srcEnv, dstEnv)
+
+	if err := ValidateAppName(appName); err != nil {
+		LogError("Invalid app name: %v", err)
+		return nil, err
+	}
+	if err := ValidateEnvironmentName(srcEnv); err != nil {
+		LogError("Invalid source environment name: %v", err)
+		return nil, err
+	}
+	if err := ValidateEnvironmentName(dstEnv); err != nil {
+		LogError("Invalid environment name: %v", err)
+		return nil, err
+	}
+	if srcEnv == dstEnv {
+		LogError("Source and destination environments are the same: %s", srcEnv)
+		return nil, fmt.Errorf("source and destination environments are the same: %s", srcEnv)
+	}
+
+	// Download the source environment pointer
+	srcKey := GeneratePointerKey(appName, srcEnv)
+	LogDebug("Looking for source environment pointer at S3 key: %s", srcKey)
+
+	srcExists, err := p.s3.Exists(ctx, p.bucket, srcKey)
+	if err != nil {
+		LogError("Failed to check if source environment pointer exists: %v", err)
+		return nil, fmt.Errorf("failed to check if source environment pointer exists: %w", err)
+	}
+	if !srcExists {
+		LogError("Environment pointer not found: %s/%s", appName, srcEnv)
+		return nil, fmt.Errorf("environment pointer not found: %s/%s", appName, srcEnv)
+	}
+
+	srcData, err := p.s3.Download(ctx, p.bucket, srcKey)
+	if err != nil {
+		LogError("Failed to download source environment pointer: %v", err)
+		return nil, fmt.Errorf("failed to download source environment pointer: %w", err)
+	}
+
+	srcPointer, err := PointerMetadataFromJSON(srcData)
+	if err != nil {
+		LogError("Failed to parse source environment pointer: %v", err)
+		return nil, fmt.Errorf("failed to parse source environment pointer: %w", err)
+	}
+
+	// Resolve through any tag indirection to the concrete image path
+	imageS3Path, err := ResolveImagePath(ctx, p.s3, p.bucket, srcPointer)
+	if err != nil {
+		LogError("Failed to resolve source environment target: %v", err)
+		return nil, fmt.Errorf("failed to resolve source environment target: %w", err)
+	}
+
+	LogDebug("Source environment %s resolves to image: %s", srcEnv, imageS3Path)
+
+	pointer, err := CreateImagePointer(imageS3Path, srcPointer.GitHash, srcPointer.GitTime, srcPointer.SourceImage)
+	if err != nil {
+		LogError("Failed to create image pointer: %v", err)
+		return nil, fmt.Errorf("failed to create image pointer: %w", err)
+	}
+
+	// Check for existing pointer to track previous state and detect duplicates
+	envKey := GeneratePointerKey(appName, dstEnv)
+	LogDebug("Environment pointer key: %s", envKey)
+
+	var previousTarget string
+
+	existingExists, err := p.s3.Exists(ctx, p.bucket, envKey)
+	if err == nil && existingExists {
+		LogDebug("Existing environment pointer found, checking previous target")
+		existingData, err := p.s3.Download(ctx, p.bucket, envKey)
+		if err == nil {
+			existingPointer, err := PointerMetadataFromJSON(existingData)
+			if err == nil {
+				previousTarget = existingPointer.TargetPath
+				LogDebug("Previous target: %s", previousTarget)
+
+				// Check if we're promoting to the same target
+				if existingPointer.TargetPath == pointer.TargetPath {
+					LogInfo("Environment %s is already pointing to %s, skipping promotion", dstEnv, pointer.TargetPath)
+					if err := p.logSkippedPromotion(ctx, appName, srcPointer.GitHash, srcPointer.GitTime, dstEnv, srcEnv, "environment", envKey, previousTarget); err != nil {
+						return nil, err
+					}
+					return &PromoteResult{
+						Source:      srcEnv,
+						Environment: dstEnv,
+						SourceType:  "environment",
+						ImageRef:    srcPointer.SourceImage,
+						Skipped:     true,
+					}, nil
+				}
+				LogDebug("Target changed from %s to %s, proceeding with promotion", existingPointer.TargetPath, pointer.TargetPath)
+			}
+		}
+	}
+
+	// Upload pointer to destination environment
+	LogDebug("Uploading environment pointer to S3")
+	pointerJSON, err := pointer.ToJSON()
+	if err != nil {
+		LogError("Failed to serialize environment pointer: %v", err)
+		return nil, fmt.Errorf("failed to serialize environment pointer: %w", err)
+	}
+
+	if err := p.writePointer(ctx, envKey, pointerJSON); err != nil {
+		LogError("Failed to upload environment pointer to S3: %v", err)
+		return nil, fmt.Errorf("failed to upload environment pointer to S3: %w", err)
+	}
+
+	LogInfo("Successfully promoted %s %s to %s environment", appName, srcEnv, dstEnv)
+
+	// Log audit event recording the source environment
+	auditEvent, err := CreatePromotionEvent(appName, srcPointer.GitHash, srcPointer.GitTime, dstEnv, srcEnv, "environment", envKey, previousTarget)
+	if err != nil {
+		LogError("Failed to create environment promotion audit event: %v", err)
+		return nil, fmt.Errorf("failed to create environment promotion audit event: %w", err)
+	}
+
+	if err := p.audit.LogEvent(ctx, auditEvent); err != nil {
+		LogError("Failed to log environment promotion audit event: %v", err)
+		return nil, fmt.Errorf("failed to log environment promotion audit event: %w", err)
+	}
+
+	return &PromoteResult{
+		Source:      srcEnv,
+		Environment: dstEnv,
+		SourceType:  "environment",
+		ImageRef:    srcPointer.SourceImage,
+		Skipped:     false,
+	}, nil
+}
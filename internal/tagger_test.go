@@ -3,8 +3,10 @@ package internal
 import (
 	"context"
 	"errors"
+	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -36,6 +38,29 @@ func TestImageTagger_Tag_Success(t *testing.T) {
 	mockS3.AssertExpectations(t)
 }
 
+func TestImageTagger_Tag_NoAudit(t *testing.T) {
+	mockS3 := new(MockS3Client)
+
+	// Mock image exists check
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	})).Return(true, nil)
+
+	// Mock tag upload; no audit/ upload is mocked, so an audit write would
+	// fail the test as an unexpected call
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "tags/") && strings.HasSuffix(key, ".json")
+	}), mock.Anything).Return(nil)
+
+	tagger := NewImageTagger(mockS3, "test-bucket")
+	tagger.DisableAudit()
+
+	_, err := tagger.Tag(context.Background(), "myapp:20250721-1430-abc1234", "v1.2.0")
+
+	assert.NoError(t, err)
+	mockS3.AssertExpectations(t)
+}
+
 func TestImageTagger_Tag_ImageNotFound(t *testing.T) {
 	mockS3 := new(MockS3Client)
 
@@ -173,3 +198,365 @@ func TestImagePromoter_PromoteFromTag_DownloadError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to download tag")
 	mockS3.AssertExpectations(t)
 }
+
+func TestImagePromoter_RequestApproval_RecordsRequester(t *testing.T) {
+	mockS3 := new(MockS3Client)
+
+	// Image exists
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "images/")
+	})).Return(true, nil)
+
+	// Pending approval record is written, not the live pointer
+	var uploaded []byte
+	mockS3.On("Upload", mock.Anything, "test-bucket", "pointers/myapp/production.pending.json", mock.Anything).Run(func(args mock.Arguments) {
+		data := make([]byte, 4096)
+		n, _ := args.Get(3).(io.Reader).Read(data)
+		uploaded = data[:n]
+	}).Return(nil)
+
+	promoter := NewImagePromoter(mockS3, "test-bucket")
+	promoter.SetActor("alice")
+
+	result, err := promoter.RequestApproval(context.Background(), "myapp:20250721-2118-f7a5a27", "production")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Pending)
+	assert.True(t, result.AwaitingApproval)
+
+	pointer, err := PointerMetadataFromJSON(uploaded)
+	assert.NoError(t, err)
+	assert.True(t, pointer.RequiresApproval)
+	assert.Equal(t, "alice", pointer.PromotedBy)
+
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePromoter_Approve_DistinctActor(t *testing.T) {
+	mockS3 := new(MockS3Client)
+
+	pending := &PointerMetadata{
+		TargetType:       TargetTypeImage,
+		TargetPath:       "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		PromotedBy:       "alice",
+		GitHash:          "f7a5a27",
+		GitTime:          "20250721-2118",
+		SourceImage:      "myapp:20250721-2118-f7a5a27",
+		RequiresApproval: true,
+	}
+	pendingJSON, _ := pending.ToJSON()
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.pending.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/production.pending.json").Return(pendingJSON, nil)
+
+	// No existing live pointer
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(false, nil)
+
+	// Live pointer written with the approver recorded
+	var uploaded []byte
+	mockS3.On("Upload", mock.Anything, "test-bucket", "pointers/myapp/production.json", mock.Anything).Run(func(args mock.Arguments) {
+		data := make([]byte, 4096)
+		n, _ := args.Get(3).(io.Reader).Read(data)
+		uploaded = data[:n]
+	}).Return(nil)
+	mockS3.On("Delete", mock.Anything, "test-bucket", "pointers/myapp/production.pending.json").Return(nil)
+
+	// Mock audit log upload
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "audit/") && strings.Contains(key, "promotion")
+	}), mock.Anything).Return(nil)
+
+	promoter := NewImagePromoter(mockS3, "test-bucket")
+	promoter.SetActor("bob")
+
+	result, err := promoter.Approve(context.Background(), "myapp", "production")
+
+	assert.NoError(t, err)
+	assert.False(t, result.Skipped)
+
+	pointer, err := PointerMetadataFromJSON(uploaded)
+	assert.NoError(t, err)
+	assert.False(t, pointer.RequiresApproval)
+	assert.Equal(t, "alice", pointer.PromotedBy)
+	assert.Equal(t, "bob", pointer.ApprovedBy)
+
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePromoter_Approve_RejectsSelfApproval(t *testing.T) {
+	mockS3 := new(MockS3Client)
+
+	pending := &PointerMetadata{
+		TargetType:       TargetTypeImage,
+		TargetPath:       "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz",
+		PromotedBy:       "alice",
+		GitHash:          "f7a5a27",
+		GitTime:          "20250721-2118",
+		SourceImage:      "myapp:20250721-2118-f7a5a27",
+		RequiresApproval: true,
+	}
+	pendingJSON, _ := pending.ToJSON()
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.pending.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/production.pending.json").Return(pendingJSON, nil)
+
+	promoter := NewImagePromoter(mockS3, "test-bucket")
+	promoter.SetActor("alice")
+
+	_, err := promoter.Approve(context.Background(), "myapp", "production")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "self-approval rejected")
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePromoter_PromoteFromEnvironment_Success(t *testing.T) {
+	mockS3 := new(MockS3Client)
+
+	// Staging points at a tag, which resolves to a concrete image
+	stagingPointer := &PointerMetadata{
+		TargetType:  TargetTypeTag,
+		TargetPath:  "tags/myapp/v1.2.0.json",
+		GitHash:     "abc1234",
+		GitTime:     "20250721-1430",
+		SourceImage: "myapp:20250721-1430-abc1234",
+		SourceTag:   "v1.2.0",
+	}
+	stagingJSON, _ := stagingPointer.ToJSON()
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/staging.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/staging.json").Return(stagingJSON, nil)
+
+	tagPointer := &PointerMetadata{
+		TargetType:  TargetTypeImage,
+		TargetPath:  "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz",
+		GitHash:     "abc1234",
+		GitTime:     "20250721-1430",
+		SourceImage: "myapp:20250721-1430-abc1234",
+	}
+	tagJSON, _ := tagPointer.ToJSON()
+	mockS3.On("Download", mock.Anything, "test-bucket", "tags/myapp/v1.2.0.json").Return(tagJSON, nil)
+
+	// No existing production pointer
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(false, nil)
+
+	// Production gets an image pointer to the resolved concrete image
+	var uploaded []byte
+	mockS3.On("Upload", mock.Anything, "test-bucket", "pointers/myapp/production.json", mock.Anything).Run(func(args mock.Arguments) {
+		data := make([]byte, 4096)
+		n, _ := args.Get(3).(io.Reader).Read(data)
+		uploaded = data[:n]
+	}).Return(nil)
+
+	// Mock audit log upload
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "audit/") && strings.Contains(key, "promotion")
+	}), mock.Anything).Return(nil)
+
+	promoter := NewImagePromoter(mockS3, "test-bucket")
+
+	result, err := promoter.PromoteFromEnvironment(context.Background(), "myapp", "staging", "production")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "staging", result.Source)
+	assert.Equal(t, "environment", result.SourceType)
+	assert.False(t, result.Skipped)
+
+	newPointer, err := PointerMetadataFromJSON(uploaded)
+	assert.NoError(t, err)
+	assert.Equal(t, TargetTypeImage, newPointer.TargetType)
+	assert.Equal(t, "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz", newPointer.TargetPath)
+
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePromoter_PromoteFromEnvironment_AlreadyCurrent(t *testing.T) {
+	mockS3 := new(MockS3Client)
+
+	stagingPointer := &PointerMetadata{
+		TargetType:  TargetTypeImage,
+		TargetPath:  "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz",
+		GitHash:     "abc1234",
+		GitTime:     "20250721-1430",
+		SourceImage: "myapp:20250721-1430-abc1234",
+	}
+	stagingJSON, _ := stagingPointer.ToJSON()
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/staging.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/staging.json").Return(stagingJSON, nil)
+
+	// Production already points at the same concrete image
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(stagingJSON, nil)
+
+	promoter := NewImagePromoter(mockS3, "test-bucket")
+
+	result, err := promoter.PromoteFromEnvironment(context.Background(), "myapp", "staging", "production")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Skipped)
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePromoter_Promote_SkipIdentical_AuditsNoOp(t *testing.T) {
+	mockS3 := new(MockS3Client)
+
+	// Mock image exists check
+	mockS3.On("Exists", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasSuffix(key, ".tar.gz") && strings.HasPrefix(key, "images/")
+	})).Return(true, nil)
+
+	// Production already points at the image being promoted
+	existingPointer := &PointerMetadata{
+		TargetType:  TargetTypeImage,
+		TargetPath:  "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz",
+		GitHash:     "abc1234",
+		GitTime:     "20250721-1430",
+		SourceImage: "myapp:20250721-1430-abc1234",
+	}
+	existingJSON, _ := existingPointer.ToJSON()
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(true, nil)
+	mockS3.On("Download", mock.Anything, "test-bucket", "pointers/myapp/production.json").Return(existingJSON, nil)
+
+	// With --no-skip-identical the skip still writes an audit event marked no_op
+	var auditBody string
+	mockS3.On("Upload", mock.Anything, "test-bucket", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "audit/") && strings.Contains(key, "promotion")
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		data, _ := io.ReadAll(args.Get(3).(io.Reader))
+		auditBody = string(data)
+	}).Return(nil)
+
+	promoter := NewImagePromoter(mockS3, "test-bucket")
+	promoter.AuditSkips()
+
+	result, err := promoter.Promote(context.Background(), "myapp:20250721-1430-abc1234", "production")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Skipped)
+	assert.Contains(t, auditBody, `"no_op": true`)
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePromoter_PromoteFromEnvironment_SourceNotFound(t *testing.T) {
+	mockS3 := new(MockS3Client)
+
+	mockS3.On("Exists", mock.Anything, "test-bucket", "pointers/myapp/staging.json").Return(false, nil)
+
+	promoter := NewImagePromoter(mockS3, "test-bucket")
+
+	_, err := promoter.PromoteFromEnvironment(context.Background(), "myapp", "staging", "production")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "environment pointer not found")
+	mockS3.AssertExpectations(t)
+}
+
+func TestImagePromoter_PromoteFromEnvironment_SameEnvironment(t *testing.T) {
+	mockS3 := new(MockS3Client)
+	promoter := NewImagePromoter(mockS3, "test-bucket")
+
+	_, err := promoter.PromoteFromEnvironment(context.Background(), "myapp", "staging", "staging")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "source and destination environments are the same")
+}
+
+func TestImageTagger_PruneDanglingTags(t *testing.T) {
+	ctx := context.Background()
+	mockS3 := newMockS3ClientForList()
+
+	mockS3.files["tags/myapp/v2.0.0.json"] = []byte(`{
+		"target_type": "image",
+		"target_path": "images/myapp/202506/myapp-20250615-0930-def5678.tar.gz",
+		"source_image": "myapp:20250615-0930-def5678"
+	}`)
+
+	tagger := NewImageTagger(mockS3, "test-bucket")
+
+	tags := []TagInfo{
+		{AppName: "myapp", Version: "v1.0.0", TargetImage: "myapp:20250721-2118-f7a5a27", S3Path: "tags/myapp/v1.0.0.json", Dangling: false},
+		{AppName: "myapp", Version: "v2.0.0", TargetImage: "myapp:20250615-0930-def5678", S3Path: "tags/myapp/v2.0.0.json", Dangling: true},
+	}
+
+	deleted, err := tagger.PruneDanglingTags(ctx, "myapp", tags)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v2.0.0"}, deleted)
+
+	// The dangling tag pointer is gone and an audit event was written
+	_, exists := mockS3.files["tags/myapp/v2.0.0.json"]
+	assert.False(t, exists)
+
+	auditCount := 0
+	for key := range mockS3.files {
+		if strings.HasPrefix(key, "audit/myapp/") && strings.Contains(key, "tag-delete") {
+			auditCount++
+		}
+	}
+	assert.Equal(t, 1, auditCount)
+}
+
+func TestWaitForVisibility(t *testing.T) {
+	ctx := context.Background()
+	mockS3 := newMockS3ClientForList()
+	mockS3.files["pointers/myapp/production.json"] = []byte("pointer")
+
+	promoter := NewImagePromoter(mockS3, "test-bucket")
+
+	// Disabled by default: a missing key is not polled for
+	assert.NoError(t, promoter.waitForVisibility(ctx, "pointers/myapp/staging.json", []byte("pointer")))
+
+	// Enabled: an immediately visible write passes without retries
+	promoter.SetConsistencyWait(time.Second)
+	assert.NoError(t, promoter.waitForVisibility(ctx, "pointers/myapp/production.json", []byte("pointer")))
+}
+
+func TestWaitForVisibility_Timeout(t *testing.T) {
+	oldInterval := consistencyPollInterval
+	consistencyPollInterval = time.Millisecond
+	defer func() { consistencyPollInterval = oldInterval }()
+
+	ctx := context.Background()
+	mockS3 := newMockS3ClientForList()
+	mockS3.files["pointers/myapp/production.json"] = []byte("stale")
+
+	promoter := NewImagePromoter(mockS3, "test-bucket")
+	promoter.SetConsistencyWait(20 * time.Millisecond)
+
+	err := promoter.waitForVisibility(ctx, "pointers/myapp/production.json", []byte("fresh"))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not visible")
+}
+
+func TestTagFromEnvironment(t *testing.T) {
+	ctx := context.Background()
+	mockS3 := newMockS3ClientForList()
+	imageKey := "images/myapp/202507/myapp-20250721-2118-f7a5a27.tar.gz"
+	mockS3.files[imageKey] = []byte("image")
+	mockS3.files["pointers/myapp/production.json"] = []byte(`{
+		"target_type": "image",
+		"target_path": "` + imageKey + `",
+		"source_image": "myapp:20250721-2118-f7a5a27"
+	}`)
+
+	tagger := NewImageTagger(mockS3, "test-bucket")
+
+	result, err := tagger.TagFromEnvironment(ctx, "myapp", "production", "v1.3.0")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:20250721-2118-f7a5a27", result.ImageRef)
+	assert.Equal(t, "v1.3.0", result.Version)
+
+	_, exists := mockS3.files["tags/myapp/v1.3.0.json"]
+	assert.True(t, exists)
+}
+
+func TestTagFromEnvironment_MissingPointer(t *testing.T) {
+	ctx := context.Background()
+	mockS3 := newMockS3ClientForList()
+
+	tagger := NewImageTagger(mockS3, "test-bucket")
+
+	_, err := tagger.TagFromEnvironment(ctx, "myapp", "production", "v1.3.0")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to resolve current image for myapp/production")
+}
@@ -2,11 +2,58 @@ package internal
 
 import (
 	"archive/tar"
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"time"
 )
 
+// ParseTarFormat maps a --tar-format flag value to an archive/tar format.
+// An empty string means no override: each entry keeps the format it was read
+// with, so USTAR archives stay USTAR and PAX entries (long names, large files)
+// stay PAX.
+func ParseTarFormat(name string) (tar.Format, error) {
+	switch name {
+	case "":
+		return tar.FormatUnknown, nil
+	case "ustar":
+		return tar.FormatUSTAR, nil
+	case "pax":
+		return tar.FormatPAX, nil
+	case "gnu":
+		return tar.FormatGNU, nil
+	default:
+		return tar.FormatUnknown, fmt.Errorf("unknown tar format %q (expected ustar, pax or gnu)", name)
+	}
+}
+
+// normalizeTarHeader rewrites a header's timestamps to fixedTime and applies
+// the format override, if any. PAX entries duplicate their timestamps as
+// extended header records; those are dropped too, otherwise the writer would
+// resurrect the cleared times from them.
+func normalizeTarHeader(header *tar.Header, fixedTime time.Time, format tar.Format) {
+	// Normalize ModTime to the fixed time
+	// Note: AccessTime and ChangeTime are not supported in USTAR format
+	// (which Docker uses), so we clear them to avoid encoding errors
+	header.ModTime = fixedTime
+	header.AccessTime = time.Time{}
+	header.ChangeTime = time.Time{}
+	delete(header.PAXRecords, "atime")
+	delete(header.PAXRecords, "ctime")
+	delete(header.PAXRecords, "mtime")
+
+	if format != tar.FormatUnknown {
+		// Reading a PAX entry leaves its extended records on the header; the
+		// parsed fields already carry their values, and only PAX can encode
+		// them, so drop the records when forcing another format
+		if format != tar.FormatPAX {
+			header.PAXRecords = nil
+		}
+		header.Format = format
+	}
+}
+
 // NormalizeTar reads a tar stream and rewrites all timestamps to fixedTime.
 // This makes Docker image exports deterministic by removing timestamp variations
 // that occur on each 'docker save' execution.
@@ -17,8 +64,19 @@ import (
 // - Clears AccessTime and ChangeTime (not supported in USTAR format)
 // - Writes normalized headers and content to output
 //
+// Entries keep the format they were read with, so archives using PAX extended
+// headers (long names, large files) round-trip without corruption.
+//
 // The resulting tar is fully compatible with Docker and can be loaded with 'docker load'.
 func NormalizeTar(input io.Reader, output io.Writer, fixedTime time.Time) error {
+	return NormalizeTarWithFormat(input, output, fixedTime, tar.FormatUnknown)
+}
+
+// NormalizeTarWithFormat is NormalizeTar with a header format override. With
+// tar.FormatUnknown every entry keeps its original format; otherwise each
+// header is forced to the given format, and entries that cannot be encoded in
+// it (e.g. a long filename in USTAR) fail the rewrite.
+func NormalizeTarWithFormat(input io.Reader, output io.Writer, fixedTime time.Time, format tar.Format) error {
 	tarReader := tar.NewReader(input)
 	tarWriter := tar.NewWriter(output)
 	defer tarWriter.Close()
@@ -32,12 +90,7 @@ func NormalizeTar(input io.Reader, output io.Writer, fixedTime time.Time) error
 			return fmt.Errorf("error reading tar header: %w", err)
 		}
 
-		// Normalize ModTime to the fixed time
-		// Note: AccessTime and ChangeTime are not supported in USTAR format
-		// (which Docker uses), so we clear them to avoid encoding errors
-		header.ModTime = fixedTime
-		header.AccessTime = time.Time{}
-		header.ChangeTime = time.Time{}
+		normalizeTarHeader(header, fixedTime, format)
 
 		// Write normalized header
 		if err := tarWriter.WriteHeader(header); err != nil {
@@ -46,6 +99,21 @@ func NormalizeTar(input io.Reader, output io.Writer, fixedTime time.Time) error
 
 		// Copy file content if it's a regular file
 		if header.Typeflag == tar.TypeReg {
+			// The save manifest is tiny - buffer it so multi-image archives
+			// can be rejected before anything is stored
+			if isSaveManifest(header.Name) {
+				content, err := io.ReadAll(tarReader)
+				if err != nil {
+					return fmt.Errorf("error reading file content: %w", err)
+				}
+				if err := validateSingleImageManifest(header.Name, content); err != nil {
+					return err
+				}
+				if _, err := tarWriter.Write(content); err != nil {
+					return fmt.Errorf("error writing file content: %w", err)
+				}
+				continue
+			}
 			if _, err := io.Copy(tarWriter, tarReader); err != nil {
 				return fmt.Errorf("error copying file content: %w", err)
 			}
@@ -55,6 +123,109 @@ func NormalizeTar(input io.Reader, output io.Writer, fixedTime time.Time) error
 	return nil
 }
 
+// isSaveManifest reports whether a tar entry describes the images contained
+// in a docker save archive (docker manifest or OCI layout index).
+func isSaveManifest(name string) bool {
+	return name == "manifest.json" || name == "index.json"
+}
+
+// validateSingleImageManifest rejects save archives that contain more than one
+// image. A ref resolving to a manifest list exports multiple images in one
+// tar, which makes the stored checksum and later import semantics ambiguous.
+func validateSingleImageManifest(name string, content []byte) error {
+	count := 0
+	switch name {
+	case "manifest.json":
+		var entries []json.RawMessage
+		if err := json.Unmarshal(content, &entries); err != nil {
+			// Not the docker-save layout; leave it for Docker to interpret
+			return nil
+		}
+		count = len(entries)
+	case "index.json":
+		var index struct {
+			Manifests []json.RawMessage `json:"manifests"`
+		}
+		if err := json.Unmarshal(content, &index); err != nil {
+			return nil
+		}
+		count = len(index.Manifests)
+	}
+
+	if count > 1 {
+		return fmt.Errorf("exported tar contains %d images - ambiguous multi-arch image; specify --platform or push a single-image reference", count)
+	}
+	return nil
+}
+
+// NormalizeTarSorted is NormalizeTar with deterministic entry ordering: it
+// buffers the whole archive, sorts entries by name, then writes them out.
+// Docker doesn't guarantee a stable entry order across exports, so sorting
+// removes that source of nondeterminism - at the cost of holding the entire
+// uncompressed archive in memory, which is why streaming stays the default.
+func NormalizeTarSorted(input io.Reader, output io.Writer, fixedTime time.Time) error {
+	return NormalizeTarSortedWithFormat(input, output, fixedTime, tar.FormatUnknown)
+}
+
+// NormalizeTarSortedWithFormat is NormalizeTarSorted with a header format
+// override, with the same semantics as NormalizeTarWithFormat.
+func NormalizeTarSortedWithFormat(input io.Reader, output io.Writer, fixedTime time.Time, format tar.Format) error {
+	type tarEntry struct {
+		header *tar.Header
+		body   []byte
+	}
+
+	tarReader := tar.NewReader(input)
+	var entries []tarEntry
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading tar header: %w", err)
+		}
+
+		normalizeTarHeader(header, fixedTime, format)
+
+		var body []byte
+		if header.Typeflag == tar.TypeReg {
+			body, err = io.ReadAll(tarReader)
+			if err != nil {
+				return fmt.Errorf("error reading file content: %w", err)
+			}
+			if isSaveManifest(header.Name) {
+				if err := validateSingleImageManifest(header.Name, body); err != nil {
+					return err
+				}
+			}
+		}
+
+		entries = append(entries, tarEntry{header: header, body: body})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].header.Name < entries[j].header.Name
+	})
+
+	tarWriter := tar.NewWriter(output)
+	defer tarWriter.Close()
+
+	for _, entry := range entries {
+		if err := tarWriter.WriteHeader(entry.header); err != nil {
+			return fmt.Errorf("error writing tar header: %w", err)
+		}
+		if entry.header.Typeflag == tar.TypeReg {
+			if _, err := tarWriter.Write(entry.body); err != nil {
+				return fmt.Errorf("error writing file content: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // ParseGitTime converts a git timestamp string (format: YYYYMMDD-HHMM) to time.Time
 func ParseGitTime(gitTime string) (time.Time, error) {
 	// Format: 20251230-1718
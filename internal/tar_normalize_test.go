@@ -242,6 +242,267 @@ func TestNormalizeTar_InvalidTar(t *testing.T) {
 	}
 }
 
+func TestNormalizeTar_RejectsMultiImageManifest(t *testing.T) {
+	fixedTime := time.Date(2025, 12, 30, 17, 18, 0, 0, time.UTC)
+
+	manifest := `[{"RepoTags":["myapp:amd64"]},{"RepoTags":["myapp:arm64"]}]`
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "manifest.json", Size: int64(len(manifest)), Mode: 0644})
+	tw.Write([]byte(manifest))
+	tw.Close()
+
+	output := &bytes.Buffer{}
+	err := NormalizeTar(buf, output, fixedTime)
+
+	if err == nil {
+		t.Fatal("Expected error for multi-image manifest, got nil")
+	}
+	if !strings.Contains(err.Error(), "ambiguous multi-arch image") {
+		t.Errorf("Expected ambiguous multi-arch error, got: %v", err)
+	}
+}
+
+func TestNormalizeTar_AcceptsSingleImageManifest(t *testing.T) {
+	fixedTime := time.Date(2025, 12, 30, 17, 18, 0, 0, time.UTC)
+
+	manifest := `[{"RepoTags":["myapp:20250721-2118-f7a5a27"]}]`
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "manifest.json", Size: int64(len(manifest)), Mode: 0644})
+	tw.Write([]byte(manifest))
+	tw.Close()
+
+	output := &bytes.Buffer{}
+	if err := NormalizeTar(buf, output, fixedTime); err != nil {
+		t.Fatalf("Expected single-image manifest to pass, got: %v", err)
+	}
+
+	// Manifest content must survive the rewrite unchanged
+	tr := tar.NewReader(output)
+	header, err := tr.Next()
+	if err != nil {
+		t.Fatalf("Failed to read normalized tar: %v", err)
+	}
+	if header.Name != "manifest.json" {
+		t.Errorf("Expected manifest.json entry, got %s", header.Name)
+	}
+	content, _ := io.ReadAll(tr)
+	if string(content) != manifest {
+		t.Errorf("Manifest content changed: got %q, want %q", content, manifest)
+	}
+}
+
+func TestNormalizeTarSorted_RejectsMultiImageIndex(t *testing.T) {
+	fixedTime := time.Date(2025, 12, 30, 17, 18, 0, 0, time.UTC)
+
+	index := `{"schemaVersion":2,"manifests":[{"digest":"sha256:aaa"},{"digest":"sha256:bbb"}]}`
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{Name: "index.json", Size: int64(len(index)), Mode: 0644})
+	tw.Write([]byte(index))
+	tw.Close()
+
+	output := &bytes.Buffer{}
+	err := NormalizeTarSorted(buf, output, fixedTime)
+
+	if err == nil {
+		t.Fatal("Expected error for multi-image index, got nil")
+	}
+	if !strings.Contains(err.Error(), "ambiguous multi-arch image") {
+		t.Errorf("Expected ambiguous multi-arch error, got: %v", err)
+	}
+}
+
+func TestNormalizeTar_PAXLongNameRoundTrip(t *testing.T) {
+	fixedTime := time.Date(2025, 12, 30, 17, 18, 0, 0, time.UTC)
+
+	// USTAR caps names at 100 characters; this entry forces PAX extended headers
+	longName := strings.Repeat("deeply/nested/", 10) + "layer.tar"
+	if len(longName) <= 100 {
+		t.Fatalf("Test name too short to require PAX: %d chars", len(longName))
+	}
+
+	makeTar := func() *bytes.Buffer {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		tw.WriteHeader(&tar.Header{
+			Name:       longName,
+			Size:       5,
+			Mode:       0644,
+			ModTime:    time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			AccessTime: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+			ChangeTime: time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC),
+			Format:     tar.FormatPAX,
+		})
+		tw.Write([]byte("hello"))
+		tw.Close()
+		return buf
+	}
+
+	normalizers := map[string]func(io.Reader, io.Writer, time.Time) error{
+		"NormalizeTar":       NormalizeTar,
+		"NormalizeTarSorted": NormalizeTarSorted,
+	}
+
+	for name, normalize := range normalizers {
+		t.Run(name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			if err := normalize(makeTar(), output, fixedTime); err != nil {
+				t.Fatalf("%s failed: %v", name, err)
+			}
+
+			tr := tar.NewReader(output)
+			header, err := tr.Next()
+			if err != nil {
+				t.Fatalf("Failed to read normalized tar: %v", err)
+			}
+
+			if header.Name != longName {
+				t.Errorf("Long name corrupted: got %q, want %q", header.Name, longName)
+			}
+			if !header.ModTime.Equal(fixedTime) {
+				t.Errorf("ModTime not normalized: got %v, want %v", header.ModTime, fixedTime)
+			}
+			if !header.AccessTime.IsZero() || !header.ChangeTime.IsZero() {
+				t.Errorf("PAX atime/ctime not cleared: atime=%v ctime=%v", header.AccessTime, header.ChangeTime)
+			}
+
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatalf("Failed to read content: %v", err)
+			}
+			if string(content) != "hello" {
+				t.Errorf("Content mismatch: got %q, want %q", content, "hello")
+			}
+		})
+	}
+}
+
+func TestNormalizeTar_PAXDeterministic(t *testing.T) {
+	fixedTime := time.Date(2025, 12, 30, 17, 18, 0, 0, time.UTC)
+	longName := strings.Repeat("deeply/nested/", 10) + "layer.tar"
+
+	// Varying access times only survive as PAX records; normalizing must drop
+	// them or the outputs differ
+	outputs := make([][]byte, 2)
+	for i := range outputs {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		tw.WriteHeader(&tar.Header{
+			Name:       longName,
+			Size:       5,
+			Mode:       0644,
+			ModTime:    time.Now(),
+			AccessTime: time.Now().Add(time.Duration(i) * time.Hour),
+			Format:     tar.FormatPAX,
+		})
+		tw.Write([]byte("hello"))
+		tw.Close()
+
+		output := &bytes.Buffer{}
+		if err := NormalizeTar(buf, output, fixedTime); err != nil {
+			t.Fatalf("NormalizeTar failed on run %d: %v", i, err)
+		}
+		outputs[i] = output.Bytes()
+	}
+
+	if !bytes.Equal(outputs[0], outputs[1]) {
+		t.Error("Outputs differ for PAX archives with varying timestamps")
+	}
+}
+
+func TestNormalizeTarWithFormat_USTARRejectsLongNames(t *testing.T) {
+	fixedTime := time.Date(2025, 12, 30, 17, 18, 0, 0, time.UTC)
+	// A single path component over 100 characters cannot be split across
+	// USTAR's prefix and name fields
+	longName := strings.Repeat("x", 150)
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{
+		Name:    longName,
+		Size:    5,
+		Mode:    0644,
+		ModTime: time.Now(),
+		Format:  tar.FormatPAX,
+	})
+	tw.Write([]byte("hello"))
+	tw.Close()
+
+	output := &bytes.Buffer{}
+	err := NormalizeTarWithFormat(buf, output, fixedTime, tar.FormatUSTAR)
+	if err == nil {
+		t.Fatal("Expected error forcing USTAR on a long filename, got nil")
+	}
+}
+
+func TestNormalizeTarWithFormat_GNULongNameRoundTrip(t *testing.T) {
+	fixedTime := time.Date(2025, 12, 30, 17, 18, 0, 0, time.UTC)
+	longName := strings.Repeat("deeply/nested/", 10) + "layer.tar"
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	tw.WriteHeader(&tar.Header{
+		Name:    longName,
+		Size:    5,
+		Mode:    0644,
+		ModTime: time.Now(),
+		Format:  tar.FormatPAX,
+	})
+	tw.Write([]byte("hello"))
+	tw.Close()
+
+	output := &bytes.Buffer{}
+	if err := NormalizeTarWithFormat(buf, output, fixedTime, tar.FormatGNU); err != nil {
+		t.Fatalf("NormalizeTarWithFormat failed: %v", err)
+	}
+
+	tr := tar.NewReader(output)
+	header, err := tr.Next()
+	if err != nil {
+		t.Fatalf("Failed to read normalized tar: %v", err)
+	}
+	if header.Name != longName {
+		t.Errorf("Long name corrupted: got %q, want %q", header.Name, longName)
+	}
+	if header.Format&tar.FormatGNU == 0 {
+		t.Errorf("Expected GNU format entry, got %v", header.Format)
+	}
+}
+
+func TestParseTarFormat(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      tar.Format
+		wantError bool
+	}{
+		{input: "", want: tar.FormatUnknown},
+		{input: "ustar", want: tar.FormatUSTAR},
+		{input: "pax", want: tar.FormatPAX},
+		{input: "gnu", want: tar.FormatGNU},
+		{input: "USTAR", wantError: true},
+		{input: "zip", wantError: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTarFormat(tt.input)
+		if tt.wantError {
+			if err == nil {
+				t.Errorf("ParseTarFormat(%q): expected error, got nil", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTarFormat(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTarFormat(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
 func TestParseGitTime(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -307,3 +568,63 @@ func TestParseGitTime(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeTarSorted_OrderIndependent(t *testing.T) {
+	fixedTime := time.Date(2025, 12, 30, 17, 18, 0, 0, time.UTC)
+
+	makeTar := func(names ...string) *bytes.Buffer {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		for _, name := range names {
+			content := "content of " + name
+			tw.WriteHeader(&tar.Header{
+				Name:    name,
+				Size:    int64(len(content)),
+				Mode:    0644,
+				ModTime: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			})
+			tw.Write([]byte(content))
+		}
+		tw.Close()
+		return buf
+	}
+
+	// Same entries, different order - Docker doesn't guarantee export order
+	first := makeTar("layer1/layer.tar", "manifest.json", "layer2/layer.tar")
+	second := makeTar("manifest.json", "layer2/layer.tar", "layer1/layer.tar")
+
+	var firstOut, secondOut bytes.Buffer
+	if err := NormalizeTarSorted(first, &firstOut, fixedTime); err != nil {
+		t.Fatalf("NormalizeTarSorted failed: %v", err)
+	}
+	if err := NormalizeTarSorted(second, &secondOut, fixedTime); err != nil {
+		t.Fatalf("NormalizeTarSorted failed: %v", err)
+	}
+
+	if !bytes.Equal(firstOut.Bytes(), secondOut.Bytes()) {
+		t.Errorf("Sorted outputs differ for the same entries in different input order")
+	}
+
+	// Entries come out sorted by name
+	tr := tar.NewReader(&firstOut)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read output tar: %v", err)
+		}
+		names = append(names, header.Name)
+	}
+	expected := []string{"layer1/layer.tar", "layer2/layer.tar", "manifest.json"}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected %d entries, got %v", len(expected), names)
+	}
+	for i, name := range names {
+		if name != expected[i] {
+			t.Errorf("Expected entry[%d] to be %s, got %s", i, expected[i], name)
+		}
+	}
+}
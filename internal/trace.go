@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+)
+
+// traceID correlates one s3dock invocation across log lines and audit events
+// so a promotion can be matched to the CI pipeline run that triggered it.
+// main sets it once at startup from --trace-id or S3DOCK_TRACE_ID.
+var traceID string
+
+// SetTraceID installs the correlation ID for this invocation. An empty value
+// falls back to S3DOCK_TRACE_ID, then to a freshly generated random ID.
+func SetTraceID(id string) {
+	if id == "" {
+		id = os.Getenv("S3DOCK_TRACE_ID")
+	}
+	if id == "" {
+		id = generateTraceID()
+	}
+	traceID = id
+}
+
+// TraceID returns the correlation ID for this invocation, or "" when none
+// has been set (library and test use).
+func TraceID() string {
+	return traceID
+}
+
+func generateTraceID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		// Entropy failure is effectively impossible; a timestamp still
+		// yields a usable per-invocation ID
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
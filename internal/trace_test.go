@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTraceID_Explicit(t *testing.T) {
+	defer func() { traceID = "" }()
+
+	SetTraceID("ci-run-42")
+
+	assert.Equal(t, "ci-run-42", TraceID())
+}
+
+func TestSetTraceID_Env(t *testing.T) {
+	defer func() { traceID = "" }()
+	t.Setenv("S3DOCK_TRACE_ID", "env-trace-7")
+
+	SetTraceID("")
+
+	assert.Equal(t, "env-trace-7", TraceID())
+}
+
+func TestSetTraceID_Generated(t *testing.T) {
+	defer func() { traceID = "" }()
+
+	SetTraceID("")
+	first := TraceID()
+	SetTraceID("")
+	second := TraceID()
+
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second, "generated IDs should be unique per invocation")
+}
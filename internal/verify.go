@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// VerifyService checks stored images against their metadata checksums
+type VerifyService struct {
+	s3     S3Client
+	bucket string
+	list   *ListService
+	emit   func(interface{})
+}
+
+func NewVerifyService(s3 S3Client, bucket string) *VerifyService {
+	return &VerifyService{
+		s3:     s3,
+		bucket: bucket,
+		list:   NewListService(s3, bucket),
+	}
+}
+
+// SetEmitter installs a callback invoked with a VerifyItem for every image as
+// it is verified, enabling streaming output. Calls are serialized.
+func (v *VerifyService) SetEmitter(emit func(interface{})) {
+	v.emit = emit
+}
+
+// VerifyAll verifies every image in the bucket (or a single app when appName
+// is set) against its metadata checksum, streaming each object through the
+// hasher with a bounded worker pool. The scan keeps going past individual
+// failures so a scheduled job reports everything wrong in one pass.
+func (v *VerifyService) VerifyAll(ctx context.Context, appName string, concurrency int) (*VerifyAllResult, error) {
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var apps []string
+	if appName != "" {
+		apps = []string{appName}
+	} else {
+		var err error
+		apps, err = v.list.ListApps(ctx, false, "")
+		if err != nil {
+			LogError("Failed to list apps: %v", err)
+			return nil, fmt.Errorf("failed to list apps: %w", err)
+		}
+	}
+
+	var images []ImageInfo
+	for _, app := range apps {
+		appImages, err := v.list.ListImages(ctx, app, "")
+		if err != nil {
+			LogError("Failed to list images for %s: %v", app, err)
+			return nil, fmt.Errorf("failed to list images for %s: %w", app, err)
+		}
+		images = append(images, appImages...)
+	}
+
+	LogInfo("Verifying %d images with %d workers", len(images), concurrency)
+
+	result := &VerifyAllResult{
+		AppName: appName,
+		Checked: len(images),
+	}
+
+	jobs := make(chan ImageInfo)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for img := range jobs {
+				status, detail := v.verifyImage(ctx, img)
+
+				mu.Lock()
+				if v.emit != nil {
+					v.emit(VerifyItem{S3Path: img.S3Path, Status: status, Detail: detail})
+				}
+				switch status {
+				case verifyStatusOK:
+					result.OK++
+				case verifyStatusMissingMetadata:
+					result.MissingMetadata = append(result.MissingMetadata, img.S3Path)
+				case verifyStatusCorrupt:
+					result.Corrupt = append(result.Corrupt, VerifyFailure{
+						S3Path: img.S3Path,
+						Detail: detail,
+					})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, img := range images {
+		jobs <- img
+	}
+	close(jobs)
+	wg.Wait()
+
+	LogInfo("Verification complete: %d ok, %d corrupt, %d missing metadata",
+		result.OK, len(result.Corrupt), len(result.MissingMetadata))
+	return result, nil
+}
+
+const (
+	verifyStatusOK              = "ok"
+	verifyStatusCorrupt         = "corrupt"
+	verifyStatusMissingMetadata = "missing-metadata"
+)
+
+// verifyImage streams one image from S3 and compares its checksum against the
+// sibling metadata. Failures that prevent verification (unreadable metadata,
+// download errors) are reported as corrupt with the cause in the detail.
+func (v *VerifyService) verifyImage(ctx context.Context, img ImageInfo) (string, string) {
+	metadataKey := GenerateMetadataKey(img.S3Path)
+
+	exists, err := v.s3.Exists(ctx, v.bucket, metadataKey)
+	if err != nil {
+		return verifyStatusCorrupt, fmt.Sprintf("failed to check metadata existence: %v", err)
+	}
+	if !exists {
+		LogDebug("Missing metadata for %s", img.S3Path)
+		return verifyStatusMissingMetadata, ""
+	}
+
+	metadataData, err := v.s3.Download(ctx, v.bucket, metadataKey)
+	if err != nil {
+		return verifyStatusCorrupt, fmt.Sprintf("failed to download metadata: %v", err)
+	}
+
+	metadata, err := ImageMetadataFromJSON(metadataData)
+	if err != nil {
+		return verifyStatusCorrupt, fmt.Sprintf("failed to parse metadata: %v", err)
+	}
+
+	// Fast path: when the push recorded the object's ETag, a cheap HEAD
+	// comparison avoids re-downloading the image. A mismatch alone is not
+	// proof of corruption (a forced re-upload changes the ETag), so it falls
+	// through to the full checksum.
+	if metadata.ETag != "" {
+		if etagger, ok := v.s3.(ETagClient); ok {
+			if etag, err := etagger.HeadETag(ctx, v.bucket, img.S3Path); err == nil && etag == metadata.ETag {
+				LogDebug("Verified %s via ETag (%s)", img.S3Path, etag)
+				return verifyStatusOK, ""
+			}
+		}
+	}
+
+	reader, err := v.s3.DownloadStream(ctx, v.bucket, img.S3Path)
+	if err != nil {
+		return verifyStatusCorrupt, fmt.Sprintf("failed to download image: %v", err)
+	}
+	defer reader.Close()
+
+	hasher := md5.New()
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return verifyStatusCorrupt, fmt.Sprintf("failed to read image: %v", err)
+	}
+
+	actualChecksum := fmt.Sprintf("%x", hasher.Sum(nil))
+	if actualChecksum != metadata.Checksum {
+		LogError("Checksum mismatch for %s: expected %s, got %s", img.S3Path, metadata.Checksum, actualChecksum)
+		return verifyStatusCorrupt, fmt.Sprintf("checksum mismatch: expected %s, got %s", metadata.Checksum, actualChecksum)
+	}
+
+	LogDebug("Verified %s (%s)", img.S3Path, actualChecksum)
+	return verifyStatusOK, ""
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func addVerifiableImage(mock *mockS3ClientForList, s3Path string, content []byte) {
+	mock.files[s3Path] = content
+
+	metadata := &ImageMetadata{
+		Checksum: fmt.Sprintf("%x", md5.Sum(content)),
+		Size:     int64(len(content)),
+	}
+	metadataData, _ := metadata.ToJSON()
+	mock.files[GenerateMetadataKey(s3Path)] = metadataData
+}
+
+func TestVerifyAll_AllOK(t *testing.T) {
+	mock := newMockS3ClientForList()
+	addVerifiableImage(mock, "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz", []byte("image-one"))
+	addVerifiableImage(mock, "images/otherapp/202507/otherapp-20250722-0900-def5678.tar.gz", []byte("image-two"))
+
+	service := NewVerifyService(mock, "test-bucket")
+
+	result, err := service.VerifyAll(context.Background(), "", 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Checked)
+	assert.Equal(t, 2, result.OK)
+	assert.Empty(t, result.Corrupt)
+	assert.Empty(t, result.MissingMetadata)
+}
+
+func TestVerifyAll_DetectsCorruption(t *testing.T) {
+	mock := newMockS3ClientForList()
+	addVerifiableImage(mock, "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz", []byte("original"))
+
+	// Corrupt the stored object after metadata was written
+	mock.files["images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz"] = []byte("tampered")
+
+	service := NewVerifyService(mock, "test-bucket")
+
+	result, err := service.VerifyAll(context.Background(), "myapp", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Checked)
+	assert.Equal(t, 0, result.OK)
+	assert.Len(t, result.Corrupt, 1)
+	assert.Equal(t, "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz", result.Corrupt[0].S3Path)
+	assert.Contains(t, result.Corrupt[0].Detail, "checksum mismatch")
+}
+
+// etagMock augments the list mock with the ETagClient capability and counts
+// downloads so tests can see whether the ETag fast path avoided them
+type etagMock struct {
+	*mockS3ClientForList
+	etags     map[string]string
+	downloads int
+}
+
+func (m *etagMock) HeadETag(ctx context.Context, bucket, key string) (string, error) {
+	return m.etags[key], nil
+}
+
+func (m *etagMock) DownloadStream(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	m.downloads++
+	return m.mockS3ClientForList.DownloadStream(ctx, bucket, key)
+}
+
+func TestVerifyAll_ETagFastPath(t *testing.T) {
+	imageKey := "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz"
+	mock := &etagMock{
+		mockS3ClientForList: newMockS3ClientForList(),
+		etags:               map[string]string{imageKey: "etag-1"},
+	}
+	addVerifiableImage(mock.mockS3ClientForList, imageKey, []byte("image"))
+
+	// Stamp the recorded ETag into the stored metadata
+	metadata, _ := ImageMetadataFromJSON(mock.files[GenerateMetadataKey(imageKey)])
+	metadata.ETag = "etag-1"
+	metadataData, _ := metadata.ToJSON()
+	mock.files[GenerateMetadataKey(imageKey)] = metadataData
+
+	service := NewVerifyService(mock, "test-bucket")
+
+	result, err := service.VerifyAll(context.Background(), "myapp", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.OK)
+	assert.Equal(t, 0, mock.downloads, "matching ETag should skip the download")
+
+	// A changed ETag falls back to the full checksum, which still passes
+	mock.etags[imageKey] = "etag-2"
+	result, err = service.VerifyAll(context.Background(), "myapp", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.OK)
+	assert.Equal(t, 1, mock.downloads, "ETag mismatch should fall back to checksum")
+}
+
+func TestVerifyAll_MissingMetadata(t *testing.T) {
+	mock := newMockS3ClientForList()
+	mock.files["images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz"] = []byte("no-metadata")
+
+	service := NewVerifyService(mock, "test-bucket")
+
+	result, err := service.VerifyAll(context.Background(), "myapp", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Checked)
+	assert.Equal(t, 0, result.OK)
+	assert.Empty(t, result.Corrupt)
+	assert.Equal(t, []string{"images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz"}, result.MissingMetadata)
+}
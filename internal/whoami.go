@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// accessCheckKey is the throwaway object used by whoami --check-access to
+// probe write permission. It is deleted immediately after the check.
+const accessCheckKey = ".s3dock/access-check"
+
+// ResolveIdentity returns the identity s3dock will record on pointers and
+// audit events, plus where it came from: the --actor override when given,
+// otherwise the OS user, falling back to "unknown" when the OS user cannot
+// be determined (the same fallback the pointer writers use).
+func ResolveIdentity(actor string) (user, source string) {
+	if actor != "" {
+		return actor, "actor"
+	}
+	if u, err := getCurrentUser(); err == nil && u != "" {
+		return u, "os"
+	}
+	return "unknown", "fallback"
+}
+
+// CheckBucketAccess confirms the current credentials can read and write the
+// bucket: a prefix listing proves read access, then a test object is written
+// and deleted to prove write access (whoami --check-access).
+func CheckBucketAccess(ctx context.Context, s3 S3Client, bucket string) error {
+	LogDebug("Checking read access to bucket %s", bucket)
+	if _, err := s3.List(ctx, bucket, ".s3dock/"); err != nil {
+		return fmt.Errorf("read check failed for bucket %s: %w", bucket, err)
+	}
+
+	LogDebug("Checking write access to bucket %s", bucket)
+	if err := s3.Upload(ctx, bucket, accessCheckKey, strings.NewReader("s3dock access check")); err != nil {
+		return fmt.Errorf("write check failed for bucket %s: %w", bucket, err)
+	}
+	if err := s3.Delete(ctx, bucket, accessCheckKey); err != nil {
+		return fmt.Errorf("delete check failed for bucket %s: %w", bucket, err)
+	}
+
+	return nil
+}
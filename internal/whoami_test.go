@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveIdentity_ActorOverride(t *testing.T) {
+	user, source := ResolveIdentity("deploy-bot")
+
+	assert.Equal(t, "deploy-bot", user)
+	assert.Equal(t, "actor", source)
+}
+
+func TestResolveIdentity_OSUser(t *testing.T) {
+	user, source := ResolveIdentity("")
+
+	assert.NotEmpty(t, user)
+	assert.Contains(t, []string{"os", "fallback"}, source)
+}
+
+func TestCheckBucketAccess(t *testing.T) {
+	mock := newMockS3ClientForList()
+
+	err := CheckBucketAccess(context.Background(), mock, "test-bucket")
+
+	assert.NoError(t, err)
+	_, exists := mock.files[accessCheckKey]
+	assert.False(t, exists, "the probe object should be deleted after the check")
+}
+
+// readOnlyS3Mock simulates credentials that can list but not write
+type readOnlyS3Mock struct {
+	*mockS3ClientForList
+}
+
+func (m *readOnlyS3Mock) Upload(ctx context.Context, bucket, key string, data io.Reader) error {
+	return errors.New("AccessDenied")
+}
+
+func TestCheckBucketAccess_WriteDenied(t *testing.T) {
+	mock := &readOnlyS3Mock{mockS3ClientForList: newMockS3ClientForList()}
+
+	err := CheckBucketAccess(context.Background(), mock, "test-bucket")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "write check failed")
+}
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"archive/tar"
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"s3dock/internal"
 )
@@ -15,13 +21,39 @@ var (
 	date    = "unknown"
 )
 
+// exitCodeEnvNotFound is returned by current when the environment has no
+// pointer, so health checks can distinguish "not configured" from transport
+// failures (generic exit 1) without parsing stderr.
+const exitCodeEnvNotFound = 3
+
 type GlobalFlags struct {
-	Config   string
-	Profile  string
-	Bucket   string
-	LogLevel int
-	Help     bool
-	JSON     bool
+	Config    string
+	Profile   string
+	Bucket    string
+	LogLevel  int
+	Help      bool
+	JSON      bool
+	NDJSON    bool
+	VerboseS3 bool
+	Quiet     bool
+
+	RegionPerBucket bool
+
+	// LocalRegistry routes push, pull, and current through a Docker registry
+	// backend instead of S3
+	LocalRegistry string
+
+	// TraceID correlates this invocation's logs and audit events with an
+	// upstream system (e.g. a CI pipeline run)
+	TraceID string
+
+	// Backend selects the storage backend: "s3" (default) or "memory", an
+	// in-process store for trying s3dock without any cloud
+	Backend string
+
+	// DryRun short-circuits every S3 mutation in the client, logging the
+	// intended write instead of performing it
+	DryRun bool
 }
 
 func main() {
@@ -32,11 +64,37 @@ func main() {
 
 	globalFlags, remaining := parseGlobalFlags(os.Args[1:])
 
+	// Stamp audit events with this binary's identity
+	internal.SetAuditClientInfo("s3dock", fmt.Sprintf("%s (%s)", version, commit))
+
+	// Correlation ID for logs and audit events (--trace-id, S3DOCK_TRACE_ID,
+	// or a random ID)
+	internal.SetTraceID(globalFlags.TraceID)
+
+	switch globalFlags.Backend {
+	case "", "s3":
+	case "memory":
+		internal.UseMemoryBackend()
+	default:
+		internal.LogError("Unknown backend: %s (expected s3 or memory)", globalFlags.Backend)
+		os.Exit(1)
+	}
+
+	// Short-circuit all S3 mutations in the client (--dry-run)
+	if globalFlags.DryRun {
+		internal.SetDryRun(true)
+	}
+
 	// Set log level from global flags
 	if globalFlags.LogLevel > 0 {
 		internal.SetLogLevel(internal.LogLevel(globalFlags.LogLevel))
 	}
 
+	// Suppress advisory warnings (e.g. the missing-config notice)
+	if globalFlags.Quiet {
+		internal.SetQuiet(true)
+	}
+
 	// Set JSON output mode
 	if globalFlags.JSON {
 		internal.SetOutputFormat(internal.OutputFormatJSON)
@@ -44,12 +102,28 @@ func main() {
 		internal.SetLogLevel(0) // Suppress all logs
 	}
 
+	// Enable AWS SDK request/response logging for endpoint/auth debugging
+	if globalFlags.VerboseS3 {
+		os.Setenv("S3DOCK_VERBOSE_S3", "1")
+	}
+
+	// Resolve bucket regions via probe, cached in ~/.s3dock/regions.json
+	if globalFlags.RegionPerBucket {
+		os.Setenv("S3DOCK_REGION_PER_BUCKET", "1")
+	}
+
+	// Streaming output emits one JSON line per result element
+	if globalFlags.NDJSON {
+		internal.SetOutputFormat(internal.OutputFormatNDJSON)
+		internal.SetLogLevel(0) // Suppress all logs
+	}
+
 	if globalFlags.Help || len(remaining) == 0 {
 		printUsage()
 		return
 	}
 
-	command := remaining[0]
+	command := canonicalCommand(remaining[0])
 	commandArgs := remaining[1:]
 
 	switch command {
@@ -63,16 +137,30 @@ func main() {
 		handleTagCommand(globalFlags, commandArgs)
 	case "promote":
 		handlePromoteCommand(globalFlags, commandArgs)
+	case "apply-pending":
+		handleApplyPendingCommand(globalFlags, commandArgs)
+	case "approve":
+		handleApproveCommand(globalFlags, commandArgs)
 	case "pull":
 		handlePullCommand(globalFlags, commandArgs)
 	case "current":
 		handleCurrentCommand(globalFlags, commandArgs)
+	case "prune-local":
+		handlePruneLocalCommand(globalFlags, commandArgs)
+	case "whoami":
+		handleWhoamiCommand(globalFlags, commandArgs)
 	case "version", "--version", "-v":
 		handleVersionCommand(globalFlags, commandArgs)
 	case "list":
 		handleListCommand(globalFlags, commandArgs)
+	case "find":
+		handleFindCommand(globalFlags, commandArgs)
 	case "cleanup":
-		internal.LogInfo("Cleanup functionality not yet implemented")
+		handleCleanupCommand(globalFlags, commandArgs)
+	case "verify-all":
+		handleVerifyAllCommand(globalFlags, commandArgs)
+	case "audit":
+		handleAuditCommand(globalFlags, commandArgs)
 	case "deploy":
 		internal.LogInfo("Deploy functionality not yet implemented")
 	case "help", "--help", "-h":
@@ -84,27 +172,89 @@ func main() {
 	}
 }
 
+// writeTagFile records the resulting image reference for later CI steps
+// (build/push --tag-file). The json format additionally includes the S3 key
+// and checksum when the command produced them.
+func writeTagFile(path, format, imageRef, s3Key, checksum string) error {
+	var content []byte
+	switch format {
+	case "text":
+		content = []byte(imageRef + "\n")
+	case "json":
+		payload := map[string]string{"image_ref": imageRef}
+		if s3Key != "" {
+			payload["s3_key"] = s3Key
+		}
+		if checksum != "" {
+			payload["checksum"] = checksum
+		}
+		data, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		content = append(data, '\n')
+	default:
+		return fmt.Errorf("invalid tag file format %q (expected text or json)", format)
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// canonicalCommand maps command aliases to their canonical names
+func canonicalCommand(command string) string {
+	switch command {
+	case "ls":
+		return "list"
+	}
+	return command
+}
+
+// canonicalListSubcommand maps list subcommand aliases to their canonical names
+func canonicalListSubcommand(subcommand string) string {
+	switch subcommand {
+	case "img":
+		return "images"
+	case "env", "environments":
+		return "envs"
+	}
+	return subcommand
+}
+
 func printUsage() {
 	fmt.Println("Usage: s3dock [global-flags] <command> [command-flags]")
 	fmt.Println("")
 	fmt.Println("Global Flags:")
-	fmt.Println("  --config <path>   Explicit config file path")
+	fmt.Println("  --config <path>   Explicit config file path (or s3://bucket/key for a shared remote config)")
 	fmt.Println("  --profile <name>  Profile to use from config")
 	fmt.Println("  --bucket <name>   Override bucket name")
 	fmt.Println("  --log-level <n>   Log level (1=error, 2=info, 3=debug)")
 	fmt.Println("  --json            Output results as JSON for programmatic consumption")
+	fmt.Println("  --output <fmt>    Output format: text, json, or ndjson (one JSON line per element)")
+	fmt.Println("  --verbose-s3      Log AWS SDK requests/responses (noisy; may leak headers)")
+	fmt.Println("  --quiet           Suppress advisory warnings (e.g. the missing-config notice)")
+	fmt.Println("  --region-per-bucket  Probe and cache each bucket's region in ~/.s3dock/regions.json")
+	fmt.Println("  --local-registry <host>  Use a Docker registry backend instead of S3 (push, pull, and current only)")
+	fmt.Println("  --trace-id <id>   Correlation ID attached to logs and audit events (default: S3DOCK_TRACE_ID or random)")
+	fmt.Println("  --backend <name>  Storage backend: s3 (default) or memory (in-process dev store)")
+	fmt.Println("  --dry-run         Log intended S3 writes without performing them")
 	fmt.Println("")
 	fmt.Println("Commands:")
 	fmt.Println("  build <app-name>    Build Docker image with git-based tag")
 	fmt.Println("  push <image:tag>    Push Docker image to S3")
 	fmt.Println("  tag <image> <ver>   Create semantic version tag")
 	fmt.Println("  promote <src> <env> Promote image/tag to environment")
+	fmt.Println("  apply-pending <app> <env> Activate a promotion scheduled with promote --schedule")
+	fmt.Println("  approve <app> <env> Approve a promotion awaiting a second actor")
 	fmt.Println("  pull <app> <env>    Pull image from environment")
-	fmt.Println("  current <app> <env> Show current image for environment")
+	fmt.Println("  current <app> <env> Show current image for environment (--exit-code --expect <ref> for CI gating)")
+	fmt.Println("  prune-local         Remove local s3dock images no longer referenced by any environment")
 	fmt.Println("  list                List images, tags, environments, or apps")
+	fmt.Println("  find <app>          Find images by Docker image ID")
 	fmt.Println("  config              Config file management")
+	fmt.Println("  whoami              Show the identity recorded in audit logs (--check-access probes the bucket)")
 	fmt.Println("  version             Show version information")
-	fmt.Println("  cleanup           Cleanup functionality (not implemented)")
+	fmt.Println("  cleanup <app>       Delete old images from S3 (see --keep-promoted)")
+	fmt.Println("  verify-all          Verify every stored image against its metadata checksum")
+	fmt.Println("  audit summary <app> Aggregate audit events (counts, users, skip rate, promotions)")
 	fmt.Println("  deploy            Deploy functionality (not implemented)")
 	fmt.Println("")
 	fmt.Println("Examples:")
@@ -159,6 +309,44 @@ func parseGlobalFlags(args []string) (*GlobalFlags, []string) {
 			flags.Help = true
 		case "--json":
 			flags.JSON = true
+		case "--verbose-s3":
+			flags.VerboseS3 = true
+		case "--quiet":
+			flags.Quiet = true
+		case "--region-per-bucket":
+			flags.RegionPerBucket = true
+		case "--local-registry":
+			if i+1 < len(args) {
+				flags.LocalRegistry = args[i+1]
+				i++
+			}
+		case "--trace-id":
+			if i+1 < len(args) {
+				flags.TraceID = args[i+1]
+				i++
+			}
+		case "--backend":
+			if i+1 < len(args) {
+				flags.Backend = args[i+1]
+				i++
+			}
+		case "--dry-run":
+			flags.DryRun = true
+		case "--output":
+			if i+1 < len(args) {
+				switch args[i+1] {
+				case "json":
+					flags.JSON = true
+				case "ndjson":
+					flags.NDJSON = true
+				case "text":
+					// Default format
+				default:
+					fmt.Fprintf(os.Stderr, "Invalid output format: %s (must be text, json, or ndjson)\n", args[i+1])
+					os.Exit(1)
+				}
+				i++
+			}
 		default:
 			remaining = append(remaining, arg)
 		}
@@ -167,20 +355,170 @@ func parseGlobalFlags(args []string) (*GlobalFlags, []string) {
 	return flags, remaining
 }
 
+// registryBackendFromFlags builds the --local-registry backend shared by
+// push, pull, and current. The caller must Close the returned Docker client.
+func registryBackendFromFlags(globalFlags *GlobalFlags) (*internal.RegistryBackend, *internal.DockerClientImpl, error) {
+	dockerClient, err := internal.NewDockerClient()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	return internal.NewRegistryBackend(globalFlags.LocalRegistry, dockerClient), dockerClient, nil
+}
+
 func handlePushCommand(globalFlags *GlobalFlags, args []string) {
 	if len(args) == 0 {
-		fmt.Println("Usage: s3dock [global-flags] push <image:tag>")
+		fmt.Println("Usage: s3dock [global-flags] push <image:tag> [push-flags]")
 		fmt.Println("")
 		fmt.Println("Push a Docker image to S3 storage.")
 		fmt.Println("")
+		fmt.Println("Push Flags:")
+		fmt.Println("  --checksum-only   Compute checksum and would-be S3 key without uploading")
+		fmt.Println("  --force           Overwrite an existing image on checksum mismatch despite immutable_tags")
+		fmt.Println("  --sort-tar        Sort tar entries by name before compressing (buffers the archive in memory)")
+		fmt.Println("  --tar-format <fmt> Force a tar header format during normalization: ustar, pax or gnu (default: keep each entry's format)")
+		fmt.Println("  --path <directory> Git repository path (default: current directory, or enclosing repo root)")
+		fmt.Println("  --app-name <name> Override the app name derived from the image reference")
+		fmt.Println("  --keep-namespace  Keep the repository namespace in the app name (team/myapp -> team-myapp)")
+		fmt.Println("  --tag-file <path> Write the pushed image reference to a file for later CI steps")
+		fmt.Println("  --tag-file-format <fmt> Tag file format: text (default) or json (adds key and checksum)")
+		fmt.Println("  --meta <key=value> Attach an annotation to the image metadata (repeatable)")
+		fmt.Println("  --dockerfile-hash-label [path] Record a hash of the Dockerfile (default ./Dockerfile) in the metadata")
+		fmt.Println("  --no-audit        Skip audit logging for this push (e.g. test pushes)")
+		fmt.Println("  --compression-workers <n> Compress with n parallel gzip workers (default: 1, single-threaded)")
+		fmt.Println("  --verify-after-push       Re-download the uploaded image and confirm its checksum before declaring success")
+		fmt.Println("  --delete-on-verify-failure Delete the uploaded object when post-push verification fails (default: leave it for investigation)")
+		fmt.Println("")
 		fmt.Println("Global Flags:")
-		fmt.Println("  --config <path>   Explicit config file path")
+		fmt.Println("  --config <path>   Explicit config file path (or s3://bucket/key for a shared remote config)")
 		fmt.Println("  --profile <name>  Profile to use from config")
 		fmt.Println("  --bucket <name>   Override bucket name")
 		return
 	}
 
-	imageRef := args[0]
+	checksumOnly := false
+	force := false
+	sortTar := false
+	gitPath := ""
+	tagFile := ""
+	tagFileFormat := "text"
+	appNameOverride := ""
+	keepNamespace := false
+	dockerfilePath := ""
+	noAudit := false
+	compressionWorkers := 0
+	tarFormat := tar.FormatUnknown
+	verifyAfterPush := false
+	deleteOnVerifyFailure := false
+	var extraMeta map[string]string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--dockerfile-hash-label":
+			dockerfilePath = "Dockerfile"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				dockerfilePath = args[i+1]
+				i++
+			}
+		case "--meta":
+			if i+1 < len(args) {
+				key, value, ok := strings.Cut(args[i+1], "=")
+				if !ok || key == "" {
+					internal.OutputError("push", fmt.Errorf("invalid --meta value: %s (expected key=value)", args[i+1]))
+					os.Exit(1)
+				}
+				if extraMeta == nil {
+					extraMeta = map[string]string{}
+				}
+				extraMeta[key] = value
+				i++
+			}
+		case "--checksum-only":
+			checksumOnly = true
+		case "--force":
+			force = true
+		case "--verify-after-push":
+			verifyAfterPush = true
+		case "--delete-on-verify-failure":
+			deleteOnVerifyFailure = true
+		case "--sort-tar":
+			sortTar = true
+		case "--tar-format":
+			if i+1 < len(args) {
+				format, err := internal.ParseTarFormat(args[i+1])
+				if err != nil {
+					internal.OutputError("push", fmt.Errorf("invalid --tar-format value: %w", err))
+					os.Exit(1)
+				}
+				tarFormat = format
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				gitPath = args[i+1]
+				i++
+			}
+		case "--app-name":
+			if i+1 < len(args) {
+				appNameOverride = args[i+1]
+				i++
+			}
+		case "--keep-namespace":
+			keepNamespace = true
+		case "--tag-file":
+			if i+1 < len(args) {
+				tagFile = args[i+1]
+				i++
+			}
+		case "--tag-file-format":
+			if i+1 < len(args) {
+				tagFileFormat = args[i+1]
+				i++
+			}
+		case "--no-audit":
+			noAudit = true
+		case "--compression-workers":
+			if i+1 < len(args) {
+				workers := 0
+				if _, err := fmt.Sscanf(args[i+1], "%d", &workers); err != nil || workers < 1 {
+					internal.OutputError("push", fmt.Errorf("invalid --compression-workers value: %s (expected a positive number)", args[i+1]))
+					os.Exit(1)
+				}
+				compressionWorkers = workers
+				i++
+			}
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	if len(positional) == 0 {
+		internal.OutputError("push", fmt.Errorf("push requires an image reference"))
+		os.Exit(1)
+	}
+	imageRef := positional[0]
+
+	// --app-name wins over --keep-namespace; both win over the image-ref default
+	if appNameOverride == "" && keepNamespace {
+		appNameOverride = internal.ExtractAppNameWithNamespace(imageRef)
+	}
+
+	if globalFlags.LocalRegistry != "" {
+		backend, dockerClient, err := registryBackendFromFlags(globalFlags)
+		if err != nil {
+			internal.OutputError("push", err)
+			os.Exit(1)
+		}
+		defer dockerClient.Close()
+
+		if err := backend.PushImage(context.Background(), imageRef); err != nil {
+			internal.OutputError("push", err)
+			os.Exit(1)
+		}
+		if globalFlags.JSON {
+			internal.OutputResult("push", internal.PushResult{ImageRef: imageRef})
+		}
+		return
+	}
 
 	resolved, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
 	if err != nil {
@@ -188,19 +526,60 @@ func handlePushCommand(globalFlags *GlobalFlags, args []string) {
 		os.Exit(1)
 	}
 
-	result, err := pushImageWithConfig(imageRef, resolved, globalFlags)
+	if checksumOnly {
+		result, err := checksumImageWithConfig(imageRef, resolved, globalFlags, sortTar, tarFormat, gitPath, appNameOverride, compressionWorkers)
+		if err != nil {
+			internal.OutputError("push", err)
+			os.Exit(1)
+		}
+
+		// JSON output
+		if globalFlags.JSON {
+			internal.OutputResult("push", result)
+			return
+		}
+
+		// Text output
+		fmt.Printf("Checksum: %s\n", result.Checksum)
+		fmt.Printf("S3 key: %s\n", result.S3Key)
+		return
+	}
+
+	dockerfileHash := ""
+	if dockerfilePath != "" {
+		dockerfileHash, err = internal.HashDockerfile(dockerfilePath)
+		if err != nil {
+			internal.OutputError("push", err)
+			os.Exit(1)
+		}
+	}
+
+	result, err := pushImageWithConfig(imageRef, resolved, globalFlags, force, sortTar, tarFormat, gitPath, appNameOverride, extraMeta, dockerfileHash, noAudit, compressionWorkers, verifyAfterPush, deleteOnVerifyFailure)
 	if err != nil {
 		internal.OutputError("push", err)
 		os.Exit(1)
 	}
 
+	if tagFile != "" {
+		if err := writeTagFile(tagFile, tagFileFormat, result.ImageRef, result.S3Key, result.Checksum); err != nil {
+			internal.OutputError("push", fmt.Errorf("failed to write tag file: %w", err))
+			os.Exit(1)
+		}
+	}
+
 	// JSON output
 	if globalFlags.JSON {
 		internal.OutputResult("push", result)
 		return
 	}
 
-	// Text output is handled by logs in the pusher
+	// Text output is handled by logs in the pusher, but show the delta when a
+	// previous image was replaced
+	if result.Archived && result.PreviousChecksum != "" {
+		fmt.Printf("Replaced %s (%.1f MB) with %s (%.1f MB)\n",
+			result.PreviousChecksum, float64(result.PreviousSize)/(1024*1024),
+			result.Checksum, float64(result.Size)/(1024*1024))
+	}
 }
 
 func handleConfigCommand(globalFlags *GlobalFlags, args []string) {
@@ -208,7 +587,8 @@ func handleConfigCommand(globalFlags *GlobalFlags, args []string) {
 		fmt.Println("Usage: s3dock config <subcommand>")
 		fmt.Println("")
 		fmt.Println("Config Subcommands:")
-		fmt.Println("  show [--profile <name>]  Show current config or specific profile")
+		fmt.Println("  show [--profile <name>]  Show current config or specific profile (credentials masked; --show-secrets reveals)")
+		fmt.Println("  show --resolved          Show effective config after env/flag overrides, with sources")
 		fmt.Println("  list                     List all profiles")
 		fmt.Println("  init                     Create default config file")
 		return
@@ -229,7 +609,30 @@ func handleConfigCommand(globalFlags *GlobalFlags, args []string) {
 }
 
 func handleConfigShow(globalFlags *GlobalFlags, args []string) {
-	localFlags, _ := parseGlobalFlags(args)
+	resolvedView := false
+	showSecrets := false
+	var rest []string
+	for _, arg := range args {
+		if arg == "--resolved" {
+			resolvedView = true
+			continue
+		}
+		if arg == "--show-secrets" {
+			showSecrets = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+
+	// Credentials are masked by default so pasted output can't leak them
+	maskSecret := func(value string) string {
+		if showSecrets {
+			return value
+		}
+		return internal.MaskSecret(value)
+	}
+
+	localFlags, _ := parseGlobalFlags(rest)
 
 	configPath := globalFlags.Config
 	if localFlags.Config != "" {
@@ -241,6 +644,39 @@ func handleConfigShow(globalFlags *GlobalFlags, args []string) {
 		profileName = localFlags.Profile
 	}
 
+	if resolvedView {
+		resolved, sources, err := internal.ResolveConfigWithSources(configPath, profileName, globalFlags.Bucket)
+		if err != nil {
+			internal.OutputError("config show", err)
+			os.Exit(1)
+		}
+
+		// JSON output
+		if globalFlags.JSON {
+			result := internal.ConfigShowResolvedResult{
+				Profile:   profileName,
+				Bucket:    internal.ResolvedFieldJSON{Value: resolved.Bucket, Source: string(sources.Bucket)},
+				Region:    internal.ResolvedFieldJSON{Value: resolved.Region, Source: string(sources.Region)},
+				Endpoint:  internal.ResolvedFieldJSON{Value: resolved.Endpoint, Source: string(sources.Endpoint)},
+				AccessKey: internal.ResolvedFieldJSON{Value: maskSecret(resolved.AccessKey), Source: string(sources.AccessKey)},
+			}
+			internal.OutputResult("config show", result)
+			return
+		}
+
+		// Text output
+		fmt.Println("Resolved configuration:")
+		fmt.Printf("  Bucket: %s (from %s)\n", resolved.Bucket, sources.Bucket)
+		fmt.Printf("  Region: %s (from %s)\n", resolved.Region, sources.Region)
+		if resolved.Endpoint != "" {
+			fmt.Printf("  Endpoint: %s (from %s)\n", resolved.Endpoint, sources.Endpoint)
+		}
+		if resolved.AccessKey != "" {
+			fmt.Printf("  Access Key: %s (from %s)\n", maskSecret(resolved.AccessKey), sources.AccessKey)
+		}
+		return
+	}
+
 	config, err := internal.LoadConfig(configPath)
 	if err != nil {
 		internal.OutputError("config show", err)
@@ -261,7 +697,7 @@ func handleConfigShow(globalFlags *GlobalFlags, args []string) {
 				Bucket:    profile.Bucket,
 				Region:    profile.Region,
 				Endpoint:  profile.Endpoint,
-				AccessKey: profile.AccessKey,
+				AccessKey: maskSecret(profile.AccessKey),
 			}
 			internal.OutputResult("config show", result)
 			return
@@ -275,7 +711,7 @@ func handleConfigShow(globalFlags *GlobalFlags, args []string) {
 			fmt.Printf("  Endpoint: %s\n", profile.Endpoint)
 		}
 		if profile.AccessKey != "" {
-			fmt.Printf("  Access Key: %s\n", profile.AccessKey)
+			fmt.Printf("  Access Key: %s\n", maskSecret(profile.AccessKey))
 		}
 		return
 	}
@@ -289,7 +725,7 @@ func handleConfigShow(globalFlags *GlobalFlags, args []string) {
 			Bucket:    defaultProfile.Bucket,
 			Region:    defaultProfile.Region,
 			Endpoint:  defaultProfile.Endpoint,
-			AccessKey: defaultProfile.AccessKey,
+			AccessKey: maskSecret(defaultProfile.AccessKey),
 		}
 		internal.OutputResult("config show", result)
 		return
@@ -375,19 +811,58 @@ func handleConfigInit(globalFlags *GlobalFlags, args []string) {
 	internal.LogInfo("Created config file: %s", configPath)
 }
 
-func pushImageWithConfig(imageRef string, config *internal.ResolvedConfig, globalFlags *GlobalFlags) (*internal.PushResult, error) {
+func pushImageWithConfig(imageRef string, config *internal.ResolvedConfig, globalFlags *GlobalFlags, force, sortTar bool, tarFormat tar.Format, gitPath, appName string, extraMeta map[string]string, dockerfileHash string, noAudit bool, compressionWorkers int, verifyAfterPush, deleteOnVerifyFailure bool) (*internal.PushResult, error) {
 	ctx := context.Background()
 
-	os.Setenv("AWS_REGION", config.Region)
-	if config.Endpoint != "" {
-		os.Setenv("AWS_ENDPOINT_URL", config.Endpoint)
+	setupAWSEnv(config)
+
+	dockerClient, err := internal.NewDockerClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
-	if config.AccessKey != "" {
-		os.Setenv("AWS_ACCESS_KEY_ID", config.AccessKey)
+	defer dockerClient.Close()
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
 	}
-	if config.SecretKey != "" {
-		os.Setenv("AWS_SECRET_ACCESS_KEY", config.SecretKey)
+
+	gitClient := internal.NewGitClient()
+
+	pusher := internal.NewImagePusher(dockerClient, s3Client, gitClient, config.Bucket)
+	pusher.SetPathTemplate(config.PathTemplate)
+	pusher.SetGitPath(gitPath)
+	if appName != "" {
+		pusher.SetAppName(appName)
+	}
+	if len(extraMeta) > 0 {
+		pusher.SetExtraMetadata(extraMeta)
+	}
+	if dockerfileHash != "" {
+		pusher.SetDockerfileHash(dockerfileHash)
+	}
+	if noAudit {
+		pusher.DisableAudit()
 	}
+	if compressionWorkers > 0 {
+		pusher.SetCompressionWorkers(compressionWorkers)
+	}
+	if verifyAfterPush {
+		pusher.SetVerifyAfterPush(deleteOnVerifyFailure)
+	}
+
+	return pusher.PushWithOptions(ctx, imageRef, internal.PushOptions{
+		ImmutableTags: config.ImmutableTags,
+		Force:         force,
+		SortTar:       sortTar,
+		TarFormat:     tarFormat,
+	})
+}
+
+func checksumImageWithConfig(imageRef string, config *internal.ResolvedConfig, globalFlags *GlobalFlags, sortTar bool, tarFormat tar.Format, gitPath, appName string, compressionWorkers int) (*internal.ChecksumResult, error) {
+	ctx := context.Background()
+
+	setupAWSEnv(config)
 
 	dockerClient, err := internal.NewDockerClient()
 	if err != nil {
@@ -395,7 +870,7 @@ func pushImageWithConfig(imageRef string, config *internal.ResolvedConfig, globa
 	}
 	defer dockerClient.Close()
 
-	s3Client, err := internal.NewS3Client(ctx)
+	s3Client, err := internal.NewBackendS3Client(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create S3 client: %w", err)
 	}
@@ -403,8 +878,16 @@ func pushImageWithConfig(imageRef string, config *internal.ResolvedConfig, globa
 	gitClient := internal.NewGitClient()
 
 	pusher := internal.NewImagePusher(dockerClient, s3Client, gitClient, config.Bucket)
+	pusher.SetPathTemplate(config.PathTemplate)
+	pusher.SetGitPath(gitPath)
+	if appName != "" {
+		pusher.SetAppName(appName)
+	}
+	if compressionWorkers > 0 {
+		pusher.SetCompressionWorkers(compressionWorkers)
+	}
 
-	return pusher.Push(ctx, imageRef)
+	return pusher.ChecksumOnly(ctx, imageRef, internal.PushOptions{SortTar: sortTar, TarFormat: tarFormat})
 }
 
 func handleBuildCommand(globalFlags *GlobalFlags, args []string) {
@@ -417,7 +900,17 @@ func handleBuildCommand(globalFlags *GlobalFlags, args []string) {
 		fmt.Println("  --path <directory>   Git repository path (default: .)")
 		fmt.Println("  --dockerfile <path>  Dockerfile to use (default: Dockerfile)")
 		fmt.Println("  --context <path>     Build context path (default: .)")
+		fmt.Println("  --dockerignore <path> Ignore file to use instead of .dockerignore in the context root")
 		fmt.Println("  --platform <platform> Target platform (e.g., linux/amd64, linux/arm64)")
+		fmt.Println("  --cache-from <image> Warm the layer cache from an image (repeatable; docker.cache_from sets defaults)")
+		fmt.Println("  --cache-to <spec>    Export build cache (repeatable; requires docker.builder = \"buildkit\")")
+		fmt.Println("  --squash             Squash new layers into a single layer (legacy builder only)")
+		fmt.Println("  --pull               Always attempt to pull a newer version of base images")
+		fmt.Println("  --dereference-symlinks Follow symlinks in the build context and tar their targets")
+		fmt.Println("  --since-tag          Skip the build if the context subtree is unchanged since the latest pushed image")
+		fmt.Println("  --verify-reproducible Build twice and fail if the normalized exports differ")
+		fmt.Println("  --tag-file <path>    Write the built image reference to a file for later CI steps")
+		fmt.Println("  --tag-file-format <fmt> Tag file format: text (default) or json")
 		fmt.Println("")
 		fmt.Println("Note: If --path is specified but --context is not, both will use the same path.")
 		fmt.Println("")
@@ -432,6 +925,7 @@ func handleBuildCommand(globalFlags *GlobalFlags, args []string) {
 		fmt.Println("  s3dock build myapp --path /git/repo --context /build/context")
 		fmt.Println("  s3dock build myapp --platform linux/amd64")
 		fmt.Println("  s3dock build myapp --platform linux/arm64")
+		fmt.Println("  s3dock build myapp --output-tag-file .s3dock-tag && s3dock push $(cat .s3dock-tag)")
 		return
 	}
 
@@ -439,13 +933,34 @@ func handleBuildCommand(globalFlags *GlobalFlags, args []string) {
 	buildArgs := args[1:]
 
 	dockerfile := "Dockerfile"
-	contextPath := "."
-	gitPath := "."
+	contextPath := ""
+	gitPath := ""
 	platform := ""
+	verifyReproducible := false
+	squash := false
+	pull := false
+	derefSymlinks := false
+	sinceTag := false
+	dockerignore := ""
+	tagFile := ""
+	tagFileFormat := "text"
+	outputTagFile := ""
+	var cacheFrom []string
+	var cacheTo []string
 
 	for i := 0; i < len(buildArgs); i++ {
 		arg := buildArgs[i]
 		switch arg {
+		case "--verify-reproducible":
+			verifyReproducible = true
+		case "--squash":
+			squash = true
+		case "--pull":
+			pull = true
+		case "--dereference-symlinks":
+			derefSymlinks = true
+		case "--since-tag":
+			sinceTag = true
 		case "--path":
 			if i+1 < len(buildArgs) {
 				gitPath = buildArgs[i+1]
@@ -461,49 +976,78 @@ func handleBuildCommand(globalFlags *GlobalFlags, args []string) {
 				contextPath = buildArgs[i+1]
 				i++
 			}
+		case "--dockerignore":
+			if i+1 < len(buildArgs) {
+				dockerignore = buildArgs[i+1]
+				i++
+			}
 		case "--platform":
 			if i+1 < len(buildArgs) {
 				platform = buildArgs[i+1]
 				i++
 			}
+		case "--cache-from":
+			if i+1 < len(buildArgs) {
+				cacheFrom = append(cacheFrom, buildArgs[i+1])
+				i++
+			}
+		case "--cache-to":
+			if i+1 < len(buildArgs) {
+				cacheTo = append(cacheTo, buildArgs[i+1])
+				i++
+			}
+		case "--tag-file":
+			if i+1 < len(buildArgs) {
+				tagFile = buildArgs[i+1]
+				i++
+			}
+		case "--tag-file-format":
+			if i+1 < len(buildArgs) {
+				tagFileFormat = buildArgs[i+1]
+				i++
+			}
+		case "--output-tag-file":
+			if i+1 < len(buildArgs) {
+				outputTagFile = buildArgs[i+1]
+				i++
+			}
 		}
 	}
 
-	// If --path is specified but --context is not, use the same path for both
-	if gitPath != "." && contextPath == "." {
-		contextPath = gitPath
-	}
-
-	// Always try to find the git repository root
+	// Resolve the git root and build context; --context only selects the
+	// Docker context and never changes git root detection
 	gitClient := internal.NewGitClient()
-	
-	// First try to find repository from the gitPath
-	if repoRoot, err := gitClient.FindRepositoryRoot(gitPath); err == nil {
-		internal.LogDebug("Found git repository root from gitPath: %s", repoRoot)
-		gitPath = repoRoot
-	} else {
-		// If that fails, try from the context path
-		if repoRoot, err := gitClient.FindRepositoryRoot(contextPath); err == nil {
-			internal.LogDebug("Found git repository root from contextPath: %s", repoRoot)
-			gitPath = repoRoot
-		} else {
-			// Finally, try from current working directory
-			if repoRoot, err := gitClient.FindRepositoryRoot("."); err == nil {
-				internal.LogDebug("Found git repository root from current directory: %s", repoRoot)
-				gitPath = repoRoot
-			} else {
-				internal.LogError("Could not find git repository: %v", err)
-				os.Exit(1)
-			}
-		}
+
+	resolvedGitPath, resolvedContextPath, err := internal.ResolveBuildPaths(gitClient, gitPath, contextPath)
+	if err != nil {
+		internal.LogError("Could not find git repository: %v", err)
+		os.Exit(1)
 	}
+	gitPath = resolvedGitPath
+	contextPath = resolvedContextPath
 
-	result, err := buildImageWithConfig(appName, contextPath, dockerfile, gitPath, platform)
+	result, err := buildImageWithConfig(globalFlags, appName, contextPath, dockerfile, gitPath, platform, verifyReproducible, cacheFrom, cacheTo, squash, pull, derefSymlinks, sinceTag, dockerignore)
 	if err != nil {
 		internal.OutputError("build", err)
 		os.Exit(1)
 	}
 
+	if tagFile != "" && !result.Skipped {
+		if err := writeTagFile(tagFile, tagFileFormat, result.ImageTag, "", ""); err != nil {
+			internal.OutputError("build", fmt.Errorf("failed to write tag file: %w", err))
+			os.Exit(1)
+		}
+	}
+
+	// Unlike --tag-file, this writes just the bare app:tag string so pipeline
+	// steps can consume it without parsing: s3dock push $(cat .s3dock-tag)
+	if outputTagFile != "" && !result.Skipped {
+		if err := os.WriteFile(outputTagFile, []byte(result.ImageTag), 0644); err != nil {
+			internal.OutputError("build", fmt.Errorf("failed to write output tag file: %w", err))
+			os.Exit(1)
+		}
+	}
+
 	// JSON output
 	if globalFlags.JSON {
 		internal.OutputResult("build", result)
@@ -513,36 +1057,98 @@ func handleBuildCommand(globalFlags *GlobalFlags, args []string) {
 	// Text output is handled by logs in the builder
 }
 
-func buildImageWithConfig(appName, contextPath, dockerfile, gitPath, platform string) (*internal.BuildResult, error) {
+func buildImageWithConfig(globalFlags *GlobalFlags, appName, contextPath, dockerfile, gitPath, platform string, verifyReproducible bool, cacheFrom, cacheTo []string, squash, pull, derefSymlinks, sinceTag bool, dockerignore string) (*internal.BuildResult, error) {
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx := context.Background()
 
+	if sinceTag {
+		setupAWSEnv(config)
+
+		s3Client, err := internal.NewBackendS3Client(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create S3 client: %w", err)
+		}
+
+		unchanged, err := internal.UnchangedSinceLatest(ctx, s3Client, internal.NewGitClient(), config.Bucket, appName, gitPath, contextPath)
+		if err != nil {
+			return nil, err
+		}
+		if unchanged {
+			internal.LogInfo("No changes in %s since the latest pushed image - skipping build", contextPath)
+			return &internal.BuildResult{AppName: appName, Skipped: true}, nil
+		}
+	}
+
 	dockerClient, err := internal.NewDockerClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
 	defer dockerClient.Close()
 
+	dockerClient.SetDereferenceSymlinks(derefSymlinks)
+	if dockerignore != "" {
+		dockerClient.SetIgnoreFile(dockerignore)
+	}
+
+	// Config supplies default cache sources; flags add to them
+	cacheFrom = append(append([]string{}, config.DockerCacheFrom...), cacheFrom...)
+
+	switch config.DockerBuilder {
+	case "", "legacy":
+		// Legacy SDK builder stays the default for compatibility. Cache export
+		// needs BuildKit, which platform builds already go through via buildx.
+		if len(cacheTo) > 0 {
+			if platform == "" {
+				return nil, fmt.Errorf("--cache-to requires docker.builder = \"buildkit\" or --platform")
+			}
+			dockerClient.UseBuildKit(nil, cacheTo)
+		}
+	case "buildkit":
+		dockerClient.UseBuildKit(nil, cacheTo)
+	default:
+		return nil, fmt.Errorf("unknown docker.builder %q (expected \"legacy\" or \"buildkit\")", config.DockerBuilder)
+	}
+
 	gitClient := internal.NewGitClient()
 
 	builder := internal.NewImageBuilder(dockerClient, gitClient)
 
-	return builder.Build(ctx, appName, contextPath, dockerfile, gitPath, platform)
+	if verifyReproducible {
+		return builder.VerifyReproducible(ctx, appName, contextPath, dockerfile, gitPath, platform, cacheFrom, squash, pull)
+	}
+
+	return builder.Build(ctx, appName, contextPath, dockerfile, gitPath, platform, cacheFrom, squash, pull)
 }
 
 func handleTagCommand(globalFlags *GlobalFlags, args []string) {
 	if len(args) < 2 {
 		fmt.Println("Usage: s3dock [global-flags] tag <image:tag> <version>")
+		fmt.Println("   or: s3dock [global-flags] tag <app> <environment> <version>")
 		fmt.Println("")
 		fmt.Println("Create a semantic version tag for an image.")
 		fmt.Println("")
 		fmt.Println("Examples:")
 		fmt.Println("  s3dock tag myapp:20250721-2118-f7a5a27 v1.2.0")
 		fmt.Println("  s3dock tag myapp:20250720-1045-def5678 v1.1.5")
+		fmt.Println("  s3dock tag myapp staging v1.3.0")
+		fmt.Println("  s3dock build myapp | s3dock tag - v1.2.0")
 		return
 	}
 
-	imageRef := args[0]
-	version := args[1]
+	noAudit := false
+	var positional []string
+	for _, arg := range args {
+		if arg == "--no-audit" {
+			noAudit = true
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	args = positional
 
 	resolved, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
 	if err != nil {
@@ -550,7 +1156,18 @@ func handleTagCommand(globalFlags *GlobalFlags, args []string) {
 		os.Exit(1)
 	}
 
-	result, err := tagImageWithConfig(imageRef, version, resolved, globalFlags)
+	var result *internal.TagResult
+	if len(args) == 3 {
+		// Environment as source: s3dock tag myapp staging v1.3.0
+		result, err = tagEnvWithConfig(args[0], args[1], args[2], resolved, globalFlags, noAudit)
+	} else {
+		imageRef, refErr := resolveRefArg(args[0])
+		if refErr != nil {
+			internal.OutputError("tag", refErr)
+			os.Exit(1)
+		}
+		result, err = tagImageWithConfig(imageRef, args[1], resolved, globalFlags, noAudit)
+	}
 	if err != nil {
 		internal.OutputError("tag", err)
 		os.Exit(1)
@@ -569,19 +1186,170 @@ func handlePromoteCommand(globalFlags *GlobalFlags, args []string) {
 	if len(args) < 2 {
 		fmt.Println("Usage: s3dock [global-flags] promote <source> <environment>")
 		fmt.Println("   or: s3dock [global-flags] promote <app> <version> <environment>")
+		fmt.Println("   or: s3dock [global-flags] promote <app> <environment> --from-env <source-env>")
 		fmt.Println("")
 		fmt.Println("Promote an image or tag to an environment.")
 		fmt.Println("")
 		fmt.Println("Examples:")
 		fmt.Println("  s3dock promote myapp:20250721-2118-f7a5a27 production")
 		fmt.Println("  s3dock promote myapp v1.2.0 staging")
+		fmt.Println("  s3dock promote myapp:20250721-2118-f7a5a27 production --schedule 2025-08-01T09:00:00Z")
+		fmt.Println("  s3dock promote myapp production --from-env staging")
+		fmt.Println("  s3dock promote --from-file promotions.json5 [--dry-run]")
+		fmt.Println("  s3dock promote myapp:20250721-2118-f7a5a27 production --wait-for-consistency 10")
+		fmt.Println("  s3dock build myapp | s3dock promote - staging")
+		fmt.Println("  s3dock promote --apps app1,app2,app3 v1.2.0 production [--parallel | --stop-on-error]")
+		fmt.Println("  s3dock promote myapp:20250721-2118-f7a5a27 production --confirm [--yes]")
+		fmt.Println("  s3dock promote myapp:20250721-2118-f7a5a27 staging --no-audit")
+		fmt.Println("  s3dock promote myapp:20250721-2118-f7a5a27 production --no-skip-identical")
 		return
 	}
 
-	var source, environment, appName, version string
-	if len(args) == 2 {
-		// Direct image promotion: s3dock promote myapp:20250721-2118-f7a5a27 production
-		source = args[0]
+	var scheduleAt *time.Time
+	fromFile := ""
+	fromEnv := ""
+	actor := ""
+	apps := ""
+	parallel := false
+	stopOnError := false
+	dryRun := globalFlags.DryRun
+	confirm := false
+	yes := false
+	noAudit := false
+	noSkipIdentical := false
+	consistencyWait := time.Duration(0)
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--confirm":
+			confirm = true
+		case "--yes":
+			yes = true
+		case "--no-audit":
+			noAudit = true
+		case "--no-skip-identical":
+			noSkipIdentical = true
+		case "--apps":
+			if i+1 < len(args) {
+				apps = args[i+1]
+				i++
+			}
+		case "--parallel":
+			parallel = true
+		case "--stop-on-error":
+			stopOnError = true
+		case "--wait-for-consistency":
+			if i+1 < len(args) {
+				seconds := 0
+				if _, err := fmt.Sscanf(args[i+1], "%d", &seconds); err != nil || seconds <= 0 {
+					internal.OutputError("promote", fmt.Errorf("invalid --wait-for-consistency value: %s (expected seconds)", args[i+1]))
+					os.Exit(1)
+				}
+				consistencyWait = time.Duration(seconds) * time.Second
+				i++
+			}
+		case "--schedule":
+			if i+1 < len(args) {
+				parsed, err := time.Parse(time.RFC3339, args[i+1])
+				if err != nil {
+					internal.OutputError("promote", fmt.Errorf("invalid --schedule time (expected RFC3339, e.g. 2025-08-01T09:00:00Z): %w", err))
+					os.Exit(1)
+				}
+				scheduleAt = &parsed
+				i++
+			}
+		case "--from-file":
+			if i+1 < len(args) {
+				fromFile = args[i+1]
+				i++
+			}
+		case "--from-env":
+			if i+1 < len(args) {
+				fromEnv = args[i+1]
+				i++
+			}
+		case "--actor":
+			if i+1 < len(args) {
+				actor = args[i+1]
+				i++
+			}
+		case "--dry-run":
+			dryRun = true
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+	args = positional
+
+	if fromFile != "" {
+		handleBatchPromote(globalFlags, fromFile, dryRun)
+		return
+	}
+
+	if apps != "" {
+		// Release train: s3dock promote --apps app1,app2,app3 <version> <env>
+		if len(args) != 2 {
+			internal.OutputError("promote", fmt.Errorf("--apps requires version and environment"))
+			os.Exit(1)
+		}
+		if parallel && stopOnError {
+			internal.OutputError("promote", fmt.Errorf("--stop-on-error cannot be combined with --parallel"))
+			os.Exit(1)
+		}
+		handleReleaseTrain(globalFlags, strings.Split(apps, ","), args[0], args[1], parallel, stopOnError, consistencyWait)
+		return
+	}
+
+	var source, environment, appName, version string
+	if fromEnv != "" {
+		// Environment-to-environment promotion: s3dock promote myapp production --from-env staging
+		if len(args) != 2 {
+			internal.LogError("--from-env requires app name and destination environment")
+			os.Exit(1)
+		}
+		if scheduleAt != nil {
+			internal.OutputError("promote", fmt.Errorf("--schedule is only supported for direct image promotion"))
+			os.Exit(1)
+		}
+		appName = args[0]
+		environment = args[1]
+
+		resolved, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+		if err != nil {
+			internal.OutputError("promote", err)
+			os.Exit(1)
+		}
+
+		if environmentRequiresApproval(resolved, environment) {
+			internal.OutputError("promote", fmt.Errorf("environment %s requires approval; promote a direct image reference", environment))
+			os.Exit(1)
+		}
+
+		confirmPromotionGuard(resolved, appName, environment, fmt.Sprintf("%s's current target", fromEnv), confirm, yes)
+
+		result, err := promoteEnvWithConfig(appName, fromEnv, environment, resolved, globalFlags, consistencyWait, noAudit, noSkipIdentical)
+		if err != nil {
+			internal.OutputError("promote", err)
+			os.Exit(1)
+		}
+
+		if globalFlags.JSON {
+			internal.OutputResult("promote", result)
+			return
+		}
+		if result.Skipped {
+			fmt.Printf("No change: %s already points to %s's target\n", result.Environment, result.Source)
+		}
+		return
+	}
+	if len(args) == 2 {
+		// Direct image promotion: s3dock promote myapp:20250721-2118-f7a5a27 production
+		src, err := resolveRefArg(args[0])
+		if err != nil {
+			internal.OutputError("promote", err)
+			os.Exit(1)
+		}
+		source = src
 		environment = args[1]
 	} else if len(args) == 3 {
 		// Tag-based promotion: s3dock promote myapp v1.2.0 staging
@@ -601,13 +1369,27 @@ func handlePromoteCommand(globalFlags *GlobalFlags, args []string) {
 
 	var result *internal.PromoteResult
 	if len(args) == 2 {
-		result, err = promoteImageWithConfig(source, environment, resolved, globalFlags)
+		sourceApp := source
+		if idx := strings.Index(source, ":"); idx >= 0 {
+			sourceApp = source[:idx]
+		}
+		confirmPromotionGuard(resolved, sourceApp, environment, source, confirm, yes)
+		result, err = promoteImageWithConfig(source, environment, resolved, globalFlags, scheduleAt, actor, consistencyWait, noAudit, noSkipIdentical)
 		if err != nil {
 			internal.OutputError("promote", err)
 			os.Exit(1)
 		}
 	} else {
-		result, err = promoteTagWithConfig(appName, version, environment, resolved, globalFlags)
+		if scheduleAt != nil {
+			internal.OutputError("promote", fmt.Errorf("--schedule is only supported for direct image promotion"))
+			os.Exit(1)
+		}
+		if environmentRequiresApproval(resolved, environment) {
+			internal.OutputError("promote", fmt.Errorf("environment %s requires approval; promote a direct image reference", environment))
+			os.Exit(1)
+		}
+		confirmPromotionGuard(resolved, appName, environment, version, confirm, yes)
+		result, err = promoteTagWithConfig(appName, version, environment, resolved, globalFlags, consistencyWait, noAudit, noSkipIdentical)
 		if err != nil {
 			internal.OutputError("promote", err)
 			os.Exit(1)
@@ -620,234 +1402,1240 @@ func handlePromoteCommand(globalFlags *GlobalFlags, args []string) {
 		return
 	}
 
-	// Text output is handled by logs in the promoter
+	// Text output is handled by logs in the promoter, but a no-op should be
+	// visible even at low log levels
+	if result.Skipped {
+		fmt.Printf("No change: %s already points to %s\n", result.Environment, result.Source)
+	}
+	if result.AwaitingApproval {
+		fmt.Printf("Promotion of %s to %s recorded, awaiting approval by a second actor\n", result.Source, result.Environment)
+	}
 }
 
-func tagImageWithConfig(imageRef, version string, config *internal.ResolvedConfig, globalFlags *GlobalFlags) (*internal.TagResult, error) {
-	ctx := context.Background()
+// environmentRequiresApproval reports whether promotions to the environment
+// need a second approver (promotion.require_approval in the config).
+func environmentRequiresApproval(config *internal.ResolvedConfig, environment string) bool {
+	for _, env := range config.RequireApproval {
+		if env == environment {
+			return true
+		}
+	}
+	return false
+}
 
-	os.Setenv("AWS_REGION", config.Region)
-	if config.Endpoint != "" {
-		os.Setenv("AWS_ENDPOINT_URL", config.Endpoint)
+// environmentRequiresConfirm reports whether promotions to the environment
+// must be confirmed interactively (promotion.require_confirm_environments).
+func environmentRequiresConfirm(config *internal.ResolvedConfig, environment string) bool {
+	for _, env := range config.RequireConfirm {
+		if env == environment {
+			return true
+		}
 	}
-	if config.AccessKey != "" {
-		os.Setenv("AWS_ACCESS_KEY_ID", config.AccessKey)
+	return false
+}
+
+// confirmPromotionGuard runs the interactive y/N check before a promotion
+// when --confirm was given or the environment is listed in
+// promotion.require_confirm_environments. --yes skips the prompt.
+func confirmPromotionGuard(resolved *internal.ResolvedConfig, appName, environment, newTarget string, confirm, yes bool) {
+	if yes {
+		return
 	}
-	if config.SecretKey != "" {
-		os.Setenv("AWS_SECRET_ACCESS_KEY", config.SecretKey)
+	if !confirm && !environmentRequiresConfirm(resolved, environment) {
+		return
 	}
 
-	s3Client, err := internal.NewS3Client(ctx)
+	ctx := context.Background()
+	setupAWSEnv(resolved)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+		internal.OutputError("promote", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
 	}
 
-	tagger := internal.NewImageTagger(s3Client, config.Bucket)
-
-	return tagger.Tag(ctx, imageRef, version)
+	prompter := &internal.StdinPrompter{In: os.Stdin, Out: os.Stderr}
+	ok, err := internal.ConfirmPromotion(ctx, s3Client, resolved.Bucket, appName, environment, newTarget, prompter)
+	if err != nil {
+		internal.OutputError("promote", err)
+		os.Exit(1)
+	}
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Promotion aborted")
+		os.Exit(1)
+	}
 }
 
-func promoteImageWithConfig(source, environment string, config *internal.ResolvedConfig, globalFlags *GlobalFlags) (*internal.PromoteResult, error) {
-	ctx := context.Background()
-
-	os.Setenv("AWS_REGION", config.Region)
-	if config.Endpoint != "" {
-		os.Setenv("AWS_ENDPOINT_URL", config.Endpoint)
-	}
-	if config.AccessKey != "" {
-		os.Setenv("AWS_ACCESS_KEY_ID", config.AccessKey)
+func handleBatchPromote(globalFlags *GlobalFlags, fromFile string, dryRun bool) {
+	entries, err := internal.LoadPromotionFile(fromFile)
+	if err != nil {
+		internal.OutputError("promote", err)
+		os.Exit(1)
 	}
-	if config.SecretKey != "" {
-		os.Setenv("AWS_SECRET_ACCESS_KEY", config.SecretKey)
+
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		internal.OutputError("promote", err)
+		os.Exit(1)
 	}
 
-	s3Client, err := internal.NewS3Client(ctx)
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+		internal.OutputError("promote", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
 	}
 
 	promoter := internal.NewImagePromoter(s3Client, config.Bucket)
+	promoter.SetPathTemplate(config.PathTemplate)
 
-	return promoter.Promote(ctx, source, environment)
-}
-
-func promoteTagWithConfig(appName, version, environment string, config *internal.ResolvedConfig, globalFlags *GlobalFlags) (*internal.PromoteResult, error) {
-	ctx := context.Background()
+	result := promoter.PromoteBatch(ctx, entries, dryRun)
 
-	os.Setenv("AWS_REGION", config.Region)
-	if config.Endpoint != "" {
-		os.Setenv("AWS_ENDPOINT_URL", config.Endpoint)
+	// JSON output
+	if globalFlags.JSON {
+		internal.OutputResult("promote", result)
+		if result.Failed > 0 {
+			os.Exit(1)
+		}
+		return
 	}
-	if config.AccessKey != "" {
-		os.Setenv("AWS_ACCESS_KEY_ID", config.AccessKey)
+
+	// Text output
+	for _, entry := range result.Entries {
+		if entry.Error != "" {
+			fmt.Printf("%s -> %s: %s (%s)\n", entry.Source, entry.Environment, entry.Status, entry.Error)
+		} else {
+			fmt.Printf("%s -> %s: %s\n", entry.Source, entry.Environment, entry.Status)
+		}
 	}
-	if config.SecretKey != "" {
-		os.Setenv("AWS_SECRET_ACCESS_KEY", config.SecretKey)
+	fmt.Printf("Promoted %d, skipped %d, failed %d of %d\n", result.Promoted, result.Skipped, result.Failed, result.Total)
+	if result.Failed > 0 {
+		os.Exit(1)
 	}
+}
 
-	s3Client, err := internal.NewS3Client(ctx)
+// handleReleaseTrain promotes the same version tag across several apps
+// (promote --apps app1,app2,app3 <version> <env>).
+func handleReleaseTrain(globalFlags *GlobalFlags, apps []string, version, environment string, parallel, stopOnError bool, consistencyWait time.Duration) {
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+		internal.OutputError("promote", err)
+		os.Exit(1)
 	}
 
-	promoter := internal.NewImagePromoter(s3Client, config.Bucket)
+	if environmentRequiresApproval(config, environment) {
+		internal.OutputError("promote", fmt.Errorf("environment %s requires approval; promote a direct image reference", environment))
+		os.Exit(1)
+	}
 
-	return promoter.PromoteFromTag(ctx, appName, version, environment)
-}
+	ctx := context.Background()
+	setupAWSEnv(config)
 
-func handlePullCommand(globalFlags *GlobalFlags, args []string) {
-	if len(args) < 2 {
-		internal.LogError("Pull command requires app name and environment/tag")
-		fmt.Fprintf(os.Stderr, "Usage:\n")
-		fmt.Fprintf(os.Stderr, "  %s pull <app> <environment>    # Pull from environment (e.g., production, staging)\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s pull <app> <tag>           # Pull from tag (e.g., v1.2.0)\n", os.Args[0])
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("promote", fmt.Errorf("failed to create S3 client: %w", err))
 		os.Exit(1)
 	}
 
-	appName := args[0]
-	target := args[1]
+	promoter := internal.NewImagePromoter(s3Client, config.Bucket)
+	promoter.SetPathTemplate(config.PathTemplate)
+	promoter.SetConsistencyWait(consistencyWait)
 
-	// Determine if target is a version tag (starts with 'v') or environment
-	var result *internal.PullResult
-	var err error
-	if strings.HasPrefix(target, "v") && len(strings.Split(target, ".")) >= 2 {
-		// It's a version tag like v1.2.0
-		result, err = pullTagWithConfig(appName, target, globalFlags)
-		if err != nil {
-			internal.OutputError("pull", err)
-			os.Exit(1)
-		}
-	} else {
-		// It's an environment like production, staging
-		result, err = pullImageWithConfig(appName, target, globalFlags)
-		if err != nil {
-			internal.OutputError("pull", err)
-			os.Exit(1)
-		}
-	}
+	result := promoter.PromoteApps(ctx, apps, version, environment, parallel, stopOnError)
 
 	// JSON output
 	if globalFlags.JSON {
-		internal.OutputResult("pull", result)
+		internal.OutputResult("promote", result)
+		if result.Failed > 0 {
+			os.Exit(1)
+		}
 		return
 	}
 
-	// Text output is handled by logs in the puller
+	// Text output
+	for _, entry := range result.Entries {
+		if entry.Error != "" {
+			fmt.Printf("%s %s -> %s: %s (%s)\n", entry.App, entry.Source, entry.Environment, entry.Status, entry.Error)
+		} else {
+			fmt.Printf("%s %s -> %s: %s\n", entry.App, entry.Source, entry.Environment, entry.Status)
+		}
+	}
+	fmt.Printf("Promoted %d, skipped %d, failed %d of %d\n", result.Promoted, result.Skipped, result.Failed, result.Total)
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
 }
 
-func pullImageWithConfig(appName, environment string, globalFlags *GlobalFlags) (*internal.PullResult, error) {
+func handleApplyPendingCommand(globalFlags *GlobalFlags, args []string) {
+	if len(args) != 2 {
+		internal.OutputError("apply-pending", fmt.Errorf("apply-pending requires app name and environment"))
+		fmt.Fprintf(os.Stderr, "Usage: s3dock apply-pending <app> <environment>\n")
+		os.Exit(1)
+	}
+
+	appName := args[0]
+	environment := args[1]
+
 	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
 	if err != nil {
-		return nil, err
+		internal.OutputError("apply-pending", err)
+		os.Exit(1)
 	}
 
 	ctx := context.Background()
+	setupAWSEnv(config)
 
-	// Set environment variables for AWS configuration
-	os.Setenv("AWS_REGION", config.Region)
-	if config.Endpoint != "" {
-		os.Setenv("AWS_ENDPOINT_URL", config.Endpoint)
-	}
-	if config.AccessKey != "" && config.SecretKey != "" {
-		os.Setenv("AWS_ACCESS_KEY_ID", config.AccessKey)
-		os.Setenv("AWS_SECRET_ACCESS_KEY", config.SecretKey)
-	}
-
-	s3Client, err := internal.NewS3Client(ctx)
+	s3Client, err := internal.NewBackendS3Client(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+		internal.OutputError("apply-pending", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
 	}
 
-	dockerClient, err := internal.NewDockerClient()
+	promoter := internal.NewImagePromoter(s3Client, config.Bucket)
+	promoter.SetPathTemplate(config.PathTemplate)
+
+	result, err := promoter.ApplyPending(ctx, appName, environment)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+		internal.OutputError("apply-pending", err)
+		os.Exit(1)
 	}
 
-	puller := internal.NewImagePuller(dockerClient, s3Client, config.Bucket)
+	// JSON output
+	if globalFlags.JSON {
+		internal.OutputResult("apply-pending", result)
+		return
+	}
 
-	return puller.Pull(ctx, appName, environment)
+	// Text output is handled by logs in the promoter
 }
 
-func pullTagWithConfig(appName, version string, globalFlags *GlobalFlags) (*internal.PullResult, error) {
+func handleApproveCommand(globalFlags *GlobalFlags, args []string) {
+	actor := ""
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--actor":
+			if i+1 < len(args) {
+				actor = args[i+1]
+				i++
+			}
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	if len(positional) != 2 {
+		internal.OutputError("approve", fmt.Errorf("approve requires app name and environment"))
+		fmt.Fprintf(os.Stderr, "Usage: s3dock approve <app> <environment> [--actor <name>]\n")
+		os.Exit(1)
+	}
+
+	appName := positional[0]
+	environment := positional[1]
+
 	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
 	if err != nil {
-		return nil, err
+		internal.OutputError("approve", err)
+		os.Exit(1)
 	}
 
 	ctx := context.Background()
+	setupAWSEnv(config)
 
-	// Set environment variables for AWS configuration
-	os.Setenv("AWS_REGION", config.Region)
-	if config.Endpoint != "" {
-		os.Setenv("AWS_ENDPOINT_URL", config.Endpoint)
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("approve", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
 	}
-	if config.AccessKey != "" && config.SecretKey != "" {
-		os.Setenv("AWS_ACCESS_KEY_ID", config.AccessKey)
-		os.Setenv("AWS_SECRET_ACCESS_KEY", config.SecretKey)
+
+	promoter := internal.NewImagePromoter(s3Client, config.Bucket)
+	promoter.SetPathTemplate(config.PathTemplate)
+	if actor != "" {
+		promoter.SetActor(actor)
 	}
 
-	s3Client, err := internal.NewS3Client(ctx)
+	result, err := promoter.Approve(ctx, appName, environment)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+		internal.OutputError("approve", err)
+		os.Exit(1)
 	}
 
-	dockerClient, err := internal.NewDockerClient()
+	// JSON output
+	if globalFlags.JSON {
+		internal.OutputResult("approve", result)
+		return
+	}
+
+	// Text output is handled by logs in the promoter
+}
+
+func tagImageWithConfig(imageRef, version string, config *internal.ResolvedConfig, globalFlags *GlobalFlags, noAudit bool) (*internal.TagResult, error) {
+	ctx := context.Background()
+
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	tagger := internal.NewImageTagger(s3Client, config.Bucket)
+	tagger.SetPathTemplate(config.PathTemplate)
+	if noAudit {
+		tagger.DisableAudit()
+	}
+
+	return tagger.Tag(ctx, imageRef, version)
+}
+
+func tagEnvWithConfig(appName, environment, version string, config *internal.ResolvedConfig, globalFlags *GlobalFlags, noAudit bool) (*internal.TagResult, error) {
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	tagger := internal.NewImageTagger(s3Client, config.Bucket)
+	tagger.SetPathTemplate(config.PathTemplate)
+	if noAudit {
+		tagger.DisableAudit()
+	}
+
+	return tagger.TagFromEnvironment(ctx, appName, environment, version)
+}
+
+func promoteImageWithConfig(source, environment string, config *internal.ResolvedConfig, globalFlags *GlobalFlags, scheduleAt *time.Time, actor string, consistencyWait time.Duration, noAudit, noSkipIdentical bool) (*internal.PromoteResult, error) {
+	ctx := context.Background()
+
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	promoter := internal.NewImagePromoter(s3Client, config.Bucket)
+	promoter.SetPathTemplate(config.PathTemplate)
+	promoter.SetConsistencyWait(consistencyWait)
+	if actor != "" {
+		promoter.SetActor(actor)
+	}
+	if noAudit {
+		promoter.DisableAudit()
+	}
+	if noSkipIdentical {
+		promoter.AuditSkips()
+	}
+
+	if environmentRequiresApproval(config, environment) {
+		if scheduleAt != nil {
+			return nil, fmt.Errorf("--schedule cannot be combined with an approval-protected environment")
+		}
+		return promoter.RequestApproval(ctx, source, environment)
+	}
+
+	if scheduleAt != nil {
+		return promoter.SchedulePromote(ctx, source, environment, *scheduleAt)
+	}
+
+	return promoter.Promote(ctx, source, environment)
+}
+
+func promoteTagWithConfig(appName, version, environment string, config *internal.ResolvedConfig, globalFlags *GlobalFlags, consistencyWait time.Duration, noAudit, noSkipIdentical bool) (*internal.PromoteResult, error) {
+	ctx := context.Background()
+
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	promoter := internal.NewImagePromoter(s3Client, config.Bucket)
+	promoter.SetPathTemplate(config.PathTemplate)
+	promoter.SetConsistencyWait(consistencyWait)
+	if noAudit {
+		promoter.DisableAudit()
+	}
+	if noSkipIdentical {
+		promoter.AuditSkips()
+	}
+
+	return promoter.PromoteFromTag(ctx, appName, version, environment)
+}
+
+func promoteEnvWithConfig(appName, srcEnv, dstEnv string, config *internal.ResolvedConfig, globalFlags *GlobalFlags, consistencyWait time.Duration, noAudit, noSkipIdentical bool) (*internal.PromoteResult, error) {
+	ctx := context.Background()
+
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	promoter := internal.NewImagePromoter(s3Client, config.Bucket)
+	promoter.SetPathTemplate(config.PathTemplate)
+	promoter.SetConsistencyWait(consistencyWait)
+	if noAudit {
+		promoter.DisableAudit()
+	}
+	if noSkipIdentical {
+		promoter.AuditSkips()
+	}
+
+	return promoter.PromoteFromEnvironment(ctx, appName, srcEnv, dstEnv)
+}
+
+func handlePullCommand(globalFlags *GlobalFlags, args []string) {
+	onlyMetadata := false
+	stripTags := false
+	keepDownload := false
+	localRefPrefix := ""
+	platform := ""
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--only-metadata":
+			onlyMetadata = true
+		case "--platform":
+			if i+1 < len(args) {
+				platform = args[i+1]
+				i++
+			}
+		case "--strip-tags-on-import":
+			stripTags = true
+		case "--keep-download":
+			keepDownload = true
+		case "--local-ref-prefix":
+			if i+1 < len(args) {
+				localRefPrefix = args[i+1]
+				i++
+			}
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	if len(positional) < 2 {
+		internal.LogError("Pull command requires app name and environment/tag")
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  %s pull <app> <environment>    # Pull from environment (e.g., production, staging)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s pull <app> <tag>           # Pull from tag (e.g., v1.2.0)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s pull <app> <target> --only-metadata    # Fetch just the image metadata JSON\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s pull <app> <target> --strip-tags-on-import    # Keep only the canonical tag locally\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s pull <app> <target> --keep-download    # Preserve the downloaded .tar.gz for inspection\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s pull <app> <target> --local-ref-prefix <registry>    # Also tag locally as <registry>/<app>:<tag>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s pull <app> <target> --platform <os/arch>    # Fail if the imported image's architecture does not match\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	appName := positional[0]
+	target := positional[1]
+
+	if globalFlags.LocalRegistry != "" {
+		if onlyMetadata {
+			internal.OutputError("pull", fmt.Errorf("--only-metadata is not supported with --local-registry"))
+			os.Exit(1)
+		}
+
+		backend, dockerClient, err := registryBackendFromFlags(globalFlags)
+		if err != nil {
+			internal.OutputError("pull", err)
+			os.Exit(1)
+		}
+		defer dockerClient.Close()
+
+		localRef, err := backend.PullImage(context.Background(), appName, target)
+		if err != nil {
+			internal.OutputError("pull", err)
+			os.Exit(1)
+		}
+
+		if globalFlags.JSON {
+			sourceType := "environment"
+			if strings.HasPrefix(target, "v") && len(strings.Split(target, ".")) >= 2 {
+				sourceType = "tag"
+			}
+			internal.OutputResult("pull", internal.PullResult{
+				ImageRef:   localRef,
+				Source:     target,
+				SourceType: sourceType,
+			})
+		}
+		return
+	}
+
+	if onlyMetadata {
+		metadata, err := pullMetadataWithConfig(appName, target, globalFlags)
+		if err != nil {
+			internal.OutputError("pull", err)
+			os.Exit(1)
+		}
+
+		// JSON output
+		if globalFlags.JSON {
+			internal.OutputResult("pull", metadata)
+			return
+		}
+
+		// Text output
+		metadataJSON, err := metadata.ToJSON()
+		if err != nil {
+			internal.OutputError("pull", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(metadataJSON))
+		return
+	}
+
+	// Determine if target is a version tag (starts with 'v') or environment
+	var result *internal.PullResult
+	var err error
+	if strings.HasPrefix(target, "v") && len(strings.Split(target, ".")) >= 2 {
+		// It's a version tag like v1.2.0
+		result, err = pullTagWithConfig(appName, target, globalFlags, stripTags, keepDownload, localRefPrefix, platform)
+		if err != nil {
+			internal.OutputError("pull", err)
+			os.Exit(1)
+		}
+	} else {
+		// It's an environment like production, staging
+		result, err = pullImageWithConfig(appName, target, globalFlags, stripTags, keepDownload, localRefPrefix, platform)
+		if err != nil {
+			internal.OutputError("pull", err)
+			os.Exit(1)
+		}
+	}
+
+	// JSON output
+	if globalFlags.JSON {
+		internal.OutputResult("pull", result)
+		return
+	}
+
+	// Text output is handled by logs in the puller
+}
+
+func pullImageWithConfig(appName, environment string, globalFlags *GlobalFlags, stripTags, keepDownload bool, localRefPrefix, platform string) (*internal.PullResult, error) {
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	// Set environment variables for AWS configuration
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	dockerClient, err := internal.NewDockerClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+
+	puller := internal.NewImagePuller(dockerClient, s3Client, config.Bucket)
+	puller.SetStripTags(stripTags)
+	puller.SetKeepDownload(keepDownload)
+	puller.SetLocalRefPrefix(localRefPrefix)
+	puller.SetPlatform(platform)
+
+	return puller.Pull(ctx, appName, environment)
+}
+
+func pullTagWithConfig(appName, version string, globalFlags *GlobalFlags, stripTags, keepDownload bool, localRefPrefix, platform string) (*internal.PullResult, error) {
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	// Set environment variables for AWS configuration
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	dockerClient, err := internal.NewDockerClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
 
 	puller := internal.NewImagePuller(dockerClient, s3Client, config.Bucket)
+	puller.SetStripTags(stripTags)
+	puller.SetKeepDownload(keepDownload)
+	puller.SetLocalRefPrefix(localRefPrefix)
+	puller.SetPlatform(platform)
 
 	return puller.PullFromTag(ctx, appName, version)
 }
 
-func handleCurrentCommand(globalFlags *GlobalFlags, args []string) {
-	if len(args) < 2 {
-		internal.OutputError("current", fmt.Errorf("Current command requires app name and environment"))
+func pullMetadataWithConfig(appName, target string, globalFlags *GlobalFlags) (*internal.ImageMetadata, error) {
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	// Metadata-only pulls never touch Docker
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	currentService := internal.NewCurrentService(s3Client, config.Bucket)
+
+	return currentService.GetImageMetadata(ctx, appName, target)
+}
+
+func handleCleanupCommand(globalFlags *GlobalFlags, args []string) {
+	if len(args) < 1 || strings.HasPrefix(args[0], "--") {
+		internal.LogError("Cleanup command requires app name")
 		fmt.Fprintf(os.Stderr, "Usage:\n")
-		fmt.Fprintf(os.Stderr, "  %s current <app> <environment>    # Show current image for environment (e.g., production, staging)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s cleanup <app> [--older-than <duration>] [--keep-promoted] [--dry-run]    # Delete images older than duration (default 720h)\n", os.Args[0])
 		os.Exit(1)
 	}
 
 	appName := args[0]
-	environment := args[1]
+	olderThan := 30 * 24 * time.Hour
+	keepPromoted := false
+	dryRun := globalFlags.DryRun
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--older-than":
+			if i+1 < len(args) {
+				parsed, err := time.ParseDuration(args[i+1])
+				if err != nil {
+					internal.OutputError("cleanup", fmt.Errorf("invalid --older-than duration: %w", err))
+					os.Exit(1)
+				}
+				olderThan = parsed
+				i++
+			}
+		case "--keep-promoted":
+			keepPromoted = true
+		case "--dry-run":
+			dryRun = true
+		default:
+			internal.LogError("Unknown cleanup flag: %s", args[i])
+			os.Exit(1)
+		}
+	}
+
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		internal.OutputError("cleanup", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("cleanup", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
+	}
+
+	cleaner := internal.NewImageCleaner(s3Client, config.Bucket)
+	result, err := cleaner.Cleanup(ctx, appName, olderThan, keepPromoted, dryRun)
+	if err != nil {
+		internal.OutputError("cleanup", err)
+		os.Exit(1)
+	}
+
+	if globalFlags.JSON {
+		internal.OutputResult("cleanup", result)
+		return
+	}
+
+	if dryRun {
+		fmt.Printf("Would delete %d images, %d protected\n", len(result.Deleted), result.Protected)
+	} else {
+		fmt.Printf("Deleted %d images, %d protected\n", len(result.Deleted), result.Protected)
+	}
+}
+
+func handleAuditCommand(globalFlags *GlobalFlags, args []string) {
+	if len(args) < 2 || (args[0] != "summary" && args[0] != "verify" && args[0] != "replay") || strings.HasPrefix(args[1], "--") {
+		internal.LogError("Audit command requires a subcommand and app name")
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  %s audit summary <app> [--since <duration>]    # Aggregate audit events over a window (default 720h)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s audit verify <app>                          # Validate the audit event hash chain\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s audit replay <app> <environment>            # Rebuild a lost environment pointer from audit history\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	if args[0] == "verify" {
+		handleAuditVerifyCommand(globalFlags, args[1])
+		return
+	}
+
+	if args[0] == "replay" {
+		if len(args) < 3 {
+			internal.OutputError("audit", fmt.Errorf("audit replay requires app name and environment"))
+			os.Exit(1)
+		}
+		handleAuditReplayCommand(globalFlags, args[1], args[2])
+		return
+	}
+
+	appName := args[1]
+	window := 30 * 24 * time.Hour
+
+	for i := 2; i < len(args); i++ {
+		switch args[i] {
+		case "--since":
+			if i+1 < len(args) {
+				parsed, err := time.ParseDuration(args[i+1])
+				if err != nil {
+					internal.OutputError("audit", fmt.Errorf("invalid --since duration: %w", err))
+					os.Exit(1)
+				}
+				window = parsed
+				i++
+			}
+		default:
+			internal.LogError("Unknown audit flag: %s", args[i])
+			os.Exit(1)
+		}
+	}
+
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		internal.OutputError("audit", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("audit", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
+	}
+
+	historyService := internal.NewHistoryService(s3Client, config.Bucket)
+	result, err := historyService.Summary(ctx, appName, time.Now().Add(-window))
+	if err != nil {
+		internal.OutputError("audit", err)
+		os.Exit(1)
+	}
+
+	if globalFlags.JSON {
+		internal.OutputResult("audit", result)
+		return
+	}
+
+	fmt.Printf("Audit summary for %s since %s: %d events\n", result.AppName, result.Since, result.Events)
+	for eventType, count := range result.EventCounts {
+		fmt.Printf("  %s: %d\n", eventType, count)
+	}
+	if result.Pushes > 0 {
+		fmt.Printf("Push skip rate: %.0f%% (%d of %d)\n", result.PushSkipRate*100, result.PushesSkipped, result.Pushes)
+	}
+	for _, user := range result.TopUsers {
+		fmt.Printf("  %s: %d events\n", user.User, user.Events)
+	}
+	for env, count := range result.PromotionsByEnv {
+		fmt.Printf("  promoted to %s: %d\n", env, count)
+	}
+}
+
+func handleAuditVerifyCommand(globalFlags *GlobalFlags, appName string) {
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		internal.OutputError("audit", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("audit", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
+	}
+
+	historyService := internal.NewHistoryService(s3Client, config.Bucket)
+	result, err := historyService.VerifyChain(ctx, appName)
+	if err != nil {
+		internal.OutputError("audit", err)
+		os.Exit(1)
+	}
+
+	if globalFlags.JSON {
+		internal.OutputResult("audit", result)
+	} else if result.Intact {
+		fmt.Printf("Audit chain for %s intact: %d events (%d chained, %d unchained)\n",
+			result.AppName, result.Events, result.Chained, result.Unchained)
+	} else {
+		fmt.Printf("Audit chain for %s BROKEN at %s: %s\n", result.AppName, result.BrokenAt, result.Detail)
+	}
+
+	if !result.Intact {
+		os.Exit(1)
+	}
+}
+
+func handleAuditReplayCommand(globalFlags *GlobalFlags, appName, environment string) {
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		internal.OutputError("audit", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("audit", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
+	}
+
+	historyService := internal.NewHistoryService(s3Client, config.Bucket)
+	historyService.SetPathTemplate(config.PathTemplate)
+	result, err := historyService.Replay(ctx, appName, environment)
+	if err != nil {
+		internal.OutputError("audit", err)
+		os.Exit(1)
+	}
+
+	if globalFlags.JSON {
+		internal.OutputResult("audit", result)
+		return
+	}
+
+	if result.Skipped {
+		fmt.Printf("Pointer for %s/%s already matches audit history (%s)\n", result.AppName, result.Environment, result.ImageRef)
+		return
+	}
+	fmt.Printf("Rebuilt pointer for %s/%s from promotion recorded at %s: %s\n", result.AppName, result.Environment, result.EventTimestamp, result.ImageRef)
+}
+func handlePruneLocalCommand(globalFlags *GlobalFlags, args []string) {
+	keepLast := 0
+	dryRun := globalFlags.DryRun
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--keep-last":
+			if i+1 < len(args) {
+				keep := 0
+				if _, err := fmt.Sscanf(args[i+1], "%d", &keep); err != nil || keep < 0 {
+					internal.OutputError("prune-local", fmt.Errorf("invalid --keep-last value: %s (expected a non-negative number)", args[i+1]))
+					os.Exit(1)
+				}
+				keepLast = keep
+				i++
+			}
+		case "--dry-run":
+			dryRun = true
+		default:
+			internal.LogError("Unknown prune-local flag: %s", args[i])
+			fmt.Fprintf(os.Stderr, "Usage:\n")
+			fmt.Fprintf(os.Stderr, "  %s prune-local [--keep-last N] [--dry-run]    # Remove unreferenced local s3dock images\n", os.Args[0])
+			os.Exit(1)
+		}
+	}
+
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		internal.OutputError("prune-local", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("prune-local", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
+	}
+
+	dockerClient, err := internal.NewDockerClient()
+	if err != nil {
+		internal.OutputError("prune-local", fmt.Errorf("failed to create Docker client: %w", err))
+		os.Exit(1)
+	}
+
+	pruner := internal.NewLocalPruner(dockerClient, s3Client, config.Bucket)
+	result, err := pruner.PruneLocal(ctx, keepLast, dryRun)
+	if err != nil {
+		internal.OutputError("prune-local", err)
+		os.Exit(1)
+	}
+
+	if globalFlags.JSON {
+		internal.OutputResult("prune-local", result)
+		return
+	}
+
+	if dryRun {
+		fmt.Printf("Would remove %d local images, keep %d\n", len(result.Removed), len(result.Kept))
+	} else {
+		fmt.Printf("Removed %d local images, kept %d\n", len(result.Removed), len(result.Kept))
+	}
+}
+
+func handleVerifyAllCommand(globalFlags *GlobalFlags, args []string) {
+	appName := ""
+	concurrency := 0
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--app":
+			if i+1 < len(args) {
+				appName = args[i+1]
+				i++
+			}
+		case "--concurrency":
+			if i+1 < len(args) {
+				fmt.Sscanf(args[i+1], "%d", &concurrency)
+				i++
+			}
+		default:
+			internal.LogError("Unknown verify-all flag: %s", args[i])
+			fmt.Fprintf(os.Stderr, "Usage:\n")
+			fmt.Fprintf(os.Stderr, "  %s verify-all [--app <name>] [--concurrency N]    # Verify stored images against metadata checksums\n", os.Args[0])
+			os.Exit(1)
+		}
+	}
+
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		internal.OutputError("verify-all", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("verify-all", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
+	}
+
+	verifyService := internal.NewVerifyService(s3Client, config.Bucket)
+	if globalFlags.NDJSON {
+		verifyService.SetEmitter(func(item interface{}) {
+			internal.OutputStreamItem(item)
+		})
+	}
+
+	result, err := verifyService.VerifyAll(ctx, appName, concurrency)
+	if err != nil {
+		internal.OutputError("verify-all", err)
+		os.Exit(1)
+	}
+
+	if globalFlags.NDJSON {
+		// Elements were already streamed
+	} else if globalFlags.JSON {
+		internal.OutputResult("verify-all", result)
+	} else {
+		fmt.Printf("Checked %d images: %d ok, %d corrupt, %d missing metadata\n",
+			result.Checked, result.OK, len(result.Corrupt), len(result.MissingMetadata))
+		for _, failure := range result.Corrupt {
+			fmt.Printf("  CORRUPT %s: %s\n", failure.S3Path, failure.Detail)
+		}
+		for _, path := range result.MissingMetadata {
+			fmt.Printf("  MISSING METADATA %s\n", path)
+		}
+	}
+
+	if len(result.Corrupt) > 0 || len(result.MissingMetadata) > 0 {
+		os.Exit(1)
+	}
+}
+
+func handleCurrentCommand(globalFlags *GlobalFlags, args []string) {
+	fallback := false
+	exitCode := false
+	quiet := false
+	expect := ""
+	format := ""
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--fallback":
+			fallback = true
+		case "--exit-code":
+			exitCode = true
+		case "--quiet":
+			quiet = true
+		case "--expect":
+			if i+1 < len(args) {
+				expect = args[i+1]
+				i++
+			}
+		case "--format":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	if len(positional) < 2 {
+		internal.OutputError("current", fmt.Errorf("Current command requires app name and environment"))
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  %s current <app> <environment> [--fallback] [--quiet] [--format ref|s3path] [--exit-code --expect <ref>]    # Show current image for environment (e.g., production, staging); exits 3 if the environment isn't configured\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	appName := positional[0]
+	environment := positional[1]
+
+	if exitCode && expect == "" {
+		internal.OutputError("current", fmt.Errorf("--exit-code requires --expect <ref>"))
+		os.Exit(1)
+	}
+
+	switch format {
+	case "", "ref":
+		// Default: print the app:tag reference
+	case "s3path":
+		if fallback || exitCode || globalFlags.LocalRegistry != "" {
+			internal.OutputError("current", fmt.Errorf("--format s3path cannot be combined with --fallback, --exit-code, or --local-registry"))
+			os.Exit(1)
+		}
+	default:
+		internal.OutputError("current", fmt.Errorf("unknown --format value: %s (expected ref or s3path)", format))
+		os.Exit(1)
+	}
+
+	if globalFlags.LocalRegistry != "" {
+		if fallback {
+			internal.OutputError("current", fmt.Errorf("--fallback is not supported with --local-registry"))
+			os.Exit(1)
+		}
+
+		backend, dockerClient, err := registryBackendFromFlags(globalFlags)
+		if err != nil {
+			internal.OutputError("current", err)
+			os.Exit(1)
+		}
+		defer dockerClient.Close()
+
+		imageRef, err := backend.CurrentImage(context.Background(), appName, environment)
+		if err != nil {
+			internal.OutputError("current", err)
+			os.Exit(1)
+		}
+
+		match := false
+		if exitCode {
+			match = currentMatchesExpected(imageRef, expect)
+		}
+
+		if globalFlags.JSON {
+			result := internal.CurrentResult{
+				AppName:     appName,
+				Environment: environment,
+				ImageRef:    imageRef,
+			}
+			if exitCode {
+				result.Expected = expect
+				result.Match = &match
+			}
+			internal.OutputResult("current", result)
+		} else if !quiet {
+			fmt.Println(imageRef)
+		}
+
+		if exitCode && !match {
+			os.Exit(1)
+		}
+		return
+	}
+
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		internal.OutputError("current", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("current", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
+	}
+
+	currentService := internal.NewCurrentService(s3Client, config.Bucket)
+
+	if format == "s3path" {
+		s3Path, err := currentService.GetCurrentImagePath(ctx, appName, environment)
+		if err != nil {
+			internal.OutputError("current", err)
+			if errors.Is(err, internal.ErrEnvironmentNotFound) {
+				os.Exit(exitCodeEnvNotFound)
+			}
+			os.Exit(1)
+		}
+
+		if globalFlags.JSON {
+			internal.OutputResult("current", internal.CurrentResult{
+				AppName:     appName,
+				Environment: environment,
+				S3Path:      s3Path,
+			})
+		} else if !quiet {
+			fmt.Println(s3Path)
+		}
+		return
+	}
+
+	var imageRef, source string
+	if fallback {
+		imageRef, source, err = currentService.GetCurrentImageWithFallback(ctx, appName, environment, config.FallbackTag)
+	} else {
+		imageRef, err = currentService.GetCurrentImage(ctx, appName, environment)
+	}
+	if err != nil {
+		internal.OutputError("current", err)
+		if errors.Is(err, internal.ErrEnvironmentNotFound) {
+			os.Exit(exitCodeEnvNotFound)
+		}
+		os.Exit(1)
+	}
+
+	match := false
+	if exitCode {
+		match = currentMatchesExpected(imageRef, expect)
+	}
+
+	// JSON output
+	if globalFlags.JSON {
+		result := internal.CurrentResult{
+			AppName:     appName,
+			Environment: environment,
+			ImageRef:    imageRef,
+			Source:      source,
+		}
+		if exitCode {
+			result.Expected = expect
+			result.Match = &match
+		}
+		internal.OutputResult("current", result)
+		if exitCode && !match {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Text output; --quiet reports only via the exit status
+	if fallback && source != internal.CurrentSourceEnvironment && !quiet {
+		fmt.Fprintf(os.Stderr, "Resolved via fallback source: %s\n", source)
+	}
+	if !quiet {
+		fmt.Println(imageRef)
+	}
 
-	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
-	if err != nil {
-		internal.OutputError("current", err)
+	if exitCode && !match {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Current image does not match expected %s\n", expect)
+		}
 		os.Exit(1)
 	}
+}
 
-	ctx := context.Background()
-	setupAWSEnv(config)
+// currentMatchesExpected reports whether the resolved image matches the
+// --expect reference. The expected value may be a full image reference
+// (myapp:20250721-1430-abc1234) or just the tag part.
+func currentMatchesExpected(imageRef, expected string) bool {
+	if imageRef == expected {
+		return true
+	}
+	if idx := strings.Index(imageRef, ":"); idx >= 0 {
+		return imageRef[idx+1:] == expected
+	}
+	return false
+}
 
-	s3Client, err := internal.NewS3Client(ctx)
-	if err != nil {
-		internal.OutputError("current", fmt.Errorf("failed to create S3 client: %w", err))
-		os.Exit(1)
+func handleWhoamiCommand(globalFlags *GlobalFlags, args []string) {
+	checkAccess := false
+	actor := ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--check-access":
+			checkAccess = true
+		case "--actor":
+			if i+1 < len(args) {
+				actor = args[i+1]
+				i++
+			}
+		}
 	}
 
-	currentService := internal.NewCurrentService(s3Client, config.Bucket)
+	user, source := internal.ResolveIdentity(actor)
+	result := internal.WhoamiResult{User: user, Source: source}
 
-	imageRef, err := currentService.GetCurrentImage(ctx, appName, environment)
-	if err != nil {
-		internal.OutputError("current", err)
-		os.Exit(1)
+	var accessErr error
+	if checkAccess {
+		config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+		if err != nil {
+			internal.OutputError("whoami", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		setupAWSEnv(config)
+
+		s3Client, err := internal.NewBackendS3Client(ctx)
+		if err != nil {
+			internal.OutputError("whoami", fmt.Errorf("failed to create S3 client: %w", err))
+			os.Exit(1)
+		}
+
+		result.Bucket = config.Bucket
+		accessErr = internal.CheckBucketAccess(ctx, s3Client, config.Bucket)
+		writable := accessErr == nil
+		result.WriteAccess = &writable
 	}
 
 	// JSON output
 	if globalFlags.JSON {
-		result := internal.CurrentResult{
-			AppName:     appName,
-			Environment: environment,
-			ImageRef:    imageRef,
+		internal.OutputResult("whoami", result)
+		if accessErr != nil {
+			os.Exit(1)
 		}
-		internal.OutputResult("current", result)
 		return
 	}
 
 	// Text output
-	fmt.Println(imageRef)
+	fmt.Printf("%s (source: %s)\n", result.User, result.Source)
+	if checkAccess {
+		fmt.Printf("Bucket: %s\n", result.Bucket)
+		if accessErr != nil {
+			fmt.Printf("Write access: FAILED (%v)\n", accessErr)
+			os.Exit(1)
+		}
+		fmt.Println("Write access: ok")
+	}
 }
 
 func handleVersionCommand(globalFlags *GlobalFlags, args []string) {
@@ -888,6 +2676,7 @@ func handleListCommand(globalFlags *GlobalFlags, args []string) {
 		fmt.Println("")
 		fmt.Println("Subcommands:")
 		fmt.Println("  apps                    List all apps")
+		fmt.Println("  all <app>               Combined images, tags, and environments in one document")
 		fmt.Println("  images <app>            List all images for an app")
 		fmt.Println("  tags <app>              List all semantic version tags for an app")
 		fmt.Println("  envs <app>              List all environments for an app")
@@ -895,28 +2684,41 @@ func handleListCommand(globalFlags *GlobalFlags, args []string) {
 		fmt.Println("")
 		fmt.Println("Options:")
 		fmt.Println("  --month <YYYYMM>        Filter images by year-month (e.g., 202507)")
+		fmt.Println("  --resolve               Resolve env pointers to the currently-tagged image (list envs)")
+		fmt.Println("  --wide                  Tabular output with promoted-by/promoted-at columns (list envs)")
+		fmt.Println("  --include-archived      Include apps that only exist under archive/ (list apps)")
+		fmt.Println("  --format jsonl          One JSON object per line for piping (alias: json-lines)")
 		fmt.Println("")
 		fmt.Println("Examples:")
 		fmt.Println("  s3dock list apps")
+		fmt.Println("  s3dock list apps --with-latest")
 		fmt.Println("  s3dock list images myapp")
 		fmt.Println("  s3dock list images myapp --month 202507")
+		fmt.Println("  s3dock list images myapp --checksum")
+		fmt.Println("  s3dock list images myapp --group-by-month")
 		fmt.Println("  s3dock list tags myapp")
 		fmt.Println("  s3dock list envs myapp")
 		fmt.Println("  s3dock list tag-for myapp production")
 		return
 	}
 
-	subcommand := args[0]
+	subcommand := canonicalListSubcommand(args[0])
 	subArgs := args[1:]
 
+	// --format jsonl enables streaming output before dispatch, so each element
+	// is emitted as one JSON line (same as the global --output ndjson)
+	subArgs = extractListFormat(globalFlags, subArgs)
+
 	switch subcommand {
 	case "apps":
-		handleListApps(globalFlags)
+		handleListApps(globalFlags, subArgs)
+	case "all":
+		handleListAll(globalFlags, subArgs)
 	case "images":
 		handleListImages(globalFlags, subArgs)
 	case "tags":
 		handleListTags(globalFlags, subArgs)
-	case "envs", "environments":
+	case "envs":
 		handleListEnvironments(globalFlags, subArgs)
 	case "tag-for":
 		handleListTagFor(globalFlags, subArgs)
@@ -926,7 +2728,49 @@ func handleListCommand(globalFlags *GlobalFlags, args []string) {
 	}
 }
 
-func handleListApps(globalFlags *GlobalFlags) {
+// extractListFormat handles the list-level --format jsonl flag, which switches
+// to NDJSON streaming (one JSON object per line, unwrapped). Other --format
+// values (e.g. wide for list envs) are left for the subcommand to interpret.
+func extractListFormat(globalFlags *GlobalFlags, args []string) []string {
+	var remaining []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--format" && i+1 < len(args) {
+			switch args[i+1] {
+			case "jsonl", "json-lines", "ndjson":
+				globalFlags.NDJSON = true
+				internal.SetOutputFormat(internal.OutputFormatNDJSON)
+				internal.SetLogLevel(0) // Suppress all logs
+				i++
+				continue
+			}
+		}
+		remaining = append(remaining, args[i])
+	}
+	return remaining
+}
+
+func handleListApps(globalFlags *GlobalFlags, args []string) {
+	includeArchived := false
+	withLatest := false
+	appScope := ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--include-archived":
+			includeArchived = true
+		case "--with-latest":
+			withLatest = true
+		case "--app":
+			if i+1 < len(args) {
+				appScope = args[i+1]
+				i++
+			}
+		default:
+			internal.LogError("Unknown list apps flag: %s", args[i])
+			fmt.Fprintf(os.Stderr, "Usage: s3dock list apps [--include-archived] [--with-latest] [--app <name>]\n")
+			os.Exit(1)
+		}
+	}
+
 	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
 	if err != nil {
 		internal.OutputError("list apps", err)
@@ -936,22 +2780,44 @@ func handleListApps(globalFlags *GlobalFlags) {
 	ctx := context.Background()
 	setupAWSEnv(config)
 
-	s3Client, err := internal.NewS3Client(ctx)
+	s3Client, err := internal.NewBackendS3Client(ctx)
 	if err != nil {
 		internal.OutputError("list apps", fmt.Errorf("failed to create S3 client: %w", err))
 		os.Exit(1)
 	}
 
 	listService := internal.NewListService(s3Client, config.Bucket)
+	listService.SetPathTemplate(config.PathTemplate)
 
-	apps, err := listService.ListApps(ctx)
+	apps, err := listService.ListApps(ctx, includeArchived, appScope)
 	if err != nil {
 		internal.OutputError("list apps", err)
 		os.Exit(1)
 	}
 
+	var latest map[string]string
+	if withLatest {
+		latest = listService.LatestImages(ctx, apps)
+	}
+
+	// Streamed output: one JSON line per app
+	if globalFlags.NDJSON {
+		for _, app := range apps {
+			internal.OutputStreamItem(internal.AppItemJSON{App: app, Latest: latest[app]})
+		}
+		return
+	}
+
 	// JSON output
 	if globalFlags.JSON {
+		if withLatest {
+			items := make([]internal.AppItemJSON, len(apps))
+			for i, app := range apps {
+				items[i] = internal.AppItemJSON{App: app, Latest: latest[app]}
+			}
+			internal.OutputResult("list apps", internal.ListAppsWithLatestResult{Apps: items})
+			return
+		}
 		result := internal.ListAppsResult{Apps: apps}
 		internal.OutputResult("list apps", result)
 		return
@@ -964,25 +2830,123 @@ func handleListApps(globalFlags *GlobalFlags) {
 	}
 
 	for _, app := range apps {
+		if withLatest {
+			latestTag := latest[app]
+			if latestTag == "" {
+				latestTag = "-"
+			}
+			fmt.Printf("%s\t%s\n", app, latestTag)
+			continue
+		}
 		fmt.Println(app)
 	}
 }
 
+func handleListAll(globalFlags *GlobalFlags, args []string) {
+	if len(args) == 0 {
+		internal.OutputError("list all", fmt.Errorf("list all requires app name"))
+		fmt.Fprintf(os.Stderr, "Usage: s3dock list all <app>\n")
+		os.Exit(1)
+	}
+
+	appName := args[0]
+
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		internal.OutputError("list all", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("list all", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
+	}
+
+	listService := internal.NewListService(s3Client, config.Bucket)
+	listService.SetPathTemplate(config.PathTemplate)
+
+	images, tags, envs, err := listService.ListAll(ctx, appName)
+	if err != nil {
+		internal.OutputError("list all", err)
+		os.Exit(1)
+	}
+
+	// Streamed output: one JSON line per image, tag, and environment
+	if globalFlags.NDJSON {
+		for _, img := range images {
+			internal.OutputStreamItem(img.ToJSON())
+		}
+		for _, tag := range tags {
+			internal.OutputStreamItem(tag.ToJSON())
+		}
+		for _, env := range envs {
+			internal.OutputStreamItem(env.ToJSON())
+		}
+		return
+	}
+
+	if globalFlags.JSON {
+		result := internal.ListAllResult{
+			AppName:      appName,
+			Images:       make([]internal.ImageInfoJSON, len(images)),
+			Tags:         make([]internal.TagInfoJSON, len(tags)),
+			Environments: make([]internal.EnvInfoJSON, len(envs)),
+		}
+		for i, img := range images {
+			result.Images[i] = img.ToJSON()
+		}
+		for i, tag := range tags {
+			result.Tags[i] = tag.ToJSON()
+		}
+		for i, env := range envs {
+			result.Environments[i] = env.ToJSON()
+		}
+		internal.OutputResult("list all", result)
+		return
+	}
+
+	// Text output
+	fmt.Printf("Images (%d):\n", len(images))
+	for _, img := range images {
+		fmt.Printf("  %s:%s\n", img.AppName, img.Tag)
+	}
+	fmt.Printf("Tags (%d):\n", len(tags))
+	for _, tag := range tags {
+		fmt.Printf("  %s -> %s\n", tag.Version, tag.TargetImage)
+	}
+	fmt.Printf("Environments (%d):\n", len(envs))
+	for _, env := range envs {
+		fmt.Printf("  %s -> %s\n", env.Environment, env.SourceImage)
+	}
+}
+
 func handleListImages(globalFlags *GlobalFlags, args []string) {
 	if len(args) == 0 {
 		internal.OutputError("list images", fmt.Errorf("list images requires app name"))
-		fmt.Fprintf(os.Stderr, "Usage: s3dock list images <app> [--month YYYYMM]\n")
+		fmt.Fprintf(os.Stderr, "Usage: s3dock list images <app> [--month YYYYMM] [--checksum] [--group-by-month]\n")
 		os.Exit(1)
 	}
 
 	appName := args[0]
 	yearMonth := ""
+	withChecksum := false
+	groupByMonth := false
 
-	// Parse --month flag
 	for i := 1; i < len(args); i++ {
-		if args[i] == "--month" && i+1 < len(args) {
-			yearMonth = args[i+1]
-			i++
+		switch args[i] {
+		case "--month":
+			if i+1 < len(args) {
+				yearMonth = args[i+1]
+				i++
+			}
+		case "--checksum":
+			withChecksum = true
+		case "--group-by-month":
+			groupByMonth = true
 		}
 	}
 
@@ -995,13 +2959,21 @@ func handleListImages(globalFlags *GlobalFlags, args []string) {
 	ctx := context.Background()
 	setupAWSEnv(config)
 
-	s3Client, err := internal.NewS3Client(ctx)
+	s3Client, err := internal.NewBackendS3Client(ctx)
 	if err != nil {
 		internal.OutputError("list images", fmt.Errorf("failed to create S3 client: %w", err))
 		os.Exit(1)
 	}
 
 	listService := internal.NewListService(s3Client, config.Bucket)
+	listService.SetPathTemplate(config.PathTemplate)
+	// Checksums are filled in after listing, so streaming per-element would
+	// emit them empty - stream after enrichment instead
+	if globalFlags.NDJSON && !withChecksum {
+		listService.SetEmitter(func(item interface{}) {
+			internal.OutputStreamItem(item)
+		})
+	}
 
 	images, err := listService.ListImages(ctx, appName, yearMonth)
 	if err != nil {
@@ -1009,8 +2981,39 @@ func handleListImages(globalFlags *GlobalFlags, args []string) {
 		os.Exit(1)
 	}
 
+	if withChecksum {
+		listService.EnrichChecksums(ctx, images)
+	}
+
+	// Elements were already streamed
+	if globalFlags.NDJSON {
+		if withChecksum {
+			for _, img := range images {
+				internal.OutputStreamItem(img.ToJSON())
+			}
+		}
+		return
+	}
+
 	// JSON output
 	if globalFlags.JSON {
+		if groupByMonth {
+			_, groups := internal.GroupImagesByMonth(images)
+			jsonMonths := make(map[string][]internal.ImageInfoJSON, len(groups))
+			for month, monthImages := range groups {
+				jsonImages := make([]internal.ImageInfoJSON, len(monthImages))
+				for i, img := range monthImages {
+					jsonImages[i] = img.ToJSON()
+				}
+				jsonMonths[month] = jsonImages
+			}
+			result := internal.ListImagesByMonthResult{
+				AppName: appName,
+				Months:  jsonMonths,
+			}
+			internal.OutputResult("list images", result)
+			return
+		}
 		jsonImages := make([]internal.ImageInfoJSON, len(images))
 		for i, img := range images {
 			jsonImages[i] = img.ToJSON()
@@ -1030,7 +3033,26 @@ func handleListImages(globalFlags *GlobalFlags, args []string) {
 		return
 	}
 
+	if groupByMonth {
+		months, groups := internal.GroupImagesByMonth(images)
+		for _, month := range months {
+			fmt.Printf("%s (%d):\n", month, len(groups[month]))
+			for _, img := range groups[month] {
+				fmt.Printf("  %s:%s\n", img.AppName, img.Tag)
+			}
+		}
+		return
+	}
+
 	for _, img := range images {
+		if withChecksum {
+			checksum := img.Checksum
+			if checksum == "" {
+				checksum = "-"
+			}
+			fmt.Printf("%s:%s\t%s\n", img.AppName, img.Tag, checksum)
+			continue
+		}
 		fmt.Printf("%s:%s\n", img.AppName, img.Tag)
 	}
 }
@@ -1038,11 +3060,28 @@ func handleListImages(globalFlags *GlobalFlags, args []string) {
 func handleListTags(globalFlags *GlobalFlags, args []string) {
 	if len(args) == 0 {
 		internal.OutputError("list tags", fmt.Errorf("list tags requires app name"))
-		fmt.Fprintf(os.Stderr, "Usage: s3dock list tags <app>\n")
+		fmt.Fprintf(os.Stderr, "Usage: s3dock list tags <app> [--dangling] [--prune [--confirm]]\n")
 		os.Exit(1)
 	}
 
 	appName := args[0]
+	danglingOnly := false
+	prune := false
+	confirm := false
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--dangling":
+			danglingOnly = true
+		case "--prune":
+			prune = true
+		case "--confirm":
+			confirm = true
+		default:
+			internal.LogError("Unknown list tags flag: %s", args[i])
+			os.Exit(1)
+		}
+	}
 
 	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
 	if err != nil {
@@ -1053,13 +3092,23 @@ func handleListTags(globalFlags *GlobalFlags, args []string) {
 	ctx := context.Background()
 	setupAWSEnv(config)
 
-	s3Client, err := internal.NewS3Client(ctx)
+	s3Client, err := internal.NewBackendS3Client(ctx)
 	if err != nil {
 		internal.OutputError("list tags", fmt.Errorf("failed to create S3 client: %w", err))
 		os.Exit(1)
 	}
 
 	listService := internal.NewListService(s3Client, config.Bucket)
+	listService.SetPathTemplate(config.PathTemplate)
+
+	// Streaming only applies to plain listing; dangling detection and pruning
+	// post-process the full set
+	streaming := globalFlags.NDJSON && !danglingOnly && !prune
+	if streaming {
+		listService.SetEmitter(func(item interface{}) {
+			internal.OutputStreamItem(item)
+		})
+	}
 
 	tags, err := listService.ListTags(ctx, appName)
 	if err != nil {
@@ -1067,6 +3116,45 @@ func handleListTags(globalFlags *GlobalFlags, args []string) {
 		os.Exit(1)
 	}
 
+	// Elements were already streamed
+	if streaming {
+		return
+	}
+
+	if danglingOnly || prune {
+		if err := listService.MarkDanglingTags(ctx, tags); err != nil {
+			internal.OutputError("list tags", err)
+			os.Exit(1)
+		}
+		var dangling []internal.TagInfo
+		for _, tag := range tags {
+			if tag.Dangling {
+				dangling = append(dangling, tag)
+			}
+		}
+		tags = dangling
+	}
+
+	if prune {
+		if !confirm {
+			for _, tag := range tags {
+				fmt.Printf("Would delete dangling tag %s -> %s\n", tag.Version, tag.TargetImage)
+			}
+			fmt.Printf("Found %d dangling tags; re-run with --confirm to delete them\n", len(tags))
+			return
+		}
+
+		tagger := internal.NewImageTagger(s3Client, config.Bucket)
+		tagger.SetPathTemplate(config.PathTemplate)
+		deleted, err := tagger.PruneDanglingTags(ctx, appName, tags)
+		if err != nil {
+			internal.OutputError("list tags", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Deleted %d dangling tags\n", len(deleted))
+		return
+	}
+
 	// JSON output
 	if globalFlags.JSON {
 		jsonTags := make([]internal.TagInfoJSON, len(tags))
@@ -1095,12 +3183,38 @@ func handleListTags(globalFlags *GlobalFlags, args []string) {
 func handleListEnvironments(globalFlags *GlobalFlags, args []string) {
 	if len(args) == 0 {
 		internal.OutputError("list envs", fmt.Errorf("list envs requires app name"))
-		fmt.Fprintf(os.Stderr, "Usage: s3dock list envs <app>\n")
+		fmt.Fprintf(os.Stderr, "Usage: s3dock list envs <app> [--resolve] [--wide] [--graph [--format dot]]\n")
 		os.Exit(1)
 	}
 
 	appName := args[0]
 
+	resolve := false
+	wide := false
+	graph := false
+	dot := false
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--resolve":
+			resolve = true
+		case "--wide":
+			wide = true
+		case "--graph":
+			graph = true
+		case "--format":
+			if i+1 < len(args) {
+				switch args[i+1] {
+				case "wide":
+					wide = true
+					i++
+				case "dot":
+					dot = true
+					i++
+				}
+			}
+		}
+	}
+
 	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
 	if err != nil {
 		internal.OutputError("list envs", err)
@@ -1110,13 +3224,14 @@ func handleListEnvironments(globalFlags *GlobalFlags, args []string) {
 	ctx := context.Background()
 	setupAWSEnv(config)
 
-	s3Client, err := internal.NewS3Client(ctx)
+	s3Client, err := internal.NewBackendS3Client(ctx)
 	if err != nil {
 		internal.OutputError("list envs", fmt.Errorf("failed to create S3 client: %w", err))
 		os.Exit(1)
 	}
 
 	listService := internal.NewListService(s3Client, config.Bucket)
+	listService.SetPathTemplate(config.PathTemplate)
 
 	envs, err := listService.ListEnvironments(ctx, appName)
 	if err != nil {
@@ -1124,6 +3239,28 @@ func handleListEnvironments(globalFlags *GlobalFlags, args []string) {
 		os.Exit(1)
 	}
 
+	if resolve || wide || graph || dot {
+		listService.ResolveEnvironments(ctx, envs)
+	}
+
+	// Pointer chain visualization (env -> tag -> image)
+	if graph || dot {
+		if dot {
+			fmt.Print(internal.FormatEnvironmentsDOT(appName, envs))
+		} else {
+			fmt.Print(internal.FormatEnvironmentsGraph(appName, envs))
+		}
+		return
+	}
+
+	// Streamed output: one JSON line per environment
+	if globalFlags.NDJSON {
+		for _, env := range envs {
+			internal.OutputStreamItem(env.ToJSON())
+		}
+		return
+	}
+
 	// JSON output
 	if globalFlags.JSON {
 		jsonEnvs := make([]internal.EnvInfoJSON, len(envs))
@@ -1144,7 +3281,22 @@ func handleListEnvironments(globalFlags *GlobalFlags, args []string) {
 		return
 	}
 
+	if wide {
+		fmt.Print(internal.FormatEnvironmentsWide(envs))
+		return
+	}
+
 	for _, env := range envs {
+		if resolve && env.ResolvedImage != "" {
+			if env.Drifted {
+				fmt.Printf("%s -> %s (drifted, recorded %s)\n", env.Environment, env.ResolvedImage, env.SourceImage)
+			} else if env.TargetType == internal.TargetTypeTag && env.SourceTag != "" {
+				fmt.Printf("%s -> %s (via %s)\n", env.Environment, env.ResolvedImage, env.SourceTag)
+			} else {
+				fmt.Printf("%s -> %s\n", env.Environment, env.ResolvedImage)
+			}
+			continue
+		}
 		if env.TargetType == internal.TargetTypeTag && env.SourceTag != "" {
 			fmt.Printf("%s -> %s (via %s)\n", env.Environment, env.SourceImage, env.SourceTag)
 		} else {
@@ -1172,13 +3324,14 @@ func handleListTagFor(globalFlags *GlobalFlags, args []string) {
 	ctx := context.Background()
 	setupAWSEnv(config)
 
-	s3Client, err := internal.NewS3Client(ctx)
+	s3Client, err := internal.NewBackendS3Client(ctx)
 	if err != nil {
 		internal.OutputError("list tag-for", fmt.Errorf("failed to create S3 client: %w", err))
 		os.Exit(1)
 	}
 
 	listService := internal.NewListService(s3Client, config.Bucket)
+	listService.SetPathTemplate(config.PathTemplate)
 
 	tag, err := listService.GetTagForEnvironment(ctx, appName, environment)
 	if err != nil {
@@ -1207,8 +3360,110 @@ func handleListTagFor(globalFlags *GlobalFlags, args []string) {
 	fmt.Println(tag)
 }
 
+func handleFindCommand(globalFlags *GlobalFlags, args []string) {
+	appName := ""
+	imageID := ""
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--image-id" && i+1 < len(args) {
+			imageID = args[i+1]
+			i++
+		} else if appName == "" {
+			appName = args[i]
+		}
+	}
+
+	if appName == "" || imageID == "" {
+		internal.OutputError("find", fmt.Errorf("find requires app name and --image-id"))
+		fmt.Fprintf(os.Stderr, "Usage: s3dock find <app> --image-id <sha256:...>\n")
+		os.Exit(1)
+	}
+
+	config, err := internal.ResolveConfig(globalFlags.Config, globalFlags.Profile, globalFlags.Bucket)
+	if err != nil {
+		internal.OutputError("find", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	setupAWSEnv(config)
+
+	s3Client, err := internal.NewBackendS3Client(ctx)
+	if err != nil {
+		internal.OutputError("find", fmt.Errorf("failed to create S3 client: %w", err))
+		os.Exit(1)
+	}
+
+	listService := internal.NewListService(s3Client, config.Bucket)
+	listService.SetPathTemplate(config.PathTemplate)
+
+	matches, err := listService.FindByImageID(ctx, appName, imageID)
+	if err != nil {
+		internal.OutputError("find", err)
+		os.Exit(1)
+	}
+
+	// JSON output
+	if globalFlags.JSON {
+		jsonMatches := make([]internal.ImageInfoJSON, len(matches))
+		for i, img := range matches {
+			jsonMatches[i] = img.ToJSON()
+		}
+		result := internal.FindResult{
+			AppName: appName,
+			ImageID: imageID,
+			Matches: jsonMatches,
+		}
+		internal.OutputResult("find", result)
+		return
+	}
+
+	// Text output
+	if len(matches) == 0 {
+		fmt.Printf("No images found for image ID %s\n", imageID)
+		return
+	}
+
+	for _, img := range matches {
+		fmt.Printf("%s:%s\n", img.AppName, img.Tag)
+	}
+}
+
+// readRefFrom reads an image reference from r, taking the first non-empty
+// line and trimming whitespace. Used when "-" is passed as a reference
+// argument so build output can be piped into tag/promote.
+func readRefFrom(r io.Reader) (string, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			return line, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read reference from stdin: %w", err)
+	}
+	return "", fmt.Errorf("no image reference on stdin")
+}
+
+// resolveRefArg expands a "-" argument into a reference read from stdin.
+func resolveRefArg(arg string) (string, error) {
+	if arg != "-" {
+		return arg, nil
+	}
+	return readRefFrom(os.Stdin)
+}
+
 func setupAWSEnv(config *internal.ResolvedConfig) {
-	os.Setenv("AWS_REGION", config.Region)
+	region := config.Region
+	if os.Getenv("S3DOCK_REGION_PER_BUCKET") != "" && config.Bucket != "" {
+		if resolved, err := internal.ResolveBucketRegion(context.Background(), config.Bucket); err == nil {
+			region = resolved
+		} else {
+			internal.LogDebug("Bucket region probe failed, using configured region %s: %v", region, err)
+		}
+	}
+	os.Setenv("AWS_REGION", region)
 	if config.Endpoint != "" {
 		os.Setenv("AWS_ENDPOINT_URL", config.Endpoint)
 	}
@@ -1218,4 +3473,22 @@ func setupAWSEnv(config *internal.ResolvedConfig) {
 	if config.SecretKey != "" {
 		os.Setenv("AWS_SECRET_ACCESS_KEY", config.SecretKey)
 	}
+	if config.RoleArn != "" {
+		os.Setenv("S3DOCK_ROLE_ARN", config.RoleArn)
+	}
+	if config.ExternalID != "" {
+		os.Setenv("S3DOCK_EXTERNAL_ID", config.ExternalID)
+	}
+	if config.AuditChain {
+		os.Setenv("S3DOCK_AUDIT_CHAIN", "1")
+	}
+	if config.DisableServerSideCopy {
+		os.Setenv("S3DOCK_DISABLE_COPY", "1")
+	}
+	if config.DisableEndpointDetection {
+		os.Setenv("S3DOCK_DISABLE_ENDPOINT_DETECTION", "1")
+	}
+	if config.RateLimit > 0 {
+		os.Setenv("S3DOCK_RATE_LIMIT", fmt.Sprintf("%g", config.RateLimit))
+	}
 }
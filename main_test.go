@@ -0,0 +1,175 @@
+package main
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"s3dock/internal"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalCommand(t *testing.T) {
+	assert.Equal(t, "list", canonicalCommand("ls"))
+	assert.Equal(t, "list", canonicalCommand("list"))
+	assert.Equal(t, "push", canonicalCommand("push"))
+}
+
+func TestCanonicalListSubcommand(t *testing.T) {
+	assert.Equal(t, "images", canonicalListSubcommand("img"))
+	assert.Equal(t, "images", canonicalListSubcommand("images"))
+	assert.Equal(t, "envs", canonicalListSubcommand("env"))
+	assert.Equal(t, "envs", canonicalListSubcommand("environments"))
+	assert.Equal(t, "envs", canonicalListSubcommand("envs"))
+	assert.Equal(t, "apps", canonicalListSubcommand("apps"))
+}
+
+func TestExtractListFormat_JSONL(t *testing.T) {
+	defer internal.SetOutputFormat(internal.OutputFormatText)
+
+	flags := &GlobalFlags{}
+	remaining := extractListFormat(flags, []string{"myapp", "--format", "jsonl"})
+
+	assert.True(t, flags.NDJSON)
+	assert.Equal(t, []string{"myapp"}, remaining)
+	assert.Equal(t, internal.OutputFormatNDJSON, internal.GetOutputFormat())
+}
+
+func TestExtractListFormat_LeavesOtherValues(t *testing.T) {
+	defer internal.SetOutputFormat(internal.OutputFormatText)
+
+	flags := &GlobalFlags{}
+	remaining := extractListFormat(flags, []string{"myapp", "--format", "wide"})
+
+	assert.False(t, flags.NDJSON)
+	assert.Equal(t, []string{"myapp", "--format", "wide"}, remaining)
+}
+
+func TestWriteTagFile_Text(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tag.txt")
+
+	err := writeTagFile(path, "text", "myapp:20250721-1430-abc1234", "", "")
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:20250721-1430-abc1234\n", string(content))
+}
+
+func TestWriteTagFile_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tag.json")
+
+	err := writeTagFile(path, "json", "myapp:20250721-1430-abc1234", "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz", "d41d8cd9")
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var parsed map[string]string
+	assert.NoError(t, json.Unmarshal(content, &parsed))
+	assert.Equal(t, "myapp:20250721-1430-abc1234", parsed["image_ref"])
+	assert.Equal(t, "images/myapp/202507/myapp-20250721-1430-abc1234.tar.gz", parsed["s3_key"])
+	assert.Equal(t, "d41d8cd9", parsed["checksum"])
+}
+
+func TestWriteTagFile_InvalidFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tag.out")
+
+	err := writeTagFile(path, "yaml", "myapp:latest", "", "")
+	assert.Error(t, err)
+}
+
+func TestCurrentMatchesExpected_FullRef(t *testing.T) {
+	assert.True(t, currentMatchesExpected("myapp:20250721-1430-abc1234", "myapp:20250721-1430-abc1234"))
+}
+
+func TestCurrentMatchesExpected_TagOnly(t *testing.T) {
+	assert.True(t, currentMatchesExpected("myapp:20250721-1430-abc1234", "20250721-1430-abc1234"))
+}
+
+func TestCurrentMatchesExpected_Mismatch(t *testing.T) {
+	assert.False(t, currentMatchesExpected("myapp:20250721-1430-abc1234", "myapp:20250720-1045-def5678"))
+}
+
+func TestCurrentMatchesExpected_Empty(t *testing.T) {
+	assert.False(t, currentMatchesExpected("", "myapp:20250721-1430-abc1234"))
+}
+
+func TestReadRefFrom(t *testing.T) {
+	ref, err := readRefFrom(strings.NewReader("myapp:20250721-2118-f7a5a27\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:20250721-2118-f7a5a27", ref)
+}
+
+func TestReadRefFrom_SkipsBlankLines(t *testing.T) {
+	ref, err := readRefFrom(strings.NewReader("\n  \n  myapp:20250721-2118-f7a5a27  \nextra\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:20250721-2118-f7a5a27", ref)
+}
+
+func TestReadRefFrom_Empty(t *testing.T) {
+	_, err := readRefFrom(strings.NewReader(""))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no image reference")
+}
+
+func TestResolveRefArg_Passthrough(t *testing.T) {
+	ref, err := resolveRefArg("myapp:20250721-2118-f7a5a27")
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp:20250721-2118-f7a5a27", ref)
+}
+
+func TestPushImageWithConfig_ExportsRoleArn(t *testing.T) {
+	t.Setenv("S3DOCK_ROLE_ARN", "")
+	os.Unsetenv("S3DOCK_ROLE_ARN")
+
+	config := &internal.ResolvedConfig{
+		Bucket:   "test-bucket",
+		Region:   "us-east-1",
+		Endpoint: "http://127.0.0.1:1",
+		RoleArn:  "arn:aws:iam::123456789012:role/deploy",
+	}
+
+	// The push fails against the unroutable endpoint; what matters is that
+	// the config's role_arn is exported before the S3 client is built
+	pushImageWithConfig("myapp:test", config, &GlobalFlags{}, false, false, tar.FormatUnknown, "", "", nil, "", true, 0, false, false)
+
+	assert.Equal(t, "arn:aws:iam::123456789012:role/deploy", os.Getenv("S3DOCK_ROLE_ARN"))
+}
+
+func TestPushImageWithConfig_ExportsRateLimit(t *testing.T) {
+	t.Setenv("S3DOCK_RATE_LIMIT", "")
+	os.Unsetenv("S3DOCK_RATE_LIMIT")
+	defer internal.SetRateLimit(0)
+
+	config := &internal.ResolvedConfig{
+		Bucket:    "test-bucket",
+		Region:    "us-east-1",
+		Endpoint:  "http://127.0.0.1:1",
+		RateLimit: 5,
+	}
+
+	pushImageWithConfig("myapp:test", config, &GlobalFlags{}, false, false, tar.FormatUnknown, "", "", nil, "", true, 0, false, false)
+
+	assert.Equal(t, "5", os.Getenv("S3DOCK_RATE_LIMIT"))
+}
+
+func TestPromoteImageWithConfig_ExportsRoleArn(t *testing.T) {
+	t.Setenv("S3DOCK_ROLE_ARN", "")
+	os.Unsetenv("S3DOCK_ROLE_ARN")
+
+	config := &internal.ResolvedConfig{
+		Bucket:   "test-bucket",
+		Region:   "us-east-1",
+		Endpoint: "http://127.0.0.1:1",
+		RoleArn:  "arn:aws:iam::123456789012:role/deploy",
+	}
+
+	promoteImageWithConfig("myapp:20250721-1430-abc1234", "staging", config, &GlobalFlags{}, nil, "", 0, true, false)
+
+	assert.Equal(t, "arn:aws:iam::123456789012:role/deploy", os.Getenv("S3DOCK_ROLE_ARN"))
+}